@@ -20,11 +20,17 @@ package hash
 
 import (
 	"hash"
+	"sync"
 
 	bls377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/mimc"
 	bls381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/mimc"
 	bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
 	bw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
+
+	bls377poseidon "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/poseidon"
+	bls381poseidon "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon"
+	bn254poseidon "github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon"
+	bw761poseidon "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/poseidon"
 )
 
 type Hash uint
@@ -34,17 +40,55 @@ const (
 	MIMC_BLS12_381
 	MIMC_BLS12_377
 	MIMC_BW6_761
+	POSEIDON_BN254
+	POSEIDON_BLS12_381
+	POSEIDON_BLS12_377
+	POSEIDON_BW6_761
+)
+
+// poseidonWidth width of the Poseidon permutation used by the POSEIDON_* hash functions
+// (2-to-1 compression)
+const poseidonWidth = 3
+
+// parameters of the POSEIDON_* hash functions, generated once per curve by the Grain LFSR
+var (
+	bn254Poseidon struct {
+		once   sync.Once
+		params *bn254poseidon.Parameters
+		err    error
+	}
+	bls381Poseidon struct {
+		once   sync.Once
+		params *bls381poseidon.Parameters
+		err    error
+	}
+	bls377Poseidon struct {
+		once   sync.Once
+		params *bls377poseidon.Parameters
+		err    error
+	}
+	bw761Poseidon struct {
+		once   sync.Once
+		params *bw761poseidon.Parameters
+		err    error
+	}
 )
 
 // size of digests in bytes
 var digestSize = []uint8{
-	MIMC_BN254:     32,
-	MIMC_BLS12_381: 48,
-	MIMC_BLS12_377: 48,
-	MIMC_BW6_761:   96,
+	MIMC_BN254:         32,
+	MIMC_BLS12_381:     48,
+	MIMC_BLS12_377:     48,
+	MIMC_BW6_761:       96,
+	POSEIDON_BN254:     32,
+	POSEIDON_BLS12_381: 32,
+	POSEIDON_BLS12_377: 32,
+	POSEIDON_BW6_761:   48,
 }
 
-// New creates the corresponding mimc hash function.
+// New creates the corresponding hash function.
+// The seed is only used by mimc, Poseidon hash functions use the default
+// parameters of width 3, which are generated on the first call.
 func (m Hash) New(seed string) hash.Hash {
 	switch m {
 	case MIMC_BN254:
@@ -55,12 +99,40 @@ func (m Hash) New(seed string) hash.Hash {
 		return bls377.NewMiMC(seed)
 	case MIMC_BW6_761:
 		return bw761.NewMiMC(seed)
+	case POSEIDON_BN254:
+		p := &bn254Poseidon
+		p.once.Do(func() { p.params, p.err = bn254poseidon.DefaultParameters(poseidonWidth) })
+		if p.err != nil {
+			panic(p.err)
+		}
+		return bn254poseidon.NewPoseidon(p.params)
+	case POSEIDON_BLS12_381:
+		p := &bls381Poseidon
+		p.once.Do(func() { p.params, p.err = bls381poseidon.DefaultParameters(poseidonWidth) })
+		if p.err != nil {
+			panic(p.err)
+		}
+		return bls381poseidon.NewPoseidon(p.params)
+	case POSEIDON_BLS12_377:
+		p := &bls377Poseidon
+		p.once.Do(func() { p.params, p.err = bls377poseidon.DefaultParameters(poseidonWidth) })
+		if p.err != nil {
+			panic(p.err)
+		}
+		return bls377poseidon.NewPoseidon(p.params)
+	case POSEIDON_BW6_761:
+		p := &bw761Poseidon
+		p.once.Do(func() { p.params, p.err = bw761poseidon.DefaultParameters(poseidonWidth) })
+		if p.err != nil {
+			panic(p.err)
+		}
+		return bw761poseidon.NewPoseidon(p.params)
 	default:
-		panic("Unknown mimc ID")
+		panic("Unknown hash ID")
 	}
 }
 
-// String returns the mimc ID to string format.
+// String returns the hash ID to string format.
 func (m Hash) String() string {
 	switch m {
 	case MIMC_BN254:
@@ -71,8 +143,16 @@ func (m Hash) String() string {
 		return "MIMC_BLS377"
 	case MIMC_BW6_761:
 		return "MIMC_BW761"
+	case POSEIDON_BN254:
+		return "POSEIDON_BN254"
+	case POSEIDON_BLS12_381:
+		return "POSEIDON_BLS381"
+	case POSEIDON_BLS12_377:
+		return "POSEIDON_BLS377"
+	case POSEIDON_BW6_761:
+		return "POSEIDON_BW761"
 	default:
-		panic("Unknown mimc ID")
+		panic("Unknown hash ID")
 	}
 }
 
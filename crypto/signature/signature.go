@@ -78,13 +78,20 @@ type Signer interface {
 
 type SignatureScheme uint
 
-const maxSignatures = 4
+const maxSignatures = 11
 
 const (
 	EDDSA_BN254 SignatureScheme = iota
 	EDDSA_BLS12_381
 	EDDSA_BLS12_377
 	EDDSA_BW6_761
+	BLS_BLS12_381_MINPK_BASIC  // BLS, public keys in G1, basic scheme
+	BLS_BLS12_381_MINPK_AUG    // BLS, public keys in G1, message augmentation scheme
+	BLS_BLS12_381_MINPK_POP    // BLS, public keys in G1, proof of possession scheme
+	BLS_BLS12_381_MINSIG_BASIC // BLS, signatures in G1, basic scheme
+	BLS_BLS12_381_MINSIG_AUG   // BLS, signatures in G1, message augmentation scheme
+	BLS_BLS12_381_MINSIG_POP   // BLS, signatures in G1, proof of possession scheme
+	EDDSA_BLS12_381_BANDERSNATCH
 )
 
 var signatures = make([]func(io.Reader) (Signer, error), maxSignatures)
@@ -80,6 +80,24 @@ type E6 = fptower.E6
 // E12 is a degree two finite field extension of fp6
 type E12 = fptower.E12
 
+// BatchInvertE2 returns a new slice with every element of a inverted, using Montgomery's
+// batch inversion trick (a single E2 inversion). Zero elements are left unchanged.
+func BatchInvertE2(a []E2) []E2 {
+	return fptower.BatchInvertE2(a)
+}
+
+// BatchInvertE6 returns a new slice with every element of a inverted, using Montgomery's
+// batch inversion trick (a single E6 inversion). Zero elements are left unchanged.
+func BatchInvertE6(a []E6) []E6 {
+	return fptower.BatchInvertE6(a)
+}
+
+// BatchInvertE12 returns a new slice with every element of a inverted, using Montgomery's
+// batch inversion trick (a single E12 inversion). Zero elements are left unchanged.
+func BatchInvertE12(a []E12) []E12 {
+	return fptower.BatchInvertE12(a)
+}
+
 func init() {
 
 	bCurveCoeff.SetUint64(1)
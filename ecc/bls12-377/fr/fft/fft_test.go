@@ -17,10 +17,13 @@
 package fft
 
 import (
+	"context"
 	"math/big"
 	"strconv"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc"
+
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 
 	"github.com/leanovate/gopter"
@@ -144,6 +147,63 @@ func TestFFT(t *testing.T) {
 
 }
 
+func TestFFTContext(t *testing.T) {
+	const size = 1 << 10
+
+	domain := NewDomain(size, 1)
+
+	pol := make([]fr.Element, size)
+	for i := 0; i < size; i++ {
+		pol[i].SetRandom()
+	}
+
+	for _, decimation := range []Decimation{DIT, DIF} {
+		for _, coset := range []uint64{0, 1} {
+			expected := make([]fr.Element, size)
+			copy(expected, pol)
+			domain.FFT(expected, decimation, coset)
+			expectedInv := make([]fr.Element, size)
+			copy(expectedInv, pol)
+			domain.FFTInverse(expectedInv, decimation, coset)
+
+			for _, nbTasks := range []int{0, 1, 3} {
+				config := ecc.FFTConfig{NbTasks: nbTasks}
+
+				result := make([]fr.Element, size)
+				copy(result, pol)
+				if err := domain.FFTContext(context.Background(), result, decimation, coset, config); err != nil {
+					t.Fatal(err)
+				}
+				for i := 0; i < size; i++ {
+					if !result[i].Equal(&expected[i]) {
+						t.Fatalf("FFTContext with %d tasks should be consistent with FFT", nbTasks)
+					}
+				}
+
+				copy(result, pol)
+				if err := domain.FFTInverseContext(context.Background(), result, decimation, coset, config); err != nil {
+					t.Fatal(err)
+				}
+				for i := 0; i < size; i++ {
+					if !result[i].Equal(&expectedInv[i]) {
+						t.Fatalf("FFTInverseContext with %d tasks should be consistent with FFTInverse", nbTasks)
+					}
+				}
+			}
+		}
+	}
+
+	// a cancelled FFT returns ctx.Err()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := domain.FFTContext(ctx, pol, DIT, 0, ecc.FFTConfig{}); err != context.Canceled {
+		t.Fatal("a cancelled FFTContext should return context.Canceled")
+	}
+	if err := domain.FFTInverseContext(ctx, pol, DIF, 1, ecc.FFTConfig{}); err != context.Canceled {
+		t.Fatal("a cancelled FFTInverseContext should return context.Canceled")
+	}
+}
+
 // --------------------------------------------------------------------
 // benches
 func BenchmarkBitReverse(b *testing.B) {
@@ -0,0 +1,85 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pedersen
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+)
+
+// WriteTo writes binary encoding of the Key
+func (key *Key) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&key.H,
+		key.G,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes Key data from reader.
+func (key *Key) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&key.H,
+		&key.G,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of the Commitment
+func (c *Commitment) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+	err := enc.Encode((*bls12377.G1Affine)(c))
+	return enc.BytesWritten(), err
+}
+
+// ReadFrom decodes Commitment data from reader.
+func (c *Commitment) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+	err := dec.Decode((*bls12377.G1Affine)(c))
+	return dec.BytesRead(), err
+}
+
+// Bytes returns the compressed binary encoding of the Commitment
+func (c *Commitment) Bytes() []byte {
+	b := (*bls12377.G1Affine)(c).Bytes()
+	return b[:]
+}
+
+// SetBytes sets c from buf, as returned by Bytes.
+// It returns the number of bytes read from buf.
+func (c *Commitment) SetBytes(buf []byte) (int, error) {
+	return (*bls12377.G1Affine)(c).SetBytes(buf)
+}
@@ -0,0 +1,140 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package pedersen provides a Pedersen vector commitment scheme on G1.
+package pedersen
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+var (
+	ErrInvalidKeySize  = errors.New("invalid key size (== 0)")
+	ErrInvalidNbValues = errors.New("number of values is larger than the key size")
+	ErrVerifyOpening   = errors.New("can't verify pedersen opening")
+)
+
+// Key stores the generators of a Pedersen vector commitment
+//
+// The generators are obtained by hashing to G1, hence their discrete logarithms
+// relatively to each other are unknown.
+//
+// implements io.ReaderFrom and io.WriterTo
+type Key struct {
+	G []bls12377.G1Affine // [G_0, G_1, ... ], generators of the committed values
+	H bls12377.G1Affine   // generator of the blinding factor
+}
+
+// Commitment Pedersen commitment Σ values[i]*G_i + randomness*H to a vector of values.
+//
+// implements io.ReaderFrom and io.WriterTo
+type Commitment bls12377.G1Affine
+
+// NewKey returns a key to commit to vectors of at most size values
+//
+// G_i is HashToCurveG1Svdw(i, dst), with i encoded as a big endian uint64,
+// and H is HashToCurveG1Svdw("H", dst).
+func NewKey(size int, dst []byte) (*Key, error) {
+	if size <= 0 {
+		return nil, ErrInvalidKeySize
+	}
+	var key Key
+	var err error
+	key.H, err = bls12377.HashToCurveG1Svdw([]byte("H"), dst)
+	if err != nil {
+		return nil, err
+	}
+
+	key.G = make([]bls12377.G1Affine, size)
+	var msg [8]byte
+	for i := 0; i < size; i++ {
+		binary.BigEndian.PutUint64(msg[:], uint64(i))
+		if key.G[i], err = bls12377.HashToCurveG1Svdw(msg[:], dst); err != nil {
+			return nil, err
+		}
+	}
+
+	return &key, nil
+}
+
+// Commit returns the commitment Σ values[i]*G_i + randomness*H
+//
+// values may be shorter than the key, in which case the missing values are 0.
+func (key *Key) Commit(values []fr.Element, randomness fr.Element) (Commitment, error) {
+	if len(values) > len(key.G) {
+		return Commitment{}, ErrInvalidNbValues
+	}
+
+	// the MultiExp expects the scalars in regular form
+	points := make([]bls12377.G1Affine, len(values)+1)
+	scalars := make([]fr.Element, len(values)+1)
+	copy(points, key.G[:len(values)])
+	copy(scalars, values)
+	points[len(values)] = key.H
+	scalars[len(values)] = randomness
+	for i := 0; i < len(scalars); i++ {
+		scalars[i].FromMont()
+	}
+
+	var res bls12377.G1Affine
+	res.MultiExp(points, scalars)
+
+	return Commitment(res), nil
+}
+
+// Verify checks that commitment opens to values with the given randomness
+func (key *Key) Verify(commitment *Commitment, values []fr.Element, randomness fr.Element) error {
+	expected, err := key.Commit(values, randomness)
+	if err != nil {
+		return err
+	}
+	if !expected.Equal(commitment) {
+		return ErrVerifyOpening
+	}
+	return nil
+}
+
+// Add sets c to a + b and returns it
+//
+// c opens to the sum of the values and of the randomness of a and b.
+func (c *Commitment) Add(a, b *Commitment) *Commitment {
+	var _c, _b bls12377.G1Jac
+	_c.FromAffine((*bls12377.G1Affine)(a))
+	_b.FromAffine((*bls12377.G1Affine)(b))
+	_c.AddAssign(&_b)
+	(*bls12377.G1Affine)(c).FromJacobian(&_c)
+	return c
+}
+
+// ScalarMultiplication sets c to s*a and returns it
+//
+// c opens to the values and the randomness of a multiplied by s.
+func (c *Commitment) ScalarMultiplication(a *Commitment, s *fr.Element) *Commitment {
+	var bs big.Int
+	s.ToBigIntRegular(&bs)
+	(*bls12377.G1Affine)(c).ScalarMultiplication((*bls12377.G1Affine)(a), &bs)
+	return c
+}
+
+// Equal returns true if c and a are the same commitment
+func (c *Commitment) Equal(a *Commitment) bool {
+	return (*bls12377.G1Affine)(c).Equal((*bls12377.G1Affine)(a))
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func randomElements(n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func TestInterpolateAt(t *testing.T) {
+	// small inputs use the barycentric form, large ones the subproduct tree
+	for _, n := range []int{1, 2, 7, subproductTreeThreshold, 3*subproductTreeThreshold + 5} {
+		xs, ys := randomElements(n), randomElements(n)
+
+		p, err := InterpolateAt(xs, ys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p) != n {
+			t.Fatalf("n=%d: wrong degree", n)
+		}
+		for i := 0; i < n; i++ {
+			if y := p.Evaluate(xs[i]); !y.Equal(&ys[i]) {
+				t.Fatalf("n=%d: p(xs[%d]) != ys[%d]", n, i, i)
+			}
+		}
+	}
+}
+
+func TestInterpolateAtErrors(t *testing.T) {
+	for _, n := range []int{5, 2 * subproductTreeThreshold} {
+		xs, ys := randomElements(n), randomElements(n)
+		if _, err := InterpolateAt(xs, ys[1:]); err != ErrLengthMismatch {
+			t.Fatalf("n=%d: expected ErrLengthMismatch, got %v", n, err)
+		}
+		xs[n-1] = xs[1]
+		if _, err := InterpolateAt(xs, ys); err != ErrDuplicatePoints {
+			t.Fatalf("n=%d: expected ErrDuplicatePoints, got %v", n, err)
+		}
+	}
+}
+
+func TestEvaluateAt(t *testing.T) {
+	sizes := [][2]int{
+		{10, 5},
+		{3 * subproductTreeThreshold, 2*subproductTreeThreshold + 1},
+		{subproductTreeThreshold + 1, 5 * subproductTreeThreshold},
+	}
+	for _, size := range sizes {
+		p, xs := randomPolynomial(size[0]), randomElements(size[1])
+
+		ys := EvaluateAt(p, xs)
+		for i := 0; i < len(xs); i++ {
+			if y := p.Evaluate(xs[i]); !y.Equal(&ys[i]) {
+				t.Fatalf("len(p)=%d, len(xs)=%d: wrong evaluation %d", size[0], size[1], i)
+			}
+		}
+	}
+}
+
+func TestQuoRemFast(t *testing.T) {
+	a, b := randomPolynomial(5*fftMulThreshold), randomPolynomial(2*fftMulThreshold)
+
+	q, r := quoRem(a, b)
+	var expectedQ, expectedR Polynomial
+	expectedQ.QuoRem(a, b, &expectedR)
+	if !q.Equal(expectedQ) || !r.Equal(expectedR) {
+		t.Fatal("fast division and long division mismatch")
+	}
+}
+
+func BenchmarkInterpolateAt(b *testing.B) {
+	const n = 1 << 10
+	xs, ys := randomElements(n), randomElements(n)
+
+	b.Run("subproduct tree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = InterpolateAt(xs, ys)
+		}
+	})
+	b.Run("barycentric", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = interpolateBarycentric(xs, ys)
+		}
+	})
+}
+
+func BenchmarkEvaluateAt(b *testing.B) {
+	const n = 1 << 10
+	p, xs := randomPolynomial(n), randomElements(n)
+
+	b.Run("subproduct tree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = newSubproductTree(xs).evaluate(p)
+		}
+	})
+	b.Run("horner", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < len(xs); j++ {
+				_ = p.Evaluate(xs[j])
+			}
+		}
+	})
+}
@@ -18,6 +18,7 @@ package polynomial
 
 import (
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
 )
 
 // Polynomial polynomial represented by coefficients bls12-377 fr field.
@@ -41,3 +42,229 @@ func (p Polynomial) Eval(v interface{}) interface{} {
 	}
 	return &res
 }
+
+// Evaluate evaluates p at x, using Horner's method
+func (p Polynomial) Evaluate(x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &x)
+		res.Add(&res, &p[i])
+	}
+	return res
+}
+
+// Clone returns a copy of p
+func (p Polynomial) Clone() Polynomial {
+	res := make(Polynomial, len(p))
+	copy(res, p)
+	return res
+}
+
+// Equal returns true if p and q have the same coefficients, ignoring the leading zeros
+func (p Polynomial) Equal(q Polynomial) bool {
+	p, q = p.trim(), q.trim()
+	if len(p) != len(q) {
+		return false
+	}
+	for i := 0; i < len(p); i++ {
+		if !p[i].Equal(&q[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// trim returns p without its leading zero coefficients
+func (p Polynomial) trim() Polynomial {
+	n := len(p)
+	for n > 0 && p[n-1].IsZero() {
+		n--
+	}
+	return p[:n]
+}
+
+// resize returns p with length n, reusing its memory if possible
+func (p Polynomial) resize(n int) Polynomial {
+	if cap(p) < n {
+		return make(Polynomial, n)
+	}
+	return p[:n]
+}
+
+// Add sets p = p1 + p2 and returns p
+func (p *Polynomial) Add(p1, p2 Polynomial) *Polynomial {
+	if len(p1) < len(p2) {
+		p1, p2 = p2, p1
+	}
+	res := p.resize(len(p1))
+	for i := 0; i < len(p2); i++ {
+		res[i].Add(&p1[i], &p2[i])
+	}
+	for i := len(p2); i < len(p1); i++ {
+		res[i] = p1[i]
+	}
+	*p = res
+	return p
+}
+
+// Sub sets p = p1 - p2 and returns p
+func (p *Polynomial) Sub(p1, p2 Polynomial) *Polynomial {
+	n := len(p1)
+	if len(p2) > n {
+		n = len(p2)
+	}
+	res := p.resize(n)
+	for i := 0; i < n; i++ {
+		switch {
+		case i < len(p1) && i < len(p2):
+			res[i].Sub(&p1[i], &p2[i])
+		case i < len(p1):
+			res[i] = p1[i]
+		default:
+			res[i].Neg(&p2[i])
+		}
+	}
+	*p = res
+	return p
+}
+
+// ScaleBy sets p = c * p1 and returns p
+func (p *Polynomial) ScaleBy(p1 Polynomial, c fr.Element) *Polynomial {
+	res := p.resize(len(p1))
+	for i := 0; i < len(p1); i++ {
+		res[i].Mul(&p1[i], &c)
+	}
+	*p = res
+	return p
+}
+
+// Mul sets p = p1 * p2 and returns p, using the schoolbook method in O(len(p1)*len(p2)).
+// For large polynomials, see MulFFT.
+func (p *Polynomial) Mul(p1, p2 Polynomial) *Polynomial {
+	if len(p1) == 0 || len(p2) == 0 {
+		*p = (*p)[:0]
+		return p
+	}
+	res := make(Polynomial, len(p1)+len(p2)-1)
+	var tmp fr.Element
+	for i := 0; i < len(p1); i++ {
+		for j := 0; j < len(p2); j++ {
+			tmp.Mul(&p1[i], &p2[j])
+			res[i+j].Add(&res[i+j], &tmp)
+		}
+	}
+	*p = res
+	return p
+}
+
+// MulFFT sets p = p1 * p2 and returns p, by evaluating p1 and p2 on domain, multiplying
+// the evaluations and interpolating the result.
+// If domain is nil, a domain of cardinality at least len(p1)+len(p2)-1 is created;
+// otherwise, its cardinality must be at least len(p1)+len(p2)-1.
+func (p *Polynomial) MulFFT(p1, p2 Polynomial, domain *fft.Domain) *Polynomial {
+	if len(p1) == 0 || len(p2) == 0 {
+		*p = (*p)[:0]
+		return p
+	}
+	n := len(p1) + len(p2) - 1
+	if domain == nil {
+		domain = fft.NewDomain(uint64(n), 0)
+	} else if domain.Cardinality < uint64(n) {
+		panic("domain cardinality is smaller than the degree of the product")
+	}
+
+	a := make([]fr.Element, domain.Cardinality)
+	b := make([]fr.Element, domain.Cardinality)
+	copy(a, p1)
+	copy(b, p2)
+	domain.FFT(a, fft.DIF, 0)
+	domain.FFT(b, fft.DIF, 0)
+	for i := 0; i < len(a); i++ {
+		a[i].Mul(&a[i], &b[i])
+	}
+	domain.FFTInverse(a, fft.DIT, 0)
+
+	*p = a[:n]
+	return p
+}
+
+// DivideByLinear sets p to the quotient of p1 by (X - z), using synthetic division,
+// and returns the remainder, which is p1(z)
+func (p *Polynomial) DivideByLinear(p1 Polynomial, z fr.Element) fr.Element {
+	if len(p1) == 0 {
+		*p = (*p)[:0]
+		return fr.Element{}
+	}
+	res := make(Polynomial, len(p1)-1)
+	r := p1[len(p1)-1]
+	for i := len(p1) - 2; i >= 0; i-- {
+		res[i] = r
+		r.Mul(&r, &z).Add(&r, &p1[i])
+	}
+	*p = res
+	return r
+}
+
+// QuoRem sets p to the quotient of the long division of p1 by p2, r to its remainder,
+// and returns (p, r), such that p1 = p*p2 + r and deg(r) < deg(p2).
+// The remainder has len(p2)-1 coefficients, once the leading zeros of p2 are removed.
+// QuoRem panics if p2 is zero.
+func (p *Polynomial) QuoRem(p1, p2 Polynomial, r *Polynomial) (*Polynomial, *Polynomial) {
+	p2 = p2.trim()
+	if len(p2) == 0 {
+		panic("division by the zero polynomial")
+	}
+	rem := p1.Clone()
+	if len(p1) < len(p2) {
+		*p = (*p)[:0]
+		*r = rem
+		return p, r
+	}
+
+	var leadInv, tmp fr.Element
+	leadInv.Inverse(&p2[len(p2)-1])
+	d := len(p2) - 1
+	quo := make(Polynomial, len(p1)-d)
+	for i := len(quo) - 1; i >= 0; i-- {
+		quo[i].Mul(&rem[i+d], &leadInv)
+		for j := 0; j < len(p2); j++ {
+			tmp.Mul(&quo[i], &p2[j])
+			rem[i+j].Sub(&rem[i+j], &tmp)
+		}
+	}
+
+	*p = quo
+	*r = rem[:d]
+	return p, r
+}
+
+// Compose sets p = p1(p2(X)) and returns p
+func (p *Polynomial) Compose(p1, p2 Polynomial) *Polynomial {
+	var res Polynomial
+	for i := len(p1) - 1; i >= 0; i-- {
+		res.Mul(res, p2)
+		if len(res) == 0 {
+			res = append(res, p1[i])
+		} else {
+			res[0].Add(&res[0], &p1[i])
+		}
+	}
+	*p = res
+	return p
+}
+
+// Derivative sets p to the formal derivative of p1 and returns p
+func (p *Polynomial) Derivative(p1 Polynomial) *Polynomial {
+	if len(p1) == 0 {
+		*p = (*p)[:0]
+		return p
+	}
+	res := p.resize(len(p1) - 1)
+	var k fr.Element
+	for i := 1; i < len(p1); i++ {
+		k.SetUint64(uint64(i))
+		res[i-1].Mul(&p1[i], &k)
+	}
+	*p = res
+	return p
+}
@@ -0,0 +1,183 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func randomPolynomial(size int) Polynomial {
+	p := make(Polynomial, size)
+	for i := 0; i < size; i++ {
+		p[i].SetRandom()
+	}
+	return p
+}
+
+func TestPolynomialEvaluate(t *testing.T) {
+	p := randomPolynomial(17)
+	var x fr.Element
+	x.SetRandom()
+
+	expected := p.Eval(&x).(*fr.Element)
+	if y := p.Evaluate(x); !y.Equal(expected) {
+		t.Fatal("Evaluate and Eval mismatch")
+	}
+
+	var zero Polynomial
+	if y := zero.Evaluate(x); !y.IsZero() {
+		t.Fatal("the empty polynomial should evaluate to zero")
+	}
+}
+
+func TestPolynomialAddSub(t *testing.T) {
+	p1, p2 := randomPolynomial(12), randomPolynomial(7)
+	var x fr.Element
+	x.SetRandom()
+	y1, y2 := p1.Evaluate(x), p2.Evaluate(x)
+
+	var s, d Polynomial
+	s.Add(p1, p2)
+	d.Sub(p2, p1)
+	var expected fr.Element
+	if ys := s.Evaluate(x); !ys.Equal(expected.Add(&y1, &y2)) {
+		t.Fatal("p1+p2 evaluation mismatch")
+	}
+	if yd := d.Evaluate(x); !yd.Equal(expected.Sub(&y2, &y1)) {
+		t.Fatal("p2-p1 evaluation mismatch")
+	}
+
+	// (p1 + p2) - p2 = p1, in place
+	s.Sub(s, p2)
+	if !s.Equal(p1) {
+		t.Fatal("(p1+p2)-p2 != p1")
+	}
+
+	var c fr.Element
+	c.SetRandom()
+	s.ScaleBy(p1, c)
+	if ys := s.Evaluate(x); !ys.Equal(expected.Mul(&y1, &c)) {
+		t.Fatal("c*p1 evaluation mismatch")
+	}
+}
+
+func TestPolynomialMul(t *testing.T) {
+	p1, p2 := randomPolynomial(33), randomPolynomial(20)
+
+	var m, mFFT Polynomial
+	m.Mul(p1, p2)
+	mFFT.MulFFT(p1, p2, nil)
+	if len(m) != len(p1)+len(p2)-1 || !m.Equal(mFFT) {
+		t.Fatal("Mul and MulFFT mismatch")
+	}
+
+	var x fr.Element
+	x.SetRandom()
+	y1, y2 := p1.Evaluate(x), p2.Evaluate(x)
+	if y := m.Evaluate(x); !y.Equal(y1.Mul(&y1, &y2)) {
+		t.Fatal("p1*p2 evaluation mismatch")
+	}
+}
+
+func TestPolynomialDivision(t *testing.T) {
+	a, b := randomPolynomial(40), randomPolynomial(13)
+
+	var q, r, check Polynomial
+	q.QuoRem(a, b, &r)
+	if len(q) != len(a)-len(b)+1 || len(r) != len(b)-1 {
+		t.Fatal("wrong quotient or remainder size")
+	}
+	check.Mul(q, b).Add(check, r)
+	if !check.Equal(a) {
+		t.Fatal("a != q*b + r")
+	}
+
+	// leading zeros of the divisor are ignored
+	bz := append(b.Clone(), fr.Element{}, fr.Element{})
+	var q2, r2 Polynomial
+	q2.QuoRem(a, bz, &r2)
+	if !q2.Equal(q) || !r2.Equal(r) {
+		t.Fatal("leading zeros of the divisor should be ignored")
+	}
+
+	// division by X - z
+	var z fr.Element
+	z.SetRandom()
+	var ql Polynomial
+	rl := ql.DivideByLinear(a, z)
+	if y := a.Evaluate(z); !rl.Equal(&y) {
+		t.Fatal("the remainder of the division by X-z should be a(z)")
+	}
+	var minusZ fr.Element
+	minusZ.Neg(&z)
+	linear := Polynomial{minusZ, fr.One()}
+	q.QuoRem(a, linear, &r)
+	if !q.Equal(ql) || !r.Equal(Polynomial{rl}) {
+		t.Fatal("DivideByLinear and QuoRem mismatch")
+	}
+}
+
+func TestPolynomialCompose(t *testing.T) {
+	p1, p2 := randomPolynomial(6), randomPolynomial(5)
+
+	var c Polynomial
+	c.Compose(p1, p2)
+	if len(c) != (len(p1)-1)*(len(p2)-1)+1 {
+		t.Fatal("wrong composition size")
+	}
+	var x fr.Element
+	x.SetRandom()
+	if y, expected := c.Evaluate(x), p1.Evaluate(p2.Evaluate(x)); !y.Equal(&expected) {
+		t.Fatal("p1(p2(x)) evaluation mismatch")
+	}
+}
+
+func TestPolynomialDerivative(t *testing.T) {
+	// (X - z)^2 = X^2 - 2zX + z^2, whose derivative is 2X - 2z
+	var z, two fr.Element
+	z.SetRandom()
+	two.SetUint64(2)
+	var minusZ fr.Element
+	minusZ.Neg(&z)
+	linear := Polynomial{minusZ, fr.One()}
+	var square, d, expected Polynomial
+	square.Mul(linear, linear)
+	d.Derivative(square)
+	expected.ScaleBy(linear, two)
+	if !d.Equal(expected) {
+		t.Fatal("wrong derivative")
+	}
+}
+
+func BenchmarkPolynomialMul(b *testing.B) {
+	const size = 1 << 10
+	p1, p2 := randomPolynomial(size), randomPolynomial(size)
+	var res Polynomial
+
+	b.Run("schoolbook", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.Mul(p1, p2)
+		}
+	})
+	b.Run("fft", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.MulFFT(p1, p2, nil)
+		}
+	})
+}
@@ -77,6 +77,16 @@ func TestMapToCurveG1(t *testing.T) {
 		},
 		genFuzz1,
 	))
+
+	properties.Property("[G1] Svsw mapping should output point in the subgroup", prop.ForAll(
+		func(a fp.Element) bool {
+			g := MapToCurveG1Svdw(a)
+			return g.IsInSubGroup()
+		},
+		genFuzz1,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
 func TestG1AffineIsOnCurve(t *testing.T) {
@@ -337,6 +347,48 @@ func TestG1AffineOps(t *testing.T) {
 		genScalar,
 	))
 
+	properties.Property("[BLS12-377] constant time scalar multiplication (ladder) and double and add should output the same result", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var scalar, blindedScalar big.Int
+			var op1, op2, op3 G1Jac
+			s.ToBigIntRegular(&scalar)
+			blindedScalar.Add(&scalar, fr.Modulus())
+			op1.mulWindowed(&g1Gen, &scalar)
+			op2.ScalarMultiplicationSecret(&g1Gen, &scalar)
+			op3.ScalarMultiplicationSecret(&g1Gen, &blindedScalar)
+
+			var op1Aff, op2Aff G1Affine
+			op1Aff.FromJacobian(&op1)
+			op2Aff.ScalarMultiplicationSecret(&g1GenAff, &scalar)
+
+			return op1.Equal(&op2) && op1.Equal(&op3) && op1Aff.Equal(&op2Aff)
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS12-377] constant time scalar multiplication (ladder) of small scalars and multiples of r should be correct", prop.ForAll(
+		func() bool {
+			r := fr.Modulus()
+			var op G1Jac
+			var opAff G1Affine
+			var g2, gneg G1Jac
+			g2.Double(&g1Gen)
+			gneg.Neg(&g1Gen)
+
+			var rminusone big.Int
+			rminusone.SetUint64(1).Sub(r, &rminusone)
+
+			ok := op.ScalarMultiplicationSecret(&g1Gen, big.NewInt(0)).Z.IsZero()
+			ok = ok && op.ScalarMultiplicationSecret(&g1Gen, r).Z.IsZero()
+			ok = ok && op.ScalarMultiplicationSecret(&g1Gen, big.NewInt(1)).Equal(&g1Gen)
+			ok = ok && op.ScalarMultiplicationSecret(&g1Gen, big.NewInt(2)).Equal(&g2)
+			ok = ok && op.ScalarMultiplicationSecret(&g1Gen, &rminusone).Equal(&gneg)
+			ok = ok && opAff.ScalarMultiplicationSecret(&g1GenAff, r).IsInfinity()
+			return ok
+		},
+	))
+
 	properties.Property("[BLS12-377] scalar multiplication (GLV) should depend only on the scalar mod r", prop.ForAll(
 		func(s fr.Element) bool {
 
@@ -412,6 +464,92 @@ func TestG1AffineCofactorCleaning(t *testing.T) {
 
 }
 
+// isInSubGroupNaiveG1Jac returns true if [r]p is the infinity
+func isInSubGroupNaiveG1Jac(p *G1Jac) bool {
+	var res G1Jac
+	res.mulWindowed(p, fr.Modulus())
+	return p.IsOnCurve() && res.Z.IsZero()
+}
+
+// randomPointOnCurveG1 returns a random point of the curve, which is
+// (with overwhelming probability) not in the subgroup if the cofactor is not 1
+func randomPointOnCurveG1() G1Jac {
+	var a, x, b fp.Element
+	for {
+		a.SetRandom()
+		x.Square(&a).Mul(&x, &a).Add(&x, &bCurveCoeff)
+		if x.Legendre() == 1 {
+			break
+		}
+	}
+	b.Sqrt(&x)
+	var point G1Jac
+	point.X.Set(&a)
+	point.Y.Set(&b)
+	point.Z.SetOne()
+	return point
+}
+
+func TestG1AffineIsInSubGroup(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	properties.Property("[BLS12-377] IsInSubGroup should match the [r]p check on random points of the curve", prop.ForAll(
+		func() bool {
+			p := randomPointOnCurveG1()
+			var q G1Jac
+			q.Set(&p).AddAssign(&g1Gen)
+			return p.IsInSubGroup() == isInSubGroupNaiveG1Jac(&p) &&
+				q.IsInSubGroup() == isInSubGroupNaiveG1Jac(&q)
+		},
+	))
+
+	properties.Property("[BLS12-377] IsInSubGroup should match the [r]p check on points of the subgroup", prop.ForAll(
+		func(s fr.Element) bool {
+			var p G1Jac
+			var scalar big.Int
+			s.ToBigIntRegular(&scalar)
+			p.mulWindowed(&g1Gen, &scalar)
+			var q G1Jac
+			random := randomPointOnCurveG1()
+			q.ClearCofactor(&random)
+			return p.IsInSubGroup() && isInSubGroupNaiveG1Jac(&p) &&
+				q.IsInSubGroup() && isInSubGroupNaiveG1Jac(&q)
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS12-377] IsInSubGroup should accept the infinity", prop.ForAll(
+		func() bool {
+			var inf G1Affine
+			return g1Infinity.IsInSubGroup() && inf.IsInSubGroup()
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func BenchmarkG1AffineIsInSubGroup(b *testing.B) {
+	var p G1Jac
+	p.Double(&g1Gen)
+
+	b.Run("endomorphism", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.IsInSubGroup()
+		}
+	})
+	b.Run("[r]p", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			isInSubGroupNaiveG1Jac(&p)
+		}
+	})
+}
+
 func TestG1AffineBatchScalarMultiplication(t *testing.T) {
 
 	parameters := gopter.DefaultTestParameters()
@@ -514,6 +652,14 @@ func BenchmarkG1JacScalarMul(b *testing.B) {
 		}
 	})
 
+	var ladder G1Jac
+	b.Run("constant time ladder", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			ladder.ScalarMultiplicationSecret(&g1Gen, &scalar)
+		}
+	})
+
 }
 
 func BenchmarkG1AffineCofactorClearing(b *testing.B) {
@@ -220,3 +220,39 @@ func (z *E2) Sqrt(x *E2) *E2 {
 
 	return z
 }
+
+// BatchInvertE2 returns a new slice with every element inverted.
+// Uses Montgomery batch inversion trick: a single E2 inversion is performed.
+// Zero elements are left unchanged (their inverse is set to 0).
+func BatchInvertE2(a []E2) []E2 {
+	res := make([]E2, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E2
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i] = accumulator
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	var accInverse E2
+	accInverse.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accInverse)
+		accInverse.Mul(&accInverse, &a[i])
+	}
+
+	return res
+}
@@ -17,9 +17,17 @@
 package bls12377
 
 import (
+	"context"
+	"encoding/binary"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/internal/fptower"
 	"github.com/consensys/gnark-crypto/internal/parallel"
 	"math"
+	"math/big"
+	"math/bits"
 	"runtime"
 	"sync"
 )
@@ -44,6 +52,95 @@ func NewCPUSemaphore(numCpus int) *CPUSemaphore {
 	return toReturn
 }
 
+// msmScheduler is the scheduling state shared by the go routines of a multi-exponentiation
+type msmScheduler struct {
+	sem  *CPUSemaphore   // tokens limiting the number of go routines running at the same time
+	done <-chan struct{} // closed when the multi-exponentiation is cancelled (nil if it can't be)
+}
+
+// acquire waits for a token of the semaphore before scheduling a go routine.
+// It returns false, without a token, if the multi-exponentiation is cancelled.
+func (s *msmScheduler) acquire() bool {
+	if isCancelled(s.done) {
+		return false
+	}
+	select {
+	case <-s.sem.chCpus:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// release returns the token of a go routine to the semaphore
+func (s *msmScheduler) release() {
+	s.sem.chCpus <- struct{}{}
+}
+
+// isCancelled returns true if done is closed, without blocking
+func isCancelled(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// setRegular sets z to the regular form of k, in [0, 2^{64*fr.Limbs})
+func setRegular(z *fr.Element, k *big.Int) {
+	var buf [fr.Limbs * 8]byte
+	k.FillBytes(buf[:])
+	for j := 0; j < fr.Limbs; j++ {
+		z[j] = binary.BigEndian.Uint64(buf[(fr.Limbs-1-j)*8:])
+	}
+}
+
+// msmCost returns the approximate cost, in group operations, of a multi-exponentiation of nbPoints points
+// with c-bit windows, the partitioned scalars being of nbBits bits: each window adds the points into its buckets,
+// and the weighted sum of its 2^{c-1} buckets needs 2^c additions.
+func msmCost(nbPoints int, c, nbBits uint64) float64 {
+	cost := float64(nbBits/c) * float64(nbPoints+(1<<c))
+	if r := nbBits % c; r != 0 {
+		// smaller last window
+		cost += float64(nbPoints + (1 << r))
+	}
+	return cost
+}
+
+// bounds of the window size c of the multi-exponentiations whose buckets are allocated on the heap
+// (msmGLV, msmUint64)
+const (
+	msmMinC = 4
+	msmMaxC = 22
+)
+
+// msmBestCUint64 returns the window size c minimizing the approximate cost of a msmUint64 of nbPoints points,
+// the partitioned scalars being of nbBits bits.
+func msmBestCUint64(nbPoints int, nbBits uint64) uint64 {
+	C := uint64(msmMinC)
+	min := msmCost(nbPoints, C, nbBits)
+	for c := C + 1; c <= msmMaxC; c++ {
+		if cost := msmCost(nbPoints, c, nbBits); cost < min {
+			min = cost
+			C = c
+		}
+	}
+	return C
+}
+
+// msmMinPartSize is the minimum number of points of a part of a msmUint64 processed by its own go routines
+const msmMinPartSize = 1 << 10
+
+// msmGLVBits approximates the bit length of the partitioned scalars of msmGLV:
+// the scalars split with the GLV endomorphism are about half the size of fr.Modulus(), and msmGLV adds 2 bits
+// to the highest window
+const msmGLVBits = (fr.Bits+1)/2 + 2
+
+// msmCancelCheck is the number of points a go routine adds into its buckets
+// between two checks of the cancellation of the multi-exponentiation
+const msmCancelCheck = 1 << 10
+
 // selector stores the index, mask and shifts needed to select bits from a scalar
 // it is used during the multiExp algorithm or the batch scalar multiplication
 type selector struct {
@@ -61,7 +158,9 @@ type selector struct {
 // 2^{c} to the current digit, making it negative.
 // negative digits can be processed in a later step as adding -G into the bucket instead of G
 // (computing -G is cheap, and this saves us half of the buckets in the MultiExp or BatchScalarMul)
-func partitionScalars(scalars []fr.Element, c uint64) []fr.Element {
+//
+// the scalars are processed by nbTasks go routines
+func partitionScalars(scalars []fr.Element, c uint64, nbTasks int) []fr.Element {
 	toReturn := make([]fr.Element, len(scalars))
 
 	// number of c-bit radixes in a scalar
@@ -133,10 +232,87 @@ func partitionScalars(scalars []fr.Element, c uint64) []fr.Element {
 
 			}
 		}
-	})
+	}, nbTasks)
+	return toReturn
+}
+
+// partitionScalarsUint64 is partitionScalars for scalars of at most 64 bits: only the c-bit windows
+// of the nbBits lowest bits are computed, and the digits of the scalars 0 and 1 are left to 0.
+func partitionScalarsUint64(scalars []uint64, c, nbBits uint64, nbTasks int) []fr.Element {
+	toReturn := make([]fr.Element, len(scalars))
+
+	// number of c-bit radixes in the nbBits lowest bits
+	nbChunks := nbBits / c
+	if nbBits%c != 0 {
+		nbChunks++
+	}
+
+	mask := uint64((1 << c) - 1)      // low c bits are 1
+	msbWindow := uint64(1 << (c - 1)) // msb of the c-bit window
+
+	parallel.Execute(len(scalars), func(start, end int) {
+		for i := start; i < end; i++ {
+			if scalars[i] <= 1 {
+				continue
+			}
+			var carry uint64
+			for chunk := uint64(0); chunk < nbChunks; chunk++ {
+				jc := chunk * c
+				index, shift := jc/64, jc%64
+
+				// init with carry if any
+				digit := carry
+				carry = 0
+				if jc < 64 {
+					digit += (scalars[i] >> jc) & mask
+				}
+
+				// as in partitionScalars, if the digit is larger than 2^{c-1}, then, we borrow 2^c from the next window
+				// and substract 2^{c} to the current digit, making it negative (or 0, with the carry of a full window)
+				bits := digit
+				if digit >= msbWindow {
+					carry = 1
+					if digit == (1 << c) {
+						bits = 0
+					} else {
+						bits = ((1 << c) - digit - 1) | msbWindow
+					}
+				}
+
+				toReturn[i][index] |= bits << shift
+				if shift > 64-c && index < fr.Limbs-1 {
+					// the window is over 2 words
+					toReturn[i][index+1] |= bits >> (64 - shift)
+				}
+			}
+		}
+	}, nbTasks)
 	return toReturn
 }
 
+// batchOp is an addition of points[pointID] (or its opposite) into the bucket bucketID,
+// used by the batch affine bucket accumulation
+type batchOp struct {
+	bucketID uint32
+	pointID  uint32
+	isAdd    bool // false if -points[pointID] is added
+}
+
+// batchSize returns the number of additions performed with a single inversion
+// when accumulating the points in nbBuckets affine buckets.
+//
+// The larger the batch, the cheaper the inversion per addition, but the more
+// conflicts (additions into a bucket already in the batch) to queue.
+func batchSize(nbBuckets int) int {
+	return nbBuckets / 16
+}
+
+// minBatchAffineBuckets is the minimum number of buckets of a chunk to accumulate the points
+// in affine buckets: with less buckets, the batches are too small to amortize the inversion.
+// MultiExp uses the batch affine buckets when c >= 12, that is, above ~2^15 points.
+// this needs to be verified empirically on other hosts.
+const minBatchAffineBuckets = 1 << 11
+
 // MultiExp implements section 4 of https://eprint.iacr.org/2012/549.pdf
 // optionally, takes as parameter a CPUSemaphore struct
 // enabling to set max number of cpus to use
@@ -151,6 +327,177 @@ func (p *G1Affine) MultiExp(points []G1Affine, scalars []fr.Element, opts ...*CP
 // optionally, takes as parameter a CPUSemaphore struct
 // enabling to set max number of cpus to use
 func (p *G1Jac) MultiExp(points []G1Affine, scalars []fr.Element, opts ...*CPUSemaphore) *G1Jac {
+	var opt *CPUSemaphore
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewCPUSemaphore(runtime.NumCPU())
+	}
+
+	sched := &msmScheduler{sem: opt}
+	if msmUseGLVG1Affine(len(points)) {
+		C := msmBestCGLVG1Affine(len(points), 0)
+		return p.msmGLV(points, scalars, C, sched)
+	}
+	C := msmBestCG1Affine(len(points), 0)
+	return p.msm(points, scalars, C, sched)
+}
+
+// MultiExpContext implements section 4 of https://eprint.iacr.org/2012/549.pdf, as MultiExp does,
+// with at most config.NbTasks go routines, and at most config.MaxBucketMemory bytes of buckets allocated
+// at the same time (if the memory bound is lower than the buckets of a single c-bit window, a single
+// window of the smallest implemented c is processed at a time).
+//
+// As MultiExp, MultiExpContext splits the scalars with the GLV endomorphism (see msmGLV) for the numbers
+// of points where it is faster. If config.GLV is set, the scalars are split whatever the number of points.
+//
+// If ctx is done before the end of the computation, the go routines stop early and
+// MultiExpContext returns ctx.Err(), leaving p unchanged.
+func (p *G1Jac) MultiExpContext(ctx context.Context, points []G1Affine, scalars []fr.Element, config ecc.MultiExpConfig) (*G1Jac, error) {
+	if err := ctx.Err(); err != nil {
+		return p, err
+	}
+
+	nbTasks := config.NbTasks
+	if nbTasks <= 0 {
+		nbTasks = runtime.NumCPU()
+	}
+
+	C := msmBestCG1Affine(len(points), config.MaxBucketMemory)
+	glv := config.GLV || msmUseGLVG1Affine(len(points))
+	if glv {
+		C = msmBestCGLVG1Affine(len(points), config.MaxBucketMemory)
+	}
+	if config.MaxBucketMemory > 0 {
+		// each go routine allocates the buckets of a c-bit window
+		maxTasks := config.MaxBucketMemory / msmBucketMemoryG1Affine(C)
+		if maxTasks < 1 {
+			maxTasks = 1
+		}
+		if maxTasks < nbTasks {
+			nbTasks = maxTasks
+		}
+	}
+
+	var _p G1Jac
+	sched := &msmScheduler{sem: NewCPUSemaphore(nbTasks), done: ctx.Done()}
+	if glv {
+		_p.msmGLV(points, scalars, C, sched)
+	} else {
+		_p.msm(points, scalars, C, sched)
+	}
+	if err := ctx.Err(); err != nil {
+		return p, err
+	}
+	p.Set(&_p)
+	return p, nil
+}
+
+// MultiExpUint64 computes the MultiExp of points with small scalars, as MultiExp does with the
+// corresponding fr.Element scalars.
+// optionally, takes as parameter a CPUSemaphore struct
+// enabling to set max number of cpus to use
+func (p *G1Affine) MultiExpUint64(points []G1Affine, scalars []uint64, opts ...*CPUSemaphore) *G1Affine {
+	var _p G1Jac
+	_p.MultiExpUint64(points, scalars, opts...)
+	p.FromJacobian(&_p)
+	return p
+}
+
+// MultiExpUint64 computes the MultiExp of points with small scalars, as MultiExp does with the
+// corresponding fr.Element scalars (booleans, range checked bytes, ...).
+//
+// The points with a 0 scalar are skipped, the points with a scalar 1 are added directly, and
+// the other scalars are processed over as many c-bit windows as the bit length of the largest one needs.
+//
+// optionally, takes as parameter a CPUSemaphore struct
+// enabling to set max number of cpus to use
+func (p *G1Jac) MultiExpUint64(points []G1Affine, scalars []uint64, opts ...*CPUSemaphore) *G1Jac {
+	var opt *CPUSemaphore
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewCPUSemaphore(runtime.NumCPU())
+	}
+	return p.msmUint64(points, scalars, &msmScheduler{sem: opt})
+}
+
+// msmUint64 computes the MultiExpUint64, scheduling the go routines with sched.
+func (p *G1Jac) msmUint64(points []G1Affine, scalars []uint64, sched *msmScheduler) *G1Jac {
+	// take all the cpus to ourselves
+	sched.sem.lock.Lock()
+	nbTasks := cap(sched.sem.chCpus)
+
+	// sum the points with a scalar 1, and get the bit length of the larger scalars
+	var sum g1JacExtended
+	sum.setInfinity()
+	var acc uint64
+	nbLarge := 0
+	var lock sync.Mutex
+	parallel.Execute(len(scalars), func(start, end int) {
+		var partial g1JacExtended
+		partial.setInfinity()
+		var partialAcc uint64
+		partialNbLarge := 0
+		for i := start; i < end; i++ {
+			if scalars[i] == 1 {
+				partial.addMixed(&points[i])
+			} else if scalars[i] > 1 {
+				partialAcc |= scalars[i]
+				partialNbLarge++
+			}
+		}
+		lock.Lock()
+		sum.add(&partial)
+		acc |= partialAcc
+		nbLarge += partialNbLarge
+		lock.Unlock()
+	}, nbTasks)
+
+	if nbLarge == 0 {
+		sched.sem.lock.Unlock()
+		return p.fromJacExtended(&sum)
+	}
+
+	// as in msmGLV, the highest window has 2 bits more than the scalars: there is no carry out of it
+	nbBits := uint64(bits.Len64(acc) + 2)
+	c := msmBestCUint64(nbLarge, nbBits)
+
+	// a go routine processes a c-bit window: with fewer windows than tasks, the points are split in parts
+	// whose windows are processed concurrently
+	nbParts := nbTasks / int((nbBits+c-1)/c)
+	if maxParts := nbLarge / msmMinPartSize; nbParts > maxParts {
+		nbParts = maxParts
+	}
+	if nbParts > 1 {
+		c = msmBestCUint64(nbLarge/nbParts, nbBits)
+	} else {
+		nbParts = 1
+	}
+
+	// the digits of the scalars 0 and 1 are 0: the points are skipped
+	partitioned := partitionScalarsUint64(scalars, c, nbBits, nbTasks)
+
+	chParts := make([][]chan g1JacExtended, nbParts)
+	for i := 0; i < nbParts; i++ {
+		start, end := i*len(scalars)/nbParts, (i+1)*len(scalars)/nbParts
+		chParts[i] = msmScheduleChunksG1Affine(points[start:end], partitioned[start:end], c, nbBits, sched)
+	}
+
+	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
+	sched.sem.lock.Unlock()
+
+	var _sum G1Jac
+	p.fromJacExtended(&sum)
+	for i := 0; i < nbParts; i++ {
+		p.AddAssign(msmReduceChunkG1Affine(&_sum, int(c), chParts[i]))
+	}
+	return p
+}
+
+// msm computes the MultiExp with c-bit windows, scheduling the go routines with sched.
+// If the multi-exponentiation is cancelled, the go routines stop early and the result is undefined.
+func (p *G1Jac) msm(points []G1Affine, scalars []fr.Element, C uint64, sched *msmScheduler) *G1Jac {
 	// note:
 	// each of the msmCX method is the same, except for the c constant it declares
 	// duplicating (through template generation) these methods allows to declare the buckets on the stack
@@ -177,102 +524,213 @@ func (p *G1Jac) MultiExp(points []G1Affine, scalars []fr.Element, opts ...*CPUSe
 	// step 3
 	// reduce the buckets weigthed sums into our result (msmReduceChunk)
 
-	var opt *CPUSemaphore
-	if len(opts) > 0 {
-		opt = opts[0]
-	} else {
-		opt = NewCPUSemaphore(runtime.NumCPU())
-	}
-
-	var C uint64
-	nbPoints := len(points)
-
-	// implemented msmC methods (the c we use must be in this slice)
-	implementedCs := []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 20, 21}
-
-	// approximate cost (in group operations)
-	// cost = bits/c * (nbPoints + 2^{c})
-	// this needs to be verified empirically.
-	// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
-	min := math.MaxFloat64
-	for _, c := range implementedCs {
-		cc := fr.Limbs * 64 * (nbPoints + (1 << (c)))
-		cost := float64(cc) / float64(c)
-		if cost < min {
-			min = cost
-			C = c
-		}
-	}
-
-	// empirical, needs to be tuned.
-	// if C > 16 && nbPoints < 1 << 23 {
-	// 	C = 16
-	// }
-
 	// take all the cpus to ourselves
-	opt.lock.Lock()
+	sched.sem.lock.Lock()
 
 	// partition the scalars
 	// note: we do that before the actual chunk processing, as for each c-bit window (starting from LSW)
 	// if it's larger than 2^{c-1}, we have a carry we need to propagate up to the higher window
-	scalars = partitionScalars(scalars, C)
+	scalars = partitionScalars(scalars, C, cap(sched.sem.chCpus))
+
+	// for large MultiExp, accumulating the points in affine buckets by batches is faster
+	if (1 << (C - 1)) >= minBatchAffineBuckets {
+		return p.msmBatchAffine(points, scalars, C, fr.Limbs*64, sched)
+	}
 
 	switch C {
 
 	case 4:
-		return p.msmC4(points, scalars, opt)
+		return p.msmC4(points, scalars, sched)
 
 	case 5:
-		return p.msmC5(points, scalars, opt)
+		return p.msmC5(points, scalars, sched)
 
 	case 6:
-		return p.msmC6(points, scalars, opt)
+		return p.msmC6(points, scalars, sched)
 
 	case 7:
-		return p.msmC7(points, scalars, opt)
+		return p.msmC7(points, scalars, sched)
 
 	case 8:
-		return p.msmC8(points, scalars, opt)
+		return p.msmC8(points, scalars, sched)
 
 	case 9:
-		return p.msmC9(points, scalars, opt)
+		return p.msmC9(points, scalars, sched)
 
 	case 10:
-		return p.msmC10(points, scalars, opt)
+		return p.msmC10(points, scalars, sched)
 
 	case 11:
-		return p.msmC11(points, scalars, opt)
+		return p.msmC11(points, scalars, sched)
 
 	case 12:
-		return p.msmC12(points, scalars, opt)
+		return p.msmC12(points, scalars, sched)
 
 	case 13:
-		return p.msmC13(points, scalars, opt)
+		return p.msmC13(points, scalars, sched)
 
 	case 14:
-		return p.msmC14(points, scalars, opt)
+		return p.msmC14(points, scalars, sched)
 
 	case 15:
-		return p.msmC15(points, scalars, opt)
+		return p.msmC15(points, scalars, sched)
 
 	case 16:
-		return p.msmC16(points, scalars, opt)
+		return p.msmC16(points, scalars, sched)
 
 	case 20:
-		return p.msmC20(points, scalars, opt)
+		return p.msmC20(points, scalars, sched)
 
 	case 21:
-		return p.msmC21(points, scalars, opt)
+		return p.msmC21(points, scalars, sched)
 
 	case 22:
-		return p.msmC22(points, scalars, opt)
+		return p.msmC22(points, scalars, sched)
 
 	default:
 		panic("unimplemented")
 	}
 }
 
+// msmGLV computes the MultiExp with c-bit windows, after splitting the scalars with the GLV endomorphism
+// (see https://www.iacr.org/archive/crypto2001/21390189.pdf): s*P = k1*P + k2*phi(P), where k1 and k2
+// are about half the size of s. The multi-exponentiation of the 2*len(scalars) points ±P, ±phi(P)
+// has half as many c-bit windows.
+func (p *G1Jac) msmGLV(points []G1Affine, scalars []fr.Element, c uint64, sched *msmScheduler) *G1Jac {
+	// take all the cpus to ourselves
+	sched.sem.lock.Lock()
+	nbTasks := cap(sched.sem.chCpus)
+
+	// split the scalars, and set the points to ±P, ±phi(P) accordingly
+	n := len(scalars)
+	glvPoints := make([]G1Affine, 2*n)
+	glvScalars := make([]fr.Element, 2*n)
+	parallel.Execute(n, func(start, end int) {
+		splitter := ecc.NewScalarSplitter(&glvBasis)
+		var s, k1, k2 big.Int
+		for i := start; i < end; i++ {
+			// the scalars are in regular form
+			scalars[i].ToBigInt(&s)
+			splitter.Split(&s, &k1, &k2)
+
+			glvPoints[i] = points[i]
+			glvPoints[n+i].X.Mul(&points[i].X, &thirdRootOneG1)
+			glvPoints[n+i].Y = points[i].Y
+			if k1.Sign() == -1 {
+				k1.Neg(&k1)
+				glvPoints[i].Neg(&glvPoints[i])
+			}
+			if k2.Sign() == -1 {
+				k2.Neg(&k2)
+				glvPoints[n+i].Neg(&glvPoints[n+i])
+			}
+			setRegular(&glvScalars[i], &k1)
+			setRegular(&glvScalars[n+i], &k2)
+		}
+	}, nbTasks)
+
+	// the number of windows depends on the bit length of the largest half-size scalar
+	var acc [fr.Limbs]uint64
+	for i := 0; i < len(glvScalars); i++ {
+		for j := 0; j < fr.Limbs; j++ {
+			acc[j] |= glvScalars[i][j]
+		}
+	}
+	bitLen := 0
+	for j := fr.Limbs - 1; j >= 0; j-- {
+		if acc[j] != 0 {
+			bitLen = 64*j + bits.Len64(acc[j])
+			break
+		}
+	}
+
+	if isCancelled(sched.done) {
+		sched.sem.lock.Unlock()
+		return p
+	}
+
+	// the digits of the highest window are in [0, 2^{c-1}) if it has at least 2 bits more than the scalars:
+	// then, there is no carry out of it, and it needs less buckets
+	glvScalars = partitionScalars(glvScalars, c, nbTasks)
+	return p.msmBatchAffine(glvPoints, glvScalars, c, uint64(bitLen+2), sched)
+}
+
+// msmUseGLVG1Affine returns true if msmGLV is faster than msm for a MultiExp of nbPoints points.
+// The window is measured with BenchmarkMultiExpGLVG1 (single core): outside of it, msm is
+// as fast or faster (above it, the larger c-bit windows of msm, with affine buckets, make up for their number).
+func msmUseGLVG1Affine(nbPoints int) bool {
+	return nbPoints >= 2048 && nbPoints < 131072
+}
+
+// msmBestCGLVG1Affine returns the window size c minimizing the approximate cost of a msmGLV of nbPoints points.
+// If maxBucketMemory > 0, the buckets of a c-bit window must fit in maxBucketMemory bytes, or c is msmMinC.
+//
+// msmGLV processes 2*nbPoints points, and its c-bit windows are not bound to the implemented msmCX.
+func msmBestCGLVG1Affine(nbPoints int, maxBucketMemory int) uint64 {
+	C := uint64(msmMinC)
+	min := msmCost(2*nbPoints, C, msmGLVBits)
+	for c := C + 1; c <= msmMaxC; c++ {
+		if maxBucketMemory > 0 && msmBucketMemoryG1Affine(c) > maxBucketMemory {
+			break
+		}
+		if cost := msmCost(2*nbPoints, c, msmGLVBits); cost < min {
+			min = cost
+			C = c
+		}
+	}
+	return C
+}
+
+// msmBestCG1Affine returns the window size c minimizing the approximate cost of a MultiExp of nbPoints points.
+// If maxBucketMemory > 0, the buckets of a c-bit window must fit in maxBucketMemory bytes, or c is the smallest implemented c.
+func msmBestCG1Affine(nbPoints int, maxBucketMemory int) uint64 {
+	var C uint64
+
+	// implemented msmC methods (the c we use must be in this slice)
+	implementedCs := []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 20, 21}
+
+	// approximate cost (in group operations)
+	// cost = bits/c * (nbPoints + 2^{c})
+	// this needs to be verified empirically.
+	// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
+	min := math.MaxFloat64
+	for _, c := range implementedCs {
+		if maxBucketMemory > 0 && c != implementedCs[0] && msmBucketMemoryG1Affine(c) > maxBucketMemory {
+			continue
+		}
+		cc := fr.Limbs * 64 * (nbPoints + (1 << (c)))
+		cost := float64(cc) / float64(c)
+		if cost < min {
+			min = cost
+			C = c
+		}
+	}
+
+	// empirical, needs to be tuned.
+	// if C > 16 && nbPoints < 1 << 23 {
+	// 	C = 16
+	// }
+
+	return C
+}
+
+// msmBucketMemoryG1Affine returns the size in bytes of the buckets allocated to process a c-bit window of a MultiExp
+func msmBucketMemoryG1Affine(c uint64) int {
+	const sizeCoord = fp.Limbs * 8
+	nbBuckets := 1 << (c - 1)
+	if nbBuckets < minBatchAffineBuckets {
+		// g1JacExtended buckets
+		return nbBuckets * 4 * sizeCoord
+	}
+	// affine buckets and their inBatch flag, the batch, its scratch memory and the queue of conflicting additions
+	// (see msmProcessChunkG1AffineBatchAffine)
+	batch := batchSize(nbBuckets)
+	return nbBuckets*(2*sizeCoord+1) + batch*(4*sizeCoord+4+12)
+}
+
 // msmReduceChunkG1Affine reduces the weighted sum of the buckets into the result of the multiExp
+//
+// the channel of a chunk which is not processed (the multiExp is cancelled) is closed without a value
 func msmReduceChunkG1Affine(p *G1Jac, c int, chChunks []chan g1JacExtended) *G1Jac {
 	var _p g1JacExtended
 	totalj := <-chChunks[len(chChunks)-1]
@@ -293,7 +751,8 @@ func msmProcessChunkG1Affine(chunk uint64,
 	buckets []g1JacExtended,
 	c uint64,
 	points []G1Affine,
-	scalars []fr.Element) {
+	scalars []fr.Element,
+	done <-chan struct{}) {
 
 	mask := uint64((1 << c) - 1) // low c bits are 1
 	msbWindow := uint64(1 << (c - 1))
@@ -316,6 +775,11 @@ func msmProcessChunkG1Affine(chunk uint64,
 
 	// for each scalars, get the digit corresponding to the chunk we're processing.
 	for i := 0; i < len(scalars); i++ {
+		if i%msmCancelCheck == 0 && isCancelled(done) {
+			// the chunk is not processed
+			close(chRes)
+			return
+		}
 		bits := (scalars[i][s.index] & s.mask) >> s.shift
 		if s.multiWordSelect {
 			bits += (scalars[i][s.index+1] & s.maskHigh) << s.shiftHigh
@@ -342,9 +806,230 @@ func msmProcessChunkG1Affine(chunk uint64,
 	runningSum.setInfinity()
 	total.setInfinity()
 	for k := len(buckets) - 1; k >= 0; k-- {
-		if !buckets[k].ZZ.IsZero() {
-			runningSum.add(&buckets[k])
-		}
+		if !buckets[k].ZZ.IsZero() {
+			runningSum.add(&buckets[k])
+		}
+		total.add(&runningSum)
+	}
+
+	chRes <- total
+	close(chRes)
+}
+
+// msmBatchAffine is msmCX with the points accumulated in affine buckets by batches
+// (see msmProcessChunkG1AffineBatchAffine). scalars must be partitioned with the same c,
+// and only their nbBits lowest bits are processed: the partitioned scalars must be 0 above.
+//
+// As the buckets are allocated on the heap, c is not a constant.
+func (p *G1Jac) msmBatchAffine(points []G1Affine, scalars []fr.Element, c, nbBits uint64, sched *msmScheduler) *G1Jac {
+	chChunks := msmScheduleChunksG1Affine(points, scalars, c, nbBits, sched)
+
+	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
+	sched.sem.lock.Unlock()
+	return msmReduceChunkG1Affine(p, int(c), chChunks)
+}
+
+// msmScheduleChunksG1Affine spawns a go routine per c-bit window of the nbBits lowest bits of the
+// partitioned scalars, and returns the channels of their weighted bucket sums (see msmBatchAffine).
+// The buckets are in affine coordinates if there are enough of them.
+func msmScheduleChunksG1Affine(points []G1Affine, scalars []fr.Element, c, nbBits uint64, sched *msmScheduler) []chan g1JacExtended {
+	if nbBits > fr.Limbs*64 {
+		nbBits = fr.Limbs * 64
+	}
+	nbChunks := nbBits / c // number of c-bit radixes in a scalar
+	if nbBits%c != 0 {
+		nbChunks++
+	}
+
+	// for each chunk, spawn a go routine that'll loop through all the scalars
+	chChunks := make([]chan g1JacExtended, nbChunks)
+
+	// wait group to wait for all the go routines to start
+	var wg sync.WaitGroup
+	for chunk := int(nbChunks) - 1; chunk >= 0; chunk-- {
+		chChunks[chunk] = make(chan g1JacExtended, 1)
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended) {
+			wg.Done()
+			// if c doesn't divide nbBits, last window is smaller we can allocate less buckets
+			nbBuckets := 1 << (c - 1)
+			if lastC := nbBits - j*c; lastC < c {
+				nbBuckets = 1 << (lastC - 1)
+			}
+			if nbBuckets < minBatchAffineBuckets {
+				buckets := make([]g1JacExtended, nbBuckets)
+				msmProcessChunkG1Affine(j, chRes, buckets, c, points, scalars, sched.done)
+			} else {
+				msmProcessChunkG1AffineBatchAffine(j, chRes, nbBuckets, c, points, scalars, sched.done)
+			}
+			sched.release() // release token in the semaphore
+		}(uint64(chunk), chChunks[chunk])
+	}
+
+	// wait for all goRoutines to actually start
+	wg.Wait()
+
+	return chChunks
+}
+
+// msmProcessChunkG1AffineBatchAffine is msmProcessChunkG1Affine, with the buckets in affine coordinates
+//
+// The additions into the buckets are performed by batches, sharing a single field inversion
+// (see batchAddG1Affine). A bucket can't be updated twice in the same batch: such conflicting
+// additions are queued and processed in a later batch.
+func msmProcessChunkG1AffineBatchAffine(chunk uint64,
+	chRes chan<- g1JacExtended,
+	nbBuckets int,
+	c uint64,
+	points []G1Affine,
+	scalars []fr.Element,
+	done <-chan struct{}) {
+
+	mask := uint64((1 << c) - 1) // low c bits are 1
+	msbWindow := uint64(1 << (c - 1))
+
+	// the zero value of an affine point is the point at infinity
+	buckets := make([]G1Affine, nbBuckets)
+
+	jc := uint64(chunk * c)
+	s := selector{}
+	s.index = jc / 64
+	s.shift = jc - (s.index * 64)
+	s.mask = mask << s.shift
+	s.multiWordSelect = (64%c) != 0 && s.shift > (64-c) && s.index < (fr.Limbs-1)
+	if s.multiWordSelect {
+		nbBitsHigh := s.shift - uint64(64-c)
+		s.maskHigh = (1 << nbBitsHigh) - 1
+		s.shiftHigh = (c - nbBitsHigh)
+	}
+
+	// current batch: batchPoints[i] is to be added to buckets[batchBuckets[i]]
+	maxBatch := batchSize(nbBuckets)
+	batchBuckets := make([]uint32, 0, maxBatch)
+	batchPoints := make([]G1Affine, 0, maxBatch)
+	inBatch := make([]bool, nbBuckets)
+	var scratch batchScratchG1Affine
+	scratch.init(maxBatch)
+
+	// conflicting additions, waiting for their bucket to leave the batch
+	queue := make([]batchOp, 0, maxBatch)
+
+	// add adds ±points[op.pointID] into its bucket, or into the current batch.
+	// the bucket must not be in the current batch.
+	add := func(op batchOp) {
+		point := &points[op.pointID]
+		bucket := &buckets[op.bucketID]
+
+		// the special cases are handled directly
+		if bucket.IsInfinity() {
+			if op.isAdd {
+				bucket.Set(point)
+			} else {
+				bucket.Neg(point)
+			}
+			return
+		}
+		if bucket.X.Equal(&point.X) && (bucket.Y.Equal(&point.Y) != op.isAdd || bucket.Y.IsZero()) {
+			// bucket - bucket
+			bucket.X.SetZero()
+			bucket.Y.SetZero()
+			return
+		}
+
+		inBatch[op.bucketID] = true
+		batchBuckets = append(batchBuckets, op.bucketID)
+		batchPoints = append(batchPoints, *point)
+		if !op.isAdd {
+			batchPoints[len(batchPoints)-1].Y.Neg(&point.Y)
+		}
+	}
+
+	// executeBatch performs the additions of the current batch and empties it
+	executeBatch := func() {
+		batchAddG1Affine(buckets, batchBuckets, batchPoints, &scratch)
+		for _, bucketID := range batchBuckets {
+			inBatch[bucketID] = false
+		}
+		batchBuckets = batchBuckets[:0]
+		batchPoints = batchPoints[:0]
+	}
+
+	// processQueue moves the queued additions whose bucket is not in the batch anymore to the batch
+	processQueue := func() {
+		for i := len(queue) - 1; i >= 0; i-- {
+			if inBatch[queue[i].bucketID] {
+				continue
+			}
+			add(queue[i])
+			if len(batchBuckets) == maxBatch {
+				executeBatch()
+			}
+			queue[i] = queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+		}
+	}
+
+	// for each scalars, get the digit corresponding to the chunk we're processing.
+	for i := 0; i < len(scalars); i++ {
+		if i%msmCancelCheck == 0 && isCancelled(done) {
+			// the chunk is not processed
+			close(chRes)
+			return
+		}
+		bits := (scalars[i][s.index] & s.mask) >> s.shift
+		if s.multiWordSelect {
+			bits += (scalars[i][s.index+1] & s.maskHigh) << s.shiftHigh
+		}
+
+		if bits == 0 || points[i].IsInfinity() {
+			continue
+		}
+
+		// if msbWindow bit is set, we need to substract
+		op := batchOp{pointID: uint32(i)}
+		if bits&msbWindow == 0 {
+			op.bucketID = uint32(bits - 1)
+			op.isAdd = true
+		} else {
+			op.bucketID = uint32(bits & ^msbWindow)
+		}
+
+		if inBatch[op.bucketID] {
+			// conflict, the bucket is already in the batch
+			queue = append(queue, op)
+			if len(queue) == maxBatch {
+				executeBatch()
+				processQueue()
+			}
+			continue
+		}
+
+		add(op)
+		if len(batchBuckets) == maxBatch {
+			executeBatch()
+			processQueue()
+		}
+	}
+
+	// flush the batch and the queue
+	for len(batchBuckets) != 0 || len(queue) != 0 {
+		executeBatch()
+		processQueue()
+	}
+
+	// reduce buckets into total
+	// total =  bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+
+	var runningSum, total g1JacExtended
+	runningSum.setInfinity()
+	total.setInfinity()
+	for k := len(buckets) - 1; k >= 0; k-- {
+		runningSum.addMixed(&buckets[k])
 		total.add(&runningSum)
 	}
 
@@ -352,7 +1037,73 @@ func msmProcessChunkG1Affine(chunk uint64,
 	close(chRes)
 }
 
-func (p *G1Jac) msmC4(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+// batchScratchG1Affine is the memory used by batchAddG1Affine, allocated once per chunk
+type batchScratchG1Affine struct {
+	denominators   []fp.Element
+	prefixProducts []fp.Element
+}
+
+func (scratch *batchScratchG1Affine) init(maxBatch int) {
+	scratch.denominators = make([]fp.Element, maxBatch)
+	scratch.prefixProducts = make([]fp.Element, maxBatch)
+}
+
+// batchAddG1Affine sets buckets[bucketIDs[i]] to buckets[bucketIDs[i]] + points[i] for all i,
+// with the affine addition (or doubling) formula, sharing the inversions of the denominators
+// (Montgomery's trick).
+//
+// The bucketIDs must be distinct, and for all i, buckets[bucketIDs[i]] and points[i] must not be
+// the point at infinity, and their sum must not be the point at infinity.
+func batchAddG1Affine(buckets []G1Affine, bucketIDs []uint32, points []G1Affine, scratch *batchScratchG1Affine) {
+	n := len(bucketIDs)
+	if n == 0 {
+		return
+	}
+	denominators := scratch.denominators[:n]
+	prefixProducts := scratch.prefixProducts[:n]
+
+	// accumulator = Π denominators
+	var accumulator fp.Element
+	accumulator.SetOne()
+	for i := 0; i < n; i++ {
+		R := &buckets[bucketIDs[i]]
+		if points[i].X.Equal(&R.X) {
+			// doubling
+			denominators[i].Double(&R.Y)
+		} else {
+			denominators[i].Sub(&points[i].X, &R.X)
+		}
+		prefixProducts[i] = accumulator
+		accumulator.Mul(&accumulator, &denominators[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	var inv, lambda, x, y fp.Element
+	for i := n - 1; i >= 0; i-- {
+		// accumulator = 1 / Π_{j<=i} denominators[j]
+		inv.Mul(&accumulator, &prefixProducts[i])
+		accumulator.Mul(&accumulator, &denominators[i])
+
+		// λ = (y_P - y_R) / (x_P - x_R), or 3x_R² / 2y_R for a doubling
+		// x = λ² - x_R - x_P
+		// y = λ(x_R - x) - y_R
+		R := &buckets[bucketIDs[i]]
+		if points[i].X.Equal(&R.X) {
+			lambda.Square(&R.X)
+			x.Double(&lambda)
+			lambda.Add(&lambda, &x).Mul(&lambda, &inv)
+		} else {
+			lambda.Sub(&points[i].Y, &R.Y).Mul(&lambda, &inv)
+		}
+		x.Square(&lambda).Sub(&x, &R.X).Sub(&x, &points[i].X)
+		y.Sub(&R.X, &x).Mul(&y, &lambda).Sub(&y, &R.Y)
+		R.X = x
+		R.Y = y
+	}
+}
+
+func (p *G1Jac) msmC4(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 4                          // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) // number of c-bit radixes in a scalar
 
@@ -363,13 +1114,17 @@ func (p *G1Jac) msmC4(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	var wg sync.WaitGroup
 	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -377,11 +1132,11 @@ func (p *G1Jac) msmC4(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC5(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC5(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 5                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -393,24 +1148,32 @@ func (p *G1Jac) msmC5(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -418,11 +1181,11 @@ func (p *G1Jac) msmC5(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC6(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC6(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 6                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -434,24 +1197,32 @@ func (p *G1Jac) msmC6(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -459,11 +1230,11 @@ func (p *G1Jac) msmC6(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC7(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC7(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 7                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -475,24 +1246,32 @@ func (p *G1Jac) msmC7(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -500,11 +1279,11 @@ func (p *G1Jac) msmC7(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC8(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC8(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 8                          // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) // number of c-bit radixes in a scalar
 
@@ -515,13 +1294,17 @@ func (p *G1Jac) msmC8(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	var wg sync.WaitGroup
 	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -529,11 +1312,11 @@ func (p *G1Jac) msmC8(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC9(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC9(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 9                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -545,24 +1328,32 @@ func (p *G1Jac) msmC9(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -570,11 +1361,11 @@ func (p *G1Jac) msmC9(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC10(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC10(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 10                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -586,24 +1377,32 @@ func (p *G1Jac) msmC10(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -611,11 +1410,11 @@ func (p *G1Jac) msmC10(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC11(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC11(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 11                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -627,24 +1426,32 @@ func (p *G1Jac) msmC11(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -652,11 +1459,11 @@ func (p *G1Jac) msmC11(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC12(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC12(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 12                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -668,24 +1475,32 @@ func (p *G1Jac) msmC12(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -693,11 +1508,11 @@ func (p *G1Jac) msmC12(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC13(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC13(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 13                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -709,24 +1524,32 @@ func (p *G1Jac) msmC13(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -734,11 +1557,11 @@ func (p *G1Jac) msmC13(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC14(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC14(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 14                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -750,24 +1573,32 @@ func (p *G1Jac) msmC14(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -775,11 +1606,11 @@ func (p *G1Jac) msmC14(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC15(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC15(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 15                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -791,24 +1622,32 @@ func (p *G1Jac) msmC15(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -816,11 +1655,11 @@ func (p *G1Jac) msmC15(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC16(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC16(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 16                         // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) // number of c-bit radixes in a scalar
 
@@ -831,13 +1670,17 @@ func (p *G1Jac) msmC16(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	var wg sync.WaitGroup
 	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -845,11 +1688,11 @@ func (p *G1Jac) msmC16(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC20(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC20(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 20                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -861,24 +1704,32 @@ func (p *G1Jac) msmC20(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -886,11 +1737,11 @@ func (p *G1Jac) msmC20(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC21(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC21(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 21                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -902,24 +1753,32 @@ func (p *G1Jac) msmC21(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -927,11 +1786,11 @@ func (p *G1Jac) msmC21(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
-func (p *G1Jac) msmC22(points []G1Affine, scalars []fr.Element, opt *CPUSemaphore) *G1Jac {
+func (p *G1Jac) msmC22(points []G1Affine, scalars []fr.Element, sched *msmScheduler) *G1Jac {
 	const c = 22                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -943,24 +1802,32 @@ func (p *G1Jac) msmC22(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g1JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g1JacExtended
-		msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g1JacExtended
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g1JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g1JacExtended, points []G1Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g1JacExtended
-			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG1Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -968,7 +1835,7 @@ func (p *G1Jac) msmC22(points []G1Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG1Affine(p, c, chChunks[:])
 }
 
@@ -986,6 +1853,177 @@ func (p *G2Affine) MultiExp(points []G2Affine, scalars []fr.Element, opts ...*CP
 // optionally, takes as parameter a CPUSemaphore struct
 // enabling to set max number of cpus to use
 func (p *G2Jac) MultiExp(points []G2Affine, scalars []fr.Element, opts ...*CPUSemaphore) *G2Jac {
+	var opt *CPUSemaphore
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewCPUSemaphore(runtime.NumCPU())
+	}
+
+	sched := &msmScheduler{sem: opt}
+	if msmUseGLVG2Affine(len(points)) {
+		C := msmBestCGLVG2Affine(len(points), 0)
+		return p.msmGLV(points, scalars, C, sched)
+	}
+	C := msmBestCG2Affine(len(points), 0)
+	return p.msm(points, scalars, C, sched)
+}
+
+// MultiExpContext implements section 4 of https://eprint.iacr.org/2012/549.pdf, as MultiExp does,
+// with at most config.NbTasks go routines, and at most config.MaxBucketMemory bytes of buckets allocated
+// at the same time (if the memory bound is lower than the buckets of a single c-bit window, a single
+// window of the smallest implemented c is processed at a time).
+//
+// As MultiExp, MultiExpContext splits the scalars with the GLV endomorphism (see msmGLV) for the numbers
+// of points where it is faster. If config.GLV is set, the scalars are split whatever the number of points.
+//
+// If ctx is done before the end of the computation, the go routines stop early and
+// MultiExpContext returns ctx.Err(), leaving p unchanged.
+func (p *G2Jac) MultiExpContext(ctx context.Context, points []G2Affine, scalars []fr.Element, config ecc.MultiExpConfig) (*G2Jac, error) {
+	if err := ctx.Err(); err != nil {
+		return p, err
+	}
+
+	nbTasks := config.NbTasks
+	if nbTasks <= 0 {
+		nbTasks = runtime.NumCPU()
+	}
+
+	C := msmBestCG2Affine(len(points), config.MaxBucketMemory)
+	glv := config.GLV || msmUseGLVG2Affine(len(points))
+	if glv {
+		C = msmBestCGLVG2Affine(len(points), config.MaxBucketMemory)
+	}
+	if config.MaxBucketMemory > 0 {
+		// each go routine allocates the buckets of a c-bit window
+		maxTasks := config.MaxBucketMemory / msmBucketMemoryG2Affine(C)
+		if maxTasks < 1 {
+			maxTasks = 1
+		}
+		if maxTasks < nbTasks {
+			nbTasks = maxTasks
+		}
+	}
+
+	var _p G2Jac
+	sched := &msmScheduler{sem: NewCPUSemaphore(nbTasks), done: ctx.Done()}
+	if glv {
+		_p.msmGLV(points, scalars, C, sched)
+	} else {
+		_p.msm(points, scalars, C, sched)
+	}
+	if err := ctx.Err(); err != nil {
+		return p, err
+	}
+	p.Set(&_p)
+	return p, nil
+}
+
+// MultiExpUint64 computes the MultiExp of points with small scalars, as MultiExp does with the
+// corresponding fr.Element scalars.
+// optionally, takes as parameter a CPUSemaphore struct
+// enabling to set max number of cpus to use
+func (p *G2Affine) MultiExpUint64(points []G2Affine, scalars []uint64, opts ...*CPUSemaphore) *G2Affine {
+	var _p G2Jac
+	_p.MultiExpUint64(points, scalars, opts...)
+	p.FromJacobian(&_p)
+	return p
+}
+
+// MultiExpUint64 computes the MultiExp of points with small scalars, as MultiExp does with the
+// corresponding fr.Element scalars (booleans, range checked bytes, ...).
+//
+// The points with a 0 scalar are skipped, the points with a scalar 1 are added directly, and
+// the other scalars are processed over as many c-bit windows as the bit length of the largest one needs.
+//
+// optionally, takes as parameter a CPUSemaphore struct
+// enabling to set max number of cpus to use
+func (p *G2Jac) MultiExpUint64(points []G2Affine, scalars []uint64, opts ...*CPUSemaphore) *G2Jac {
+	var opt *CPUSemaphore
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewCPUSemaphore(runtime.NumCPU())
+	}
+	return p.msmUint64(points, scalars, &msmScheduler{sem: opt})
+}
+
+// msmUint64 computes the MultiExpUint64, scheduling the go routines with sched.
+func (p *G2Jac) msmUint64(points []G2Affine, scalars []uint64, sched *msmScheduler) *G2Jac {
+	// take all the cpus to ourselves
+	sched.sem.lock.Lock()
+	nbTasks := cap(sched.sem.chCpus)
+
+	// sum the points with a scalar 1, and get the bit length of the larger scalars
+	var sum g2JacExtended
+	sum.setInfinity()
+	var acc uint64
+	nbLarge := 0
+	var lock sync.Mutex
+	parallel.Execute(len(scalars), func(start, end int) {
+		var partial g2JacExtended
+		partial.setInfinity()
+		var partialAcc uint64
+		partialNbLarge := 0
+		for i := start; i < end; i++ {
+			if scalars[i] == 1 {
+				partial.addMixed(&points[i])
+			} else if scalars[i] > 1 {
+				partialAcc |= scalars[i]
+				partialNbLarge++
+			}
+		}
+		lock.Lock()
+		sum.add(&partial)
+		acc |= partialAcc
+		nbLarge += partialNbLarge
+		lock.Unlock()
+	}, nbTasks)
+
+	if nbLarge == 0 {
+		sched.sem.lock.Unlock()
+		return p.fromJacExtended(&sum)
+	}
+
+	// as in msmGLV, the highest window has 2 bits more than the scalars: there is no carry out of it
+	nbBits := uint64(bits.Len64(acc) + 2)
+	c := msmBestCUint64(nbLarge, nbBits)
+
+	// a go routine processes a c-bit window: with fewer windows than tasks, the points are split in parts
+	// whose windows are processed concurrently
+	nbParts := nbTasks / int((nbBits+c-1)/c)
+	if maxParts := nbLarge / msmMinPartSize; nbParts > maxParts {
+		nbParts = maxParts
+	}
+	if nbParts > 1 {
+		c = msmBestCUint64(nbLarge/nbParts, nbBits)
+	} else {
+		nbParts = 1
+	}
+
+	// the digits of the scalars 0 and 1 are 0: the points are skipped
+	partitioned := partitionScalarsUint64(scalars, c, nbBits, nbTasks)
+
+	chParts := make([][]chan g2JacExtended, nbParts)
+	for i := 0; i < nbParts; i++ {
+		start, end := i*len(scalars)/nbParts, (i+1)*len(scalars)/nbParts
+		chParts[i] = msmScheduleChunksG2Affine(points[start:end], partitioned[start:end], c, nbBits, sched)
+	}
+
+	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
+	sched.sem.lock.Unlock()
+
+	var _sum G2Jac
+	p.fromJacExtended(&sum)
+	for i := 0; i < nbParts; i++ {
+		p.AddAssign(msmReduceChunkG2Affine(&_sum, int(c), chParts[i]))
+	}
+	return p
+}
+
+// msm computes the MultiExp with c-bit windows, scheduling the go routines with sched.
+// If the multi-exponentiation is cancelled, the go routines stop early and the result is undefined.
+func (p *G2Jac) msm(points []G2Affine, scalars []fr.Element, C uint64, sched *msmScheduler) *G2Jac {
 	// note:
 	// each of the msmCX method is the same, except for the c constant it declares
 	// duplicating (through template generation) these methods allows to declare the buckets on the stack
@@ -1012,102 +2050,213 @@ func (p *G2Jac) MultiExp(points []G2Affine, scalars []fr.Element, opts ...*CPUSe
 	// step 3
 	// reduce the buckets weigthed sums into our result (msmReduceChunk)
 
-	var opt *CPUSemaphore
-	if len(opts) > 0 {
-		opt = opts[0]
-	} else {
-		opt = NewCPUSemaphore(runtime.NumCPU())
-	}
-
-	var C uint64
-	nbPoints := len(points)
-
-	// implemented msmC methods (the c we use must be in this slice)
-	implementedCs := []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 20, 21, 22}
-
-	// approximate cost (in group operations)
-	// cost = bits/c * (nbPoints + 2^{c})
-	// this needs to be verified empirically.
-	// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
-	min := math.MaxFloat64
-	for _, c := range implementedCs {
-		cc := fr.Limbs * 64 * (nbPoints + (1 << (c)))
-		cost := float64(cc) / float64(c)
-		if cost < min {
-			min = cost
-			C = c
-		}
-	}
-
-	// empirical, needs to be tuned.
-	// if C > 16 && nbPoints < 1 << 23 {
-	// 	C = 16
-	// }
-
 	// take all the cpus to ourselves
-	opt.lock.Lock()
+	sched.sem.lock.Lock()
 
 	// partition the scalars
 	// note: we do that before the actual chunk processing, as for each c-bit window (starting from LSW)
 	// if it's larger than 2^{c-1}, we have a carry we need to propagate up to the higher window
-	scalars = partitionScalars(scalars, C)
+	scalars = partitionScalars(scalars, C, cap(sched.sem.chCpus))
+
+	// for large MultiExp, accumulating the points in affine buckets by batches is faster
+	if (1 << (C - 1)) >= minBatchAffineBuckets {
+		return p.msmBatchAffine(points, scalars, C, fr.Limbs*64, sched)
+	}
 
 	switch C {
 
 	case 4:
-		return p.msmC4(points, scalars, opt)
+		return p.msmC4(points, scalars, sched)
 
 	case 5:
-		return p.msmC5(points, scalars, opt)
+		return p.msmC5(points, scalars, sched)
 
 	case 6:
-		return p.msmC6(points, scalars, opt)
+		return p.msmC6(points, scalars, sched)
 
 	case 7:
-		return p.msmC7(points, scalars, opt)
+		return p.msmC7(points, scalars, sched)
 
 	case 8:
-		return p.msmC8(points, scalars, opt)
+		return p.msmC8(points, scalars, sched)
 
 	case 9:
-		return p.msmC9(points, scalars, opt)
+		return p.msmC9(points, scalars, sched)
 
 	case 10:
-		return p.msmC10(points, scalars, opt)
+		return p.msmC10(points, scalars, sched)
 
 	case 11:
-		return p.msmC11(points, scalars, opt)
+		return p.msmC11(points, scalars, sched)
 
 	case 12:
-		return p.msmC12(points, scalars, opt)
+		return p.msmC12(points, scalars, sched)
+
+	case 13:
+		return p.msmC13(points, scalars, sched)
+
+	case 14:
+		return p.msmC14(points, scalars, sched)
+
+	case 15:
+		return p.msmC15(points, scalars, sched)
+
+	case 16:
+		return p.msmC16(points, scalars, sched)
+
+	case 20:
+		return p.msmC20(points, scalars, sched)
+
+	case 21:
+		return p.msmC21(points, scalars, sched)
+
+	case 22:
+		return p.msmC22(points, scalars, sched)
+
+	default:
+		panic("unimplemented")
+	}
+}
+
+// msmGLV computes the MultiExp with c-bit windows, after splitting the scalars with the GLV endomorphism
+// (see https://www.iacr.org/archive/crypto2001/21390189.pdf): s*P = k1*P + k2*phi(P), where k1 and k2
+// are about half the size of s. The multi-exponentiation of the 2*len(scalars) points ±P, ±phi(P)
+// has half as many c-bit windows.
+func (p *G2Jac) msmGLV(points []G2Affine, scalars []fr.Element, c uint64, sched *msmScheduler) *G2Jac {
+	// take all the cpus to ourselves
+	sched.sem.lock.Lock()
+	nbTasks := cap(sched.sem.chCpus)
+
+	// split the scalars, and set the points to ±P, ±phi(P) accordingly
+	n := len(scalars)
+	glvPoints := make([]G2Affine, 2*n)
+	glvScalars := make([]fr.Element, 2*n)
+	parallel.Execute(n, func(start, end int) {
+		splitter := ecc.NewScalarSplitter(&glvBasis)
+		var s, k1, k2 big.Int
+		for i := start; i < end; i++ {
+			// the scalars are in regular form
+			scalars[i].ToBigInt(&s)
+			splitter.Split(&s, &k1, &k2)
+
+			glvPoints[i] = points[i]
+			glvPoints[n+i].X.MulByElement(&points[i].X, &thirdRootOneG2)
+			glvPoints[n+i].Y = points[i].Y
+			if k1.Sign() == -1 {
+				k1.Neg(&k1)
+				glvPoints[i].Neg(&glvPoints[i])
+			}
+			if k2.Sign() == -1 {
+				k2.Neg(&k2)
+				glvPoints[n+i].Neg(&glvPoints[n+i])
+			}
+			setRegular(&glvScalars[i], &k1)
+			setRegular(&glvScalars[n+i], &k2)
+		}
+	}, nbTasks)
+
+	// the number of windows depends on the bit length of the largest half-size scalar
+	var acc [fr.Limbs]uint64
+	for i := 0; i < len(glvScalars); i++ {
+		for j := 0; j < fr.Limbs; j++ {
+			acc[j] |= glvScalars[i][j]
+		}
+	}
+	bitLen := 0
+	for j := fr.Limbs - 1; j >= 0; j-- {
+		if acc[j] != 0 {
+			bitLen = 64*j + bits.Len64(acc[j])
+			break
+		}
+	}
+
+	if isCancelled(sched.done) {
+		sched.sem.lock.Unlock()
+		return p
+	}
+
+	// the digits of the highest window are in [0, 2^{c-1}) if it has at least 2 bits more than the scalars:
+	// then, there is no carry out of it, and it needs less buckets
+	glvScalars = partitionScalars(glvScalars, c, nbTasks)
+	return p.msmBatchAffine(glvPoints, glvScalars, c, uint64(bitLen+2), sched)
+}
 
-	case 13:
-		return p.msmC13(points, scalars, opt)
+// msmUseGLVG2Affine returns true if msmGLV is faster than msm for a MultiExp of nbPoints points.
+// The window is measured with BenchmarkMultiExpGLVG2 (single core): outside of it, msm is
+// as fast or faster (above it, the larger c-bit windows of msm, with affine buckets, make up for their number).
+func msmUseGLVG2Affine(nbPoints int) bool {
+	return nbPoints < 65536
+}
 
-	case 14:
-		return p.msmC14(points, scalars, opt)
+// msmBestCGLVG2Affine returns the window size c minimizing the approximate cost of a msmGLV of nbPoints points.
+// If maxBucketMemory > 0, the buckets of a c-bit window must fit in maxBucketMemory bytes, or c is msmMinC.
+//
+// msmGLV processes 2*nbPoints points, and its c-bit windows are not bound to the implemented msmCX.
+func msmBestCGLVG2Affine(nbPoints int, maxBucketMemory int) uint64 {
+	C := uint64(msmMinC)
+	min := msmCost(2*nbPoints, C, msmGLVBits)
+	for c := C + 1; c <= msmMaxC; c++ {
+		if maxBucketMemory > 0 && msmBucketMemoryG2Affine(c) > maxBucketMemory {
+			break
+		}
+		if cost := msmCost(2*nbPoints, c, msmGLVBits); cost < min {
+			min = cost
+			C = c
+		}
+	}
+	return C
+}
 
-	case 15:
-		return p.msmC15(points, scalars, opt)
+// msmBestCG2Affine returns the window size c minimizing the approximate cost of a MultiExp of nbPoints points.
+// If maxBucketMemory > 0, the buckets of a c-bit window must fit in maxBucketMemory bytes, or c is the smallest implemented c.
+func msmBestCG2Affine(nbPoints int, maxBucketMemory int) uint64 {
+	var C uint64
 
-	case 16:
-		return p.msmC16(points, scalars, opt)
+	// implemented msmC methods (the c we use must be in this slice)
+	implementedCs := []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 20, 21, 22}
 
-	case 20:
-		return p.msmC20(points, scalars, opt)
+	// approximate cost (in group operations)
+	// cost = bits/c * (nbPoints + 2^{c})
+	// this needs to be verified empirically.
+	// for example, on a MBP 2016, for G2 MultiExp > 8M points, hand picking c gives better results
+	min := math.MaxFloat64
+	for _, c := range implementedCs {
+		if maxBucketMemory > 0 && c != implementedCs[0] && msmBucketMemoryG2Affine(c) > maxBucketMemory {
+			continue
+		}
+		cc := fr.Limbs * 64 * (nbPoints + (1 << (c)))
+		cost := float64(cc) / float64(c)
+		if cost < min {
+			min = cost
+			C = c
+		}
+	}
 
-	case 21:
-		return p.msmC21(points, scalars, opt)
+	// empirical, needs to be tuned.
+	// if C > 16 && nbPoints < 1 << 23 {
+	// 	C = 16
+	// }
 
-	case 22:
-		return p.msmC22(points, scalars, opt)
+	return C
+}
 
-	default:
-		panic("unimplemented")
+// msmBucketMemoryG2Affine returns the size in bytes of the buckets allocated to process a c-bit window of a MultiExp
+func msmBucketMemoryG2Affine(c uint64) int {
+	const sizeCoord = 2 * fp.Limbs * 8
+	nbBuckets := 1 << (c - 1)
+	if nbBuckets < minBatchAffineBuckets {
+		// g2JacExtended buckets
+		return nbBuckets * 4 * sizeCoord
 	}
+	// affine buckets and their inBatch flag, the batch, its scratch memory and the queue of conflicting additions
+	// (see msmProcessChunkG2AffineBatchAffine)
+	batch := batchSize(nbBuckets)
+	return nbBuckets*(2*sizeCoord+1) + batch*(4*sizeCoord+4+12)
 }
 
 // msmReduceChunkG2Affine reduces the weighted sum of the buckets into the result of the multiExp
+//
+// the channel of a chunk which is not processed (the multiExp is cancelled) is closed without a value
 func msmReduceChunkG2Affine(p *G2Jac, c int, chChunks []chan g2JacExtended) *G2Jac {
 	var _p g2JacExtended
 	totalj := <-chChunks[len(chChunks)-1]
@@ -1128,7 +2277,8 @@ func msmProcessChunkG2Affine(chunk uint64,
 	buckets []g2JacExtended,
 	c uint64,
 	points []G2Affine,
-	scalars []fr.Element) {
+	scalars []fr.Element,
+	done <-chan struct{}) {
 
 	mask := uint64((1 << c) - 1) // low c bits are 1
 	msbWindow := uint64(1 << (c - 1))
@@ -1151,6 +2301,11 @@ func msmProcessChunkG2Affine(chunk uint64,
 
 	// for each scalars, get the digit corresponding to the chunk we're processing.
 	for i := 0; i < len(scalars); i++ {
+		if i%msmCancelCheck == 0 && isCancelled(done) {
+			// the chunk is not processed
+			close(chRes)
+			return
+		}
 		bits := (scalars[i][s.index] & s.mask) >> s.shift
 		if s.multiWordSelect {
 			bits += (scalars[i][s.index+1] & s.maskHigh) << s.shiftHigh
@@ -1187,7 +2342,294 @@ func msmProcessChunkG2Affine(chunk uint64,
 	close(chRes)
 }
 
-func (p *G2Jac) msmC4(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+// msmBatchAffine is msmCX with the points accumulated in affine buckets by batches
+// (see msmProcessChunkG2AffineBatchAffine). scalars must be partitioned with the same c,
+// and only their nbBits lowest bits are processed: the partitioned scalars must be 0 above.
+//
+// As the buckets are allocated on the heap, c is not a constant.
+func (p *G2Jac) msmBatchAffine(points []G2Affine, scalars []fr.Element, c, nbBits uint64, sched *msmScheduler) *G2Jac {
+	chChunks := msmScheduleChunksG2Affine(points, scalars, c, nbBits, sched)
+
+	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
+	sched.sem.lock.Unlock()
+	return msmReduceChunkG2Affine(p, int(c), chChunks)
+}
+
+// msmScheduleChunksG2Affine spawns a go routine per c-bit window of the nbBits lowest bits of the
+// partitioned scalars, and returns the channels of their weighted bucket sums (see msmBatchAffine).
+// The buckets are in affine coordinates if there are enough of them.
+func msmScheduleChunksG2Affine(points []G2Affine, scalars []fr.Element, c, nbBits uint64, sched *msmScheduler) []chan g2JacExtended {
+	if nbBits > fr.Limbs*64 {
+		nbBits = fr.Limbs * 64
+	}
+	nbChunks := nbBits / c // number of c-bit radixes in a scalar
+	if nbBits%c != 0 {
+		nbChunks++
+	}
+
+	// for each chunk, spawn a go routine that'll loop through all the scalars
+	chChunks := make([]chan g2JacExtended, nbChunks)
+
+	// wait group to wait for all the go routines to start
+	var wg sync.WaitGroup
+	for chunk := int(nbChunks) - 1; chunk >= 0; chunk-- {
+		chChunks[chunk] = make(chan g2JacExtended, 1)
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended) {
+			wg.Done()
+			// if c doesn't divide nbBits, last window is smaller we can allocate less buckets
+			nbBuckets := 1 << (c - 1)
+			if lastC := nbBits - j*c; lastC < c {
+				nbBuckets = 1 << (lastC - 1)
+			}
+			if nbBuckets < minBatchAffineBuckets {
+				buckets := make([]g2JacExtended, nbBuckets)
+				msmProcessChunkG2Affine(j, chRes, buckets, c, points, scalars, sched.done)
+			} else {
+				msmProcessChunkG2AffineBatchAffine(j, chRes, nbBuckets, c, points, scalars, sched.done)
+			}
+			sched.release() // release token in the semaphore
+		}(uint64(chunk), chChunks[chunk])
+	}
+
+	// wait for all goRoutines to actually start
+	wg.Wait()
+
+	return chChunks
+}
+
+// msmProcessChunkG2AffineBatchAffine is msmProcessChunkG2Affine, with the buckets in affine coordinates
+//
+// The additions into the buckets are performed by batches, sharing a single field inversion
+// (see batchAddG2Affine). A bucket can't be updated twice in the same batch: such conflicting
+// additions are queued and processed in a later batch.
+func msmProcessChunkG2AffineBatchAffine(chunk uint64,
+	chRes chan<- g2JacExtended,
+	nbBuckets int,
+	c uint64,
+	points []G2Affine,
+	scalars []fr.Element,
+	done <-chan struct{}) {
+
+	mask := uint64((1 << c) - 1) // low c bits are 1
+	msbWindow := uint64(1 << (c - 1))
+
+	// the zero value of an affine point is the point at infinity
+	buckets := make([]G2Affine, nbBuckets)
+
+	jc := uint64(chunk * c)
+	s := selector{}
+	s.index = jc / 64
+	s.shift = jc - (s.index * 64)
+	s.mask = mask << s.shift
+	s.multiWordSelect = (64%c) != 0 && s.shift > (64-c) && s.index < (fr.Limbs-1)
+	if s.multiWordSelect {
+		nbBitsHigh := s.shift - uint64(64-c)
+		s.maskHigh = (1 << nbBitsHigh) - 1
+		s.shiftHigh = (c - nbBitsHigh)
+	}
+
+	// current batch: batchPoints[i] is to be added to buckets[batchBuckets[i]]
+	maxBatch := batchSize(nbBuckets)
+	batchBuckets := make([]uint32, 0, maxBatch)
+	batchPoints := make([]G2Affine, 0, maxBatch)
+	inBatch := make([]bool, nbBuckets)
+	var scratch batchScratchG2Affine
+	scratch.init(maxBatch)
+
+	// conflicting additions, waiting for their bucket to leave the batch
+	queue := make([]batchOp, 0, maxBatch)
+
+	// add adds ±points[op.pointID] into its bucket, or into the current batch.
+	// the bucket must not be in the current batch.
+	add := func(op batchOp) {
+		point := &points[op.pointID]
+		bucket := &buckets[op.bucketID]
+
+		// the special cases are handled directly
+		if bucket.IsInfinity() {
+			if op.isAdd {
+				bucket.Set(point)
+			} else {
+				bucket.Neg(point)
+			}
+			return
+		}
+		if bucket.X.Equal(&point.X) && (bucket.Y.Equal(&point.Y) != op.isAdd || bucket.Y.IsZero()) {
+			// bucket - bucket
+			bucket.X.SetZero()
+			bucket.Y.SetZero()
+			return
+		}
+
+		inBatch[op.bucketID] = true
+		batchBuckets = append(batchBuckets, op.bucketID)
+		batchPoints = append(batchPoints, *point)
+		if !op.isAdd {
+			batchPoints[len(batchPoints)-1].Y.Neg(&point.Y)
+		}
+	}
+
+	// executeBatch performs the additions of the current batch and empties it
+	executeBatch := func() {
+		batchAddG2Affine(buckets, batchBuckets, batchPoints, &scratch)
+		for _, bucketID := range batchBuckets {
+			inBatch[bucketID] = false
+		}
+		batchBuckets = batchBuckets[:0]
+		batchPoints = batchPoints[:0]
+	}
+
+	// processQueue moves the queued additions whose bucket is not in the batch anymore to the batch
+	processQueue := func() {
+		for i := len(queue) - 1; i >= 0; i-- {
+			if inBatch[queue[i].bucketID] {
+				continue
+			}
+			add(queue[i])
+			if len(batchBuckets) == maxBatch {
+				executeBatch()
+			}
+			queue[i] = queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+		}
+	}
+
+	// for each scalars, get the digit corresponding to the chunk we're processing.
+	for i := 0; i < len(scalars); i++ {
+		if i%msmCancelCheck == 0 && isCancelled(done) {
+			// the chunk is not processed
+			close(chRes)
+			return
+		}
+		bits := (scalars[i][s.index] & s.mask) >> s.shift
+		if s.multiWordSelect {
+			bits += (scalars[i][s.index+1] & s.maskHigh) << s.shiftHigh
+		}
+
+		if bits == 0 || points[i].IsInfinity() {
+			continue
+		}
+
+		// if msbWindow bit is set, we need to substract
+		op := batchOp{pointID: uint32(i)}
+		if bits&msbWindow == 0 {
+			op.bucketID = uint32(bits - 1)
+			op.isAdd = true
+		} else {
+			op.bucketID = uint32(bits & ^msbWindow)
+		}
+
+		if inBatch[op.bucketID] {
+			// conflict, the bucket is already in the batch
+			queue = append(queue, op)
+			if len(queue) == maxBatch {
+				executeBatch()
+				processQueue()
+			}
+			continue
+		}
+
+		add(op)
+		if len(batchBuckets) == maxBatch {
+			executeBatch()
+			processQueue()
+		}
+	}
+
+	// flush the batch and the queue
+	for len(batchBuckets) != 0 || len(queue) != 0 {
+		executeBatch()
+		processQueue()
+	}
+
+	// reduce buckets into total
+	// total =  bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+
+	var runningSum, total g2JacExtended
+	runningSum.setInfinity()
+	total.setInfinity()
+	for k := len(buckets) - 1; k >= 0; k-- {
+		runningSum.addMixed(&buckets[k])
+		total.add(&runningSum)
+	}
+
+	chRes <- total
+	close(chRes)
+}
+
+// batchScratchG2Affine is the memory used by batchAddG2Affine, allocated once per chunk
+type batchScratchG2Affine struct {
+	denominators   []fptower.E2
+	prefixProducts []fptower.E2
+}
+
+func (scratch *batchScratchG2Affine) init(maxBatch int) {
+	scratch.denominators = make([]fptower.E2, maxBatch)
+	scratch.prefixProducts = make([]fptower.E2, maxBatch)
+}
+
+// batchAddG2Affine sets buckets[bucketIDs[i]] to buckets[bucketIDs[i]] + points[i] for all i,
+// with the affine addition (or doubling) formula, sharing the inversions of the denominators
+// (Montgomery's trick).
+//
+// The bucketIDs must be distinct, and for all i, buckets[bucketIDs[i]] and points[i] must not be
+// the point at infinity, and their sum must not be the point at infinity.
+func batchAddG2Affine(buckets []G2Affine, bucketIDs []uint32, points []G2Affine, scratch *batchScratchG2Affine) {
+	n := len(bucketIDs)
+	if n == 0 {
+		return
+	}
+	denominators := scratch.denominators[:n]
+	prefixProducts := scratch.prefixProducts[:n]
+
+	// accumulator = Π denominators
+	var accumulator fptower.E2
+	accumulator.SetOne()
+	for i := 0; i < n; i++ {
+		R := &buckets[bucketIDs[i]]
+		if points[i].X.Equal(&R.X) {
+			// doubling
+			denominators[i].Double(&R.Y)
+		} else {
+			denominators[i].Sub(&points[i].X, &R.X)
+		}
+		prefixProducts[i] = accumulator
+		accumulator.Mul(&accumulator, &denominators[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	var inv, lambda, x, y fptower.E2
+	for i := n - 1; i >= 0; i-- {
+		// accumulator = 1 / Π_{j<=i} denominators[j]
+		inv.Mul(&accumulator, &prefixProducts[i])
+		accumulator.Mul(&accumulator, &denominators[i])
+
+		// λ = (y_P - y_R) / (x_P - x_R), or 3x_R² / 2y_R for a doubling
+		// x = λ² - x_R - x_P
+		// y = λ(x_R - x) - y_R
+		R := &buckets[bucketIDs[i]]
+		if points[i].X.Equal(&R.X) {
+			lambda.Square(&R.X)
+			x.Double(&lambda)
+			lambda.Add(&lambda, &x).Mul(&lambda, &inv)
+		} else {
+			lambda.Sub(&points[i].Y, &R.Y).Mul(&lambda, &inv)
+		}
+		x.Square(&lambda).Sub(&x, &R.X).Sub(&x, &points[i].X)
+		y.Sub(&R.X, &x).Mul(&y, &lambda).Sub(&y, &R.Y)
+		R.X = x
+		R.Y = y
+	}
+}
+
+func (p *G2Jac) msmC4(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 4                          // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) // number of c-bit radixes in a scalar
 
@@ -1198,13 +2640,17 @@ func (p *G2Jac) msmC4(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	var wg sync.WaitGroup
 	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1212,11 +2658,11 @@ func (p *G2Jac) msmC4(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC5(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC5(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 5                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1228,24 +2674,32 @@ func (p *G2Jac) msmC5(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1253,11 +2707,11 @@ func (p *G2Jac) msmC5(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC6(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC6(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 6                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1269,24 +2723,32 @@ func (p *G2Jac) msmC6(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1294,11 +2756,11 @@ func (p *G2Jac) msmC6(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC7(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC7(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 7                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1310,24 +2772,32 @@ func (p *G2Jac) msmC7(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1335,11 +2805,11 @@ func (p *G2Jac) msmC7(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC8(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC8(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 8                          // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) // number of c-bit radixes in a scalar
 
@@ -1350,13 +2820,17 @@ func (p *G2Jac) msmC8(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	var wg sync.WaitGroup
 	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1364,11 +2838,11 @@ func (p *G2Jac) msmC8(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC9(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC9(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 9                              // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1380,24 +2854,32 @@ func (p *G2Jac) msmC9(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1405,11 +2887,11 @@ func (p *G2Jac) msmC9(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC10(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC10(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 10                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1421,24 +2903,32 @@ func (p *G2Jac) msmC10(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1446,11 +2936,11 @@ func (p *G2Jac) msmC10(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC11(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC11(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 11                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1462,24 +2952,32 @@ func (p *G2Jac) msmC11(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1487,11 +2985,11 @@ func (p *G2Jac) msmC11(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC12(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC12(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 12                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1503,24 +3001,32 @@ func (p *G2Jac) msmC12(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1528,11 +3034,11 @@ func (p *G2Jac) msmC12(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC13(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC13(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 13                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1544,24 +3050,32 @@ func (p *G2Jac) msmC13(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1569,11 +3083,11 @@ func (p *G2Jac) msmC13(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC14(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC14(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 14                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1585,24 +3099,32 @@ func (p *G2Jac) msmC14(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1610,11 +3132,11 @@ func (p *G2Jac) msmC14(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC15(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC15(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 15                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1626,24 +3148,32 @@ func (p *G2Jac) msmC15(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1651,11 +3181,11 @@ func (p *G2Jac) msmC15(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC16(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC16(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 16                         // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) // number of c-bit radixes in a scalar
 
@@ -1666,13 +3196,17 @@ func (p *G2Jac) msmC16(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	var wg sync.WaitGroup
 	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1680,11 +3214,11 @@ func (p *G2Jac) msmC16(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC20(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC20(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 20                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1696,24 +3230,32 @@ func (p *G2Jac) msmC20(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1721,11 +3263,11 @@ func (p *G2Jac) msmC20(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC21(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC21(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 21                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1737,24 +3279,32 @@ func (p *G2Jac) msmC21(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1762,11 +3312,11 @@ func (p *G2Jac) msmC21(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
 
-func (p *G2Jac) msmC22(points []G2Affine, scalars []fr.Element, opt *CPUSemaphore) *G2Jac {
+func (p *G2Jac) msmC22(points []G2Affine, scalars []fr.Element, sched *msmScheduler) *G2Jac {
 	const c = 22                             // scalars partitioned into c-bit radixes
 	const nbChunks = (fr.Limbs * 64 / c) + 1 // number of c-bit radixes in a scalar
 
@@ -1778,24 +3328,32 @@ func (p *G2Jac) msmC22(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	// c doesn't divide 256, last window is smaller we can allocate less buckets
 	const lastC = (fr.Limbs * 64) - (c * (fr.Limbs * 64 / c))
 	chChunks[nbChunks-1] = make(chan g2JacExtended, 1)
-	<-opt.chCpus // wait to have a cpu before scheduling
-	wg.Add(1)
-	go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
-		wg.Done()
-		var buckets [1 << (lastC - 1)]g2JacExtended
-		msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-		opt.chCpus <- struct{}{} // release token in the semaphore
-	}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	if sched.acquire() { // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
+			wg.Done()
+			var buckets [1 << (lastC - 1)]g2JacExtended
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
+		}(uint64(nbChunks-1), chChunks[nbChunks-1], points, scalars)
+	} else {
+		// the multiExp is cancelled, the chunk is not processed
+		close(chChunks[nbChunks-1])
+	}
 
 	for chunk := nbChunks - 2; chunk >= 0; chunk-- {
 		chChunks[chunk] = make(chan g2JacExtended, 1)
-		<-opt.chCpus // wait to have a cpu before scheduling
+		if !sched.acquire() { // wait to have a cpu before scheduling
+			// the multiExp is cancelled, the chunk is not processed
+			close(chChunks[chunk])
+			continue
+		}
 		wg.Add(1)
 		go func(j uint64, chRes chan g2JacExtended, points []G2Affine, scalars []fr.Element) {
 			wg.Done()
 			var buckets [1 << (c - 1)]g2JacExtended
-			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars)
-			opt.chCpus <- struct{}{} // release token in the semaphore
+			msmProcessChunkG2Affine(j, chRes, buckets[:], c, points, scalars, sched.done)
+			sched.release() // release token in the semaphore
 		}(uint64(chunk), chChunks[chunk], points, scalars)
 	}
 
@@ -1803,6 +3361,6 @@ func (p *G2Jac) msmC22(points []G2Affine, scalars []fr.Element, opt *CPUSemaphor
 	wg.Wait()
 
 	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
-	opt.lock.Unlock()
+	sched.sem.lock.Unlock()
 	return msmReduceChunkG2Affine(p, c, chChunks[:])
 }
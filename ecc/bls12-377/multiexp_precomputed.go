@@ -0,0 +1,567 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12377
+
+import (
+	"errors"
+	"io"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidWindowSize    = errors.New("invalid window size: c must be in [3, 16]")
+	ErrInvalidStride        = errors.New("invalid stride: must be positive")
+	ErrInvalidMultiExpTable = errors.New("invalid multiExp table")
+	ErrScalarsSizeMismatch  = errors.New("number of scalars doesn't match the number of points of the table")
+)
+
+// nbWindows returns the number of c-bit windows of a scalar, as partitioned by partitionScalars
+func nbWindows(c uint64) uint64 {
+	n := fr.Limbs * 64 / c
+	if (fr.Limbs*64)%c != 0 {
+		n++
+	}
+	return n
+}
+
+// newSelector returns the selector of the c-bit window chunk of a scalar
+func newSelector(chunk, c uint64) selector {
+	mask := uint64((1 << c) - 1)
+	jc := uint64(chunk * c)
+	s := selector{}
+	s.index = jc / 64
+	s.shift = jc - (s.index * 64)
+	s.mask = mask << s.shift
+	s.multiWordSelect = (64%c) != 0 && s.shift > (64-c) && s.index < (fr.Limbs-1)
+	if s.multiWordSelect {
+		nbBitsHigh := s.shift - uint64(64-c)
+		s.maskHigh = (1 << nbBitsHigh) - 1
+		s.shiftHigh = (c - nbBitsHigh)
+	}
+	return s
+}
+
+// minWindowSize is the smallest c of a multiExp table.
+//
+// partitionScalars has no window above the highest one to carry into: the highest digit of a scalar s
+// is the rounding of s/2^{jc}, where jc is the lowest bit of the highest window, and it must be less than 2^{c-1}.
+// With c = 2, it is not on bls12-381, whose scalars have 255 bits. c >= 3 is valid on all curves.
+const minWindowSize = 3
+
+// checkMultiExpTable checks the parameters of a multiExp table
+func checkMultiExpTable(c, stride, nbPoints uint64, nbTablePoints int) error {
+	if c < minWindowSize || c > 16 {
+		return ErrInvalidWindowSize
+	}
+	if stride == 0 || stride > nbWindows(c) {
+		return ErrInvalidStride
+	}
+	nbRows := (nbWindows(c) + stride - 1) / stride
+	if uint64(nbTablePoints) != nbRows*nbPoints {
+		return ErrInvalidMultiExpTable
+	}
+	return nil
+}
+
+// G1MultiExpTable stores precomputed multiples of a fixed set of points, to speed up
+// the multi-exponentiations with these points (for example, the points of an SRS).
+//
+// Scalars are partitioned in c-bit windows, which are grouped in rows of Stride windows.
+// The row k of the table stores the points multiplied by 2^{k*Stride*c}, such that the windows
+// of a row can be processed together, with a single set of buckets: a multi-exponentiation
+// then costs Stride*c doublings instead of nbWindows*c, and Stride bucket reductions
+// instead of nbWindows.
+//
+// The table holds ceil(nbWindows/Stride) * NbPoints points: Stride = 1 uses the most memory
+// and is the fastest, Stride = nbWindows stores only the points, as MultiExp.
+type G1MultiExpTable struct {
+	C        uint64     // window size, in bits
+	Stride   uint64     // number of windows per row
+	NbPoints uint64     // number of points
+	Points   []G1Affine // Points[k*NbPoints+i] = 2^{k*Stride*C} * P_i
+}
+
+// NewG1MultiExpTable returns a table of precomputed multiples of points, using c-bit windows
+// and stride windows per row (see G1MultiExpTable). c must be in [3, 16], and stride positive;
+// a stride larger than the number of windows is set to the number of windows.
+func NewG1MultiExpTable(points []G1Affine, c, stride uint64) (*G1MultiExpTable, error) {
+	if c < minWindowSize || c > 16 {
+		return nil, ErrInvalidWindowSize
+	}
+	if stride == 0 {
+		return nil, ErrInvalidStride
+	}
+	if n := nbWindows(c); stride > n {
+		stride = n
+	}
+	nbRows := (nbWindows(c) + stride - 1) / stride
+	nbPoints := uint64(len(points))
+
+	table := &G1MultiExpTable{
+		C:        c,
+		Stride:   stride,
+		NbPoints: nbPoints,
+		Points:   make([]G1Affine, nbRows*nbPoints),
+	}
+	copy(table.Points, points)
+
+	// row k is row k-1 doubled Stride*c times
+	tmp := make([]G1Jac, nbPoints)
+	for k := uint64(1); k < nbRows; k++ {
+		prev := table.Points[(k-1)*nbPoints : k*nbPoints]
+		parallel.Execute(len(tmp), func(start, end int) {
+			for i := start; i < end; i++ {
+				tmp[i].FromAffine(&prev[i])
+				for j := uint64(0); j < stride*c; j++ {
+					tmp[i].DoubleAssign()
+				}
+			}
+		})
+		BatchJacobianToAffineG1Affine(tmp, table.Points[k*nbPoints:(k+1)*nbPoints])
+	}
+
+	return table, nil
+}
+
+// MultiExpPrecomputed computes the multi-exponentiation of the points of table by scalars
+// (in regular form, as for MultiExp).
+// optionally, takes as parameter a CPUSemaphore struct
+// enabling to set max number of cpus to use
+func (p *G1Affine) MultiExpPrecomputed(table *G1MultiExpTable, scalars []fr.Element, opts ...*CPUSemaphore) (*G1Affine, error) {
+	var _p G1Jac
+	if _, err := _p.MultiExpPrecomputed(table, scalars, opts...); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpPrecomputed computes the multi-exponentiation of the points of table by scalars
+// (in regular form, as for MultiExp).
+// optionally, takes as parameter a CPUSemaphore struct
+// enabling to set max number of cpus to use
+func (p *G1Jac) MultiExpPrecomputed(table *G1MultiExpTable, scalars []fr.Element, opts ...*CPUSemaphore) (*G1Jac, error) {
+	if err := checkMultiExpTable(table.C, table.Stride, table.NbPoints, len(table.Points)); err != nil {
+		return nil, err
+	}
+	if uint64(len(scalars)) != table.NbPoints {
+		return nil, ErrScalarsSizeMismatch
+	}
+
+	var opt *CPUSemaphore
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewCPUSemaphore(runtime.NumCPU())
+	}
+
+	// take all the cpus to ourselves
+	opt.lock.Lock()
+
+	scalars = partitionScalars(scalars, table.C, cap(opt.chCpus))
+
+	// each task processes a subset of the points, and the results are summed
+	nbTasks := cap(opt.chCpus)
+	if nbTasks > len(scalars) {
+		nbTasks = len(scalars)
+	}
+	if nbTasks < 1 {
+		nbTasks = 1
+	}
+	chRes := make(chan g1JacExtended, nbTasks)
+	chunkSize := (len(scalars) + nbTasks - 1) / nbTasks
+	for t := 0; t < nbTasks; t++ {
+		start, end := t*chunkSize, (t+1)*chunkSize
+		if end > len(scalars) {
+			end = len(scalars)
+		}
+		if start > end {
+			start = end
+		}
+		<-opt.chCpus // wait to have a cpu before scheduling
+		go func(start, end int) {
+			chRes <- msmPrecomputedG1Affine(table, scalars, start, end)
+			opt.chCpus <- struct{}{} // release token in the semaphore
+		}(start, end)
+	}
+
+	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
+	opt.lock.Unlock()
+
+	var _p g1JacExtended
+	_p.setInfinity()
+	for i := 0; i < nbTasks; i++ {
+		res := <-chRes
+		_p.add(&res)
+	}
+
+	return p.fromJacExtended(&_p), nil
+}
+
+// msmPrecomputedG1Affine computes the multi-exponentiation of the points [start, end) of
+// table by the partitioned scalars
+func msmPrecomputedG1Affine(table *G1MultiExpTable, scalars []fr.Element, start, end int) g1JacExtended {
+	c := table.C
+	msbWindow := uint64(1 << (c - 1))
+	n := nbWindows(c)
+	buckets := make([]g1JacExtended, 1<<(c-1))
+
+	var res g1JacExtended
+	res.setInfinity()
+
+	for pass := int(table.Stride) - 1; pass >= 0; pass-- {
+		for l := uint64(0); l < c && pass != int(table.Stride)-1; l++ {
+			res.double(&res)
+		}
+
+		for i := range buckets {
+			buckets[i].setInfinity()
+		}
+
+		// window k*Stride+pass of the scalars is processed with row k of the table
+		for k, chunk := uint64(0), uint64(pass); chunk < n; k, chunk = k+1, chunk+table.Stride {
+			s := newSelector(chunk, c)
+			points := table.Points[k*table.NbPoints : (k+1)*table.NbPoints]
+			for i := start; i < end; i++ {
+				bits := (scalars[i][s.index] & s.mask) >> s.shift
+				if s.multiWordSelect {
+					bits += (scalars[i][s.index+1] & s.maskHigh) << s.shiftHigh
+				}
+
+				if bits == 0 {
+					continue
+				}
+
+				// if msbWindow bit is set, we need to substract
+				if bits&msbWindow == 0 {
+					buckets[bits-1].addMixed(&points[i])
+				} else {
+					buckets[bits & ^msbWindow].subMixed(&points[i])
+				}
+			}
+		}
+
+		// reduce buckets into total
+		// total =  bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+		var runningSum, total g1JacExtended
+		runningSum.setInfinity()
+		total.setInfinity()
+		for k := len(buckets) - 1; k >= 0; k-- {
+			if !buckets[k].ZZ.IsZero() {
+				runningSum.add(&buckets[k])
+			}
+			total.add(&runningSum)
+		}
+		res.add(&total)
+	}
+
+	return res
+}
+
+// WriteTo writes the binary encoding of the table, with compressed points
+func (table *G1MultiExpTable) WriteTo(w io.Writer) (int64, error) {
+	return table.writeTo(NewEncoder(w))
+}
+
+// WriteRawTo writes the binary encoding of the table, with uncompressed points
+// (larger, but faster to decode)
+func (table *G1MultiExpTable) WriteRawTo(w io.Writer) (int64, error) {
+	return table.writeTo(NewEncoder(w, RawEncoding()))
+}
+
+func (table *G1MultiExpTable) writeTo(enc *Encoder) (int64, error) {
+	toEncode := []interface{}{
+		table.C,
+		table.Stride,
+		table.NbPoints,
+		table.Points,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a table written with WriteTo or WriteRawTo
+func (table *G1MultiExpTable) ReadFrom(r io.Reader) (int64, error) {
+	dec := NewDecoder(r)
+
+	toDecode := []interface{}{
+		&table.C,
+		&table.Stride,
+		&table.NbPoints,
+		&table.Points,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), checkMultiExpTable(table.C, table.Stride, table.NbPoints, len(table.Points))
+}
+
+// G2MultiExpTable stores precomputed multiples of a fixed set of points, to speed up
+// the multi-exponentiations with these points (for example, the points of an SRS).
+//
+// Scalars are partitioned in c-bit windows, which are grouped in rows of Stride windows.
+// The row k of the table stores the points multiplied by 2^{k*Stride*c}, such that the windows
+// of a row can be processed together, with a single set of buckets: a multi-exponentiation
+// then costs Stride*c doublings instead of nbWindows*c, and Stride bucket reductions
+// instead of nbWindows.
+//
+// The table holds ceil(nbWindows/Stride) * NbPoints points: Stride = 1 uses the most memory
+// and is the fastest, Stride = nbWindows stores only the points, as MultiExp.
+type G2MultiExpTable struct {
+	C        uint64     // window size, in bits
+	Stride   uint64     // number of windows per row
+	NbPoints uint64     // number of points
+	Points   []G2Affine // Points[k*NbPoints+i] = 2^{k*Stride*C} * P_i
+}
+
+// NewG2MultiExpTable returns a table of precomputed multiples of points, using c-bit windows
+// and stride windows per row (see G2MultiExpTable). c must be in [3, 16], and stride positive;
+// a stride larger than the number of windows is set to the number of windows.
+func NewG2MultiExpTable(points []G2Affine, c, stride uint64) (*G2MultiExpTable, error) {
+	if c < minWindowSize || c > 16 {
+		return nil, ErrInvalidWindowSize
+	}
+	if stride == 0 {
+		return nil, ErrInvalidStride
+	}
+	if n := nbWindows(c); stride > n {
+		stride = n
+	}
+	nbRows := (nbWindows(c) + stride - 1) / stride
+	nbPoints := uint64(len(points))
+
+	table := &G2MultiExpTable{
+		C:        c,
+		Stride:   stride,
+		NbPoints: nbPoints,
+		Points:   make([]G2Affine, nbRows*nbPoints),
+	}
+	copy(table.Points, points)
+
+	// row k is row k-1 doubled Stride*c times
+	tmp := make([]G2Jac, nbPoints)
+	for k := uint64(1); k < nbRows; k++ {
+		prev := table.Points[(k-1)*nbPoints : k*nbPoints]
+		parallel.Execute(len(tmp), func(start, end int) {
+			for i := start; i < end; i++ {
+				tmp[i].FromAffine(&prev[i])
+				for j := uint64(0); j < stride*c; j++ {
+					tmp[i].DoubleAssign()
+				}
+			}
+		})
+		BatchJacobianToAffineG2Affine(tmp, table.Points[k*nbPoints:(k+1)*nbPoints])
+	}
+
+	return table, nil
+}
+
+// MultiExpPrecomputed computes the multi-exponentiation of the points of table by scalars
+// (in regular form, as for MultiExp).
+// optionally, takes as parameter a CPUSemaphore struct
+// enabling to set max number of cpus to use
+func (p *G2Affine) MultiExpPrecomputed(table *G2MultiExpTable, scalars []fr.Element, opts ...*CPUSemaphore) (*G2Affine, error) {
+	var _p G2Jac
+	if _, err := _p.MultiExpPrecomputed(table, scalars, opts...); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpPrecomputed computes the multi-exponentiation of the points of table by scalars
+// (in regular form, as for MultiExp).
+// optionally, takes as parameter a CPUSemaphore struct
+// enabling to set max number of cpus to use
+func (p *G2Jac) MultiExpPrecomputed(table *G2MultiExpTable, scalars []fr.Element, opts ...*CPUSemaphore) (*G2Jac, error) {
+	if err := checkMultiExpTable(table.C, table.Stride, table.NbPoints, len(table.Points)); err != nil {
+		return nil, err
+	}
+	if uint64(len(scalars)) != table.NbPoints {
+		return nil, ErrScalarsSizeMismatch
+	}
+
+	var opt *CPUSemaphore
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewCPUSemaphore(runtime.NumCPU())
+	}
+
+	// take all the cpus to ourselves
+	opt.lock.Lock()
+
+	scalars = partitionScalars(scalars, table.C, cap(opt.chCpus))
+
+	// each task processes a subset of the points, and the results are summed
+	nbTasks := cap(opt.chCpus)
+	if nbTasks > len(scalars) {
+		nbTasks = len(scalars)
+	}
+	if nbTasks < 1 {
+		nbTasks = 1
+	}
+	chRes := make(chan g2JacExtended, nbTasks)
+	chunkSize := (len(scalars) + nbTasks - 1) / nbTasks
+	for t := 0; t < nbTasks; t++ {
+		start, end := t*chunkSize, (t+1)*chunkSize
+		if end > len(scalars) {
+			end = len(scalars)
+		}
+		if start > end {
+			start = end
+		}
+		<-opt.chCpus // wait to have a cpu before scheduling
+		go func(start, end int) {
+			chRes <- msmPrecomputedG2Affine(table, scalars, start, end)
+			opt.chCpus <- struct{}{} // release token in the semaphore
+		}(start, end)
+	}
+
+	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
+	opt.lock.Unlock()
+
+	var _p g2JacExtended
+	_p.setInfinity()
+	for i := 0; i < nbTasks; i++ {
+		res := <-chRes
+		_p.add(&res)
+	}
+
+	return p.fromJacExtended(&_p), nil
+}
+
+// msmPrecomputedG2Affine computes the multi-exponentiation of the points [start, end) of
+// table by the partitioned scalars
+func msmPrecomputedG2Affine(table *G2MultiExpTable, scalars []fr.Element, start, end int) g2JacExtended {
+	c := table.C
+	msbWindow := uint64(1 << (c - 1))
+	n := nbWindows(c)
+	buckets := make([]g2JacExtended, 1<<(c-1))
+
+	var res g2JacExtended
+	res.setInfinity()
+
+	for pass := int(table.Stride) - 1; pass >= 0; pass-- {
+		for l := uint64(0); l < c && pass != int(table.Stride)-1; l++ {
+			res.double(&res)
+		}
+
+		for i := range buckets {
+			buckets[i].setInfinity()
+		}
+
+		// window k*Stride+pass of the scalars is processed with row k of the table
+		for k, chunk := uint64(0), uint64(pass); chunk < n; k, chunk = k+1, chunk+table.Stride {
+			s := newSelector(chunk, c)
+			points := table.Points[k*table.NbPoints : (k+1)*table.NbPoints]
+			for i := start; i < end; i++ {
+				bits := (scalars[i][s.index] & s.mask) >> s.shift
+				if s.multiWordSelect {
+					bits += (scalars[i][s.index+1] & s.maskHigh) << s.shiftHigh
+				}
+
+				if bits == 0 {
+					continue
+				}
+
+				// if msbWindow bit is set, we need to substract
+				if bits&msbWindow == 0 {
+					buckets[bits-1].addMixed(&points[i])
+				} else {
+					buckets[bits & ^msbWindow].subMixed(&points[i])
+				}
+			}
+		}
+
+		// reduce buckets into total
+		// total =  bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+		var runningSum, total g2JacExtended
+		runningSum.setInfinity()
+		total.setInfinity()
+		for k := len(buckets) - 1; k >= 0; k-- {
+			if !buckets[k].ZZ.IsZero() {
+				runningSum.add(&buckets[k])
+			}
+			total.add(&runningSum)
+		}
+		res.add(&total)
+	}
+
+	return res
+}
+
+// WriteTo writes the binary encoding of the table, with compressed points
+func (table *G2MultiExpTable) WriteTo(w io.Writer) (int64, error) {
+	return table.writeTo(NewEncoder(w))
+}
+
+// WriteRawTo writes the binary encoding of the table, with uncompressed points
+// (larger, but faster to decode)
+func (table *G2MultiExpTable) WriteRawTo(w io.Writer) (int64, error) {
+	return table.writeTo(NewEncoder(w, RawEncoding()))
+}
+
+func (table *G2MultiExpTable) writeTo(enc *Encoder) (int64, error) {
+	toEncode := []interface{}{
+		table.C,
+		table.Stride,
+		table.NbPoints,
+		table.Points,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a table written with WriteTo or WriteRawTo
+func (table *G2MultiExpTable) ReadFrom(r io.Reader) (int64, error) {
+	dec := NewDecoder(r)
+
+	toDecode := []interface{}{
+		&table.C,
+		&table.Stride,
+		&table.NbPoints,
+		&table.Points,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), checkMultiExpTable(table.C, table.Stride, table.NbPoints, len(table.Points))
+}
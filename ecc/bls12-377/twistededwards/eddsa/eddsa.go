@@ -17,14 +17,17 @@
 package eddsa
 
 import (
+	"crypto/rand"
 	"crypto/subtle"
 	"errors"
 	"hash"
 	"io"
 	"math/big"
+	"sort"
 
 	"github.com/consensys/gnark-crypto/crypto/signature"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	"github.com/consensys/gnark-crypto/internal/ctmod"
 
 	"golang.org/x/crypto/blake2b"
 )
@@ -100,7 +103,7 @@ func GenerateKey(r io.Reader) (PrivateKey, error) {
 
 	var bscalar big.Int
 	bscalar.SetBytes(priv.scalar[:])
-	pub.A.ScalarMul(&c.Base, &bscalar)
+	pub.A.ScalarMulSecret(&c.Base, &bscalar)
 
 	priv.PublicKey = pub
 
@@ -154,7 +157,7 @@ func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error)
 	blindingFactorBigInt.SetBytes(blindingFactorBytes[:sizeFr])
 
 	// compute R = randScalar*Base
-	res.R.ScalarMul(&curveParams.Base, &blindingFactorBigInt)
+	res.R.ScalarMulSecret(&curveParams.Base, &blindingFactorBigInt)
 	if !res.R.IsOnCurve() {
 		return nil, errNotOnCurve
 	}
@@ -177,23 +180,17 @@ func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error)
 		return nil, err
 	}
 
-	var hramInt big.Int
 	hramBin := hFunc.Sum(nil)
-	hramInt.SetBytes(hramBin)
 
-	// Compute s = randScalarInt + H(R,A,M)*S
-	// going with big int to do ops mod curve order
-	var bscalar, bs big.Int
-	bscalar.SetBytes(privKey.scalar[:])
-	bs.Mul(&hramInt, &bscalar).
-		Add(&bs, &blindingFactorBigInt).
-		Mod(&bs, &curveParams.Order)
-	sb := bs.Bytes()
-	if len(sb) < sizeFr {
-		offset := make([]byte, sizeFr-len(sb))
-		sb = append(offset, sb...)
-	}
-	copy(res.S[:], sb[:])
+	// Compute s = randScalar + H(R,A,M)*S mod the order of the curve, with fixed width
+	// operations, in constant time with respect to randScalar and S
+	order := ctmod.NewModulus(&curveParams.Order)
+	n := order.Words()
+	randScalar := order.SetBytes(make([]uint64, n), blindingFactorBytes[:sizeFr])
+	scalar := order.SetBytes(make([]uint64, n), privKey.scalar[:])
+	hram := order.SetBytes(make([]uint64, n), hramBin)
+	s := order.MulAdd(make([]uint64, n), hram, scalar, randScalar)
+	ctmod.FillBytes(res.S[:], s)
 
 	return res.Bytes(), nil
 }
@@ -235,31 +232,175 @@ func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, err
 	hramBin := hFunc.Sum(nil)
 	hramInt.SetBytes(hramBin)
 
-	// lhs = cofactor*S*Base
-	var lhs twistededwards.PointAffine
-	var bCofactor, bs big.Int
-	curveParams.Cofactor.ToBigInt(&bCofactor)
+	// lhs = S*Base
+	var lhs twistededwards.PointExtended
+	var bs big.Int
 	bs.SetBytes(sig.S[:])
-	lhs.ScalarMul(&curveParams.Base, &bs).
-		ScalarMul(&lhs, &bCofactor)
+	lhs.FromAffine(&curveParams.Base)
+	lhs.ScalarMul(&lhs, &bs)
+
+	// rhs = R + H(R,A,M)*A
+	var rhs twistededwards.PointExtended
+	rhs.FromAffine(&pub.A)
+	rhs.ScalarMul(&rhs, &hramInt).
+		MixedAdd(&rhs, &sig.R)
+
+	// verifies that cofactor*(S*Base - (R + H(R,A,M)*A)) is the identity
+	lhs.Add(&lhs, rhs.Neg(&rhs)).
+		ClearCofactor(&lhs)
+	return lhs.IsZero(), nil
+}
 
-	if !lhs.IsOnCurve() {
-		return false, errNotOnCurve
+// BatchVerify verifies the signatures sigs[i] of the messages msgs[i] by the public keys pubs[i].
+// It returns true if all the signatures are valid; otherwise, it returns false and the indices
+// of the invalid signatures (including malformed ones).
+//
+// The signatures are checked at once, with a random linear combination of the verification
+// equations, cofactor*(Σ z_i*S_i*Base - Σ z_i*R_i - Σ z_i*H(R_i,A_i,M_i)*A_i) = 0, where z_i are
+// random 128-bit scalars. If this check fails, the batch is split in halves, which are checked
+// recursively to find the invalid signatures.
+func BatchVerify(pubs []PublicKey, sigs, msgs [][]byte, hFunc hash.Hash) (bool, []int, error) {
+	if len(pubs) != len(sigs) || len(pubs) != len(msgs) {
+		return false, nil, errBatchSizeMismatch
 	}
 
-	// rhs = cofactor*(R + H(R,A,M)*A)
-	var rhs twistededwards.PointAffine
-	rhs.ScalarMul(&pub.A, &hramInt).
-		Add(&rhs, &sig.R).
-		ScalarMul(&rhs, &bCofactor)
-	if !rhs.IsOnCurve() {
-		return false, errNotOnCurve
+	var invalid []int
+	entries := make([]batchEntry, 0, len(pubs))
+	for i := range pubs {
+		var e batchEntry
+		ok, err := e.set(i, &pubs[i], sigs[i], msgs[i], hFunc)
+		if err != nil {
+			return false, nil, err
+		}
+		if !ok {
+			invalid = append(invalid, i)
+			continue
+		}
+		entries = append(entries, e)
 	}
 
-	// verifies that cofactor*S*Base=cofactor*(R + H(R,A,M)*A)
-	if !lhs.X.Equal(&rhs.X) || !lhs.Y.Equal(&rhs.Y) {
+	res, err := findInvalid(entries)
+	if err != nil {
+		return false, nil, err
+	}
+	invalid = append(invalid, res...)
+	sort.Ints(invalid)
+
+	return len(invalid) == 0, invalid, nil
+}
+
+var errBatchSizeMismatch = errors.New("the numbers of public keys, signatures and messages must be equal")
+
+// batchEntry a deserialized signature, with its challenge H(R, A, M)
+type batchEntry struct {
+	index int
+	A, R  twistededwards.PointAffine
+	S     big.Int
+	hram  big.Int
+}
+
+// set deserializes the signature sigBin of message by pub, and computes its challenge.
+// It returns false if the signature or the public key is malformed.
+func (e *batchEntry) set(index int, pub *PublicKey, sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	e.index = index
+	if !pub.A.IsOnCurve() {
 		return false, nil
 	}
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, nil
+	}
+	e.A.Set(&pub.A)
+	e.R.Set(&sig.R)
+	e.S.SetBytes(sig.S[:])
+
+	// compute H(R, A, M), all parameters in data are in Montgomery form
+	sigRX := sig.R.X.Bytes()
+	sigRY := sig.R.Y.Bytes()
+	sigAX := pub.A.X.Bytes()
+	sigAY := pub.A.Y.Bytes()
+	sizeDataToHash := 4*sizeFr + len(message)
+	dataToHash := make([]byte, sizeDataToHash)
+	copy(dataToHash[:], sigRX[:])
+	copy(dataToHash[sizeFr:], sigRY[:])
+	copy(dataToHash[2*sizeFr:], sigAX[:])
+	copy(dataToHash[3*sizeFr:], sigAY[:])
+	copy(dataToHash[4*sizeFr:], message)
+	hFunc.Reset()
+	if _, err := hFunc.Write(dataToHash[:]); err != nil {
+		return false, err
+	}
+	e.hram.SetBytes(hFunc.Sum(nil))
 
 	return true, nil
 }
+
+// findInvalid returns the indices of the invalid signatures of entries, by checking
+// halves of the batch recursively when the batch check fails
+func findInvalid(entries []batchEntry) ([]int, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	ok, err := batchCheck(entries)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, nil
+	}
+	if len(entries) == 1 {
+		return []int{entries[0].index}, nil
+	}
+	left, err := findInvalid(entries[:len(entries)/2])
+	if err != nil {
+		return nil, err
+	}
+	right, err := findInvalid(entries[len(entries)/2:])
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// batchCheck returns true if cofactor*(Σ z_i*S_i*Base - Σ z_i*R_i - Σ z_i*H(R_i,A_i,M_i)*A_i)
+// is the identity, for random 128-bit z_i
+func batchCheck(entries []batchEntry) (bool, error) {
+	curveParams := twistededwards.GetEdwardsCurve()
+
+	points := make([]twistededwards.PointAffine, 0, 2*len(entries)+1)
+	scalars := make([]big.Int, 0, 2*len(entries)+1)
+
+	var zBytes [16]byte
+	var s, z, zh big.Int
+	for i := range entries {
+		if _, err := rand.Read(zBytes[:]); err != nil {
+			return false, err
+		}
+		z.SetBytes(zBytes[:])
+
+		// Σ z_i*S_i
+		s.Add(&s, zh.Mul(&z, &entries[i].S))
+
+		// -z_i*R_i
+		var negR twistededwards.PointAffine
+		negR.Neg(&entries[i].R)
+		points = append(points, negR)
+		scalars = append(scalars, *new(big.Int).Set(&z))
+
+		// -z_i*H(R_i,A_i,M_i)*A_i
+		var negA twistededwards.PointAffine
+		negA.Neg(&entries[i].A)
+		zh.Mul(&z, &entries[i].hram).Mod(&zh, &curveParams.Order)
+		points = append(points, negA)
+		scalars = append(scalars, *new(big.Int).Set(&zh))
+	}
+	s.Mod(&s, &curveParams.Order)
+	points = append(points, curveParams.Base)
+	scalars = append(scalars, s)
+
+	var res twistededwards.PointProj
+	res.MultiExp(points, scalars).
+		ClearCofactor(&res)
+
+	return res.IsZero(), nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func TestElligator2Map(t *testing.T) {
+
+	if elligator2Z.Legendre() != -1 {
+		t.Fatal("Z should not be a square")
+	}
+
+	// the denominator 1 + Z*u^2 of x1 never vanishes, as -1/Z is not a square (-1 is a square in fr)
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne)
+	if minusOne.Legendre() != 1 {
+		t.Fatal("-1 should be a square")
+	}
+
+	// u = 0 is mapped with x1 = -A
+	var zero fr.Element
+	inputs := []fr.Element{zero}
+	for i := 0; i < 20; i++ {
+		var u fr.Element
+		u.SetRandom()
+		inputs = append(inputs, u)
+	}
+
+	for _, u := range inputs {
+		p := elligator2Map(u)
+		if !p.IsOnCurve() {
+			t.Fatalf("elligator2Map(%s) is not on the curve", u.String())
+		}
+
+		// u and -u are mapped to the same point, as only u^2 is used to select x
+		var v fr.Element
+		v.Neg(&u)
+		if q := elligator2Map(v); !q.Equal(&p) {
+			t.Fatal("elligator2Map(u) and elligator2Map(-u) should be equal")
+		}
+	}
+}
+
+func TestHashToCurve(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+	for i := 0; i < 10; i++ {
+		msg := []byte(fmt.Sprintf("msg %d", i))
+
+		for _, hashToCurve := range []func(msg, dst []byte) (PointAffine, error){HashToCurve, EncodeToCurve} {
+			p, err := hashToCurve(msg, dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !p.IsOnCurve() {
+				t.Fatal("hash to curve output should be on the curve")
+			}
+
+			// the output is in the prime order subgroup
+			var pProj, q PointProj
+			pProj.FromAffine(&p)
+			if scalarMulNaive(&q, &pProj, &ed.Order); !q.IsZero() || pProj.IsZero() {
+				t.Fatal("hash to curve output should be in the prime order subgroup")
+			}
+
+			// the output is deterministic, and depends on the message and on the domain separation tag
+			p2, _ := hashToCurve(msg, dst)
+			p3, _ := hashToCurve(append(msg, 0), dst)
+			p4, _ := hashToCurve(msg, []byte("another DST"))
+			if !p.Equal(&p2) || p.Equal(&p3) || p.Equal(&p4) {
+				t.Fatal("hash to curve output should depend only on the message and on the domain separation tag")
+			}
+		}
+	}
+
+	// EncodeToCurve(msg) = MapToCurve(u), with u = hash_to_field(msg)
+	u, err := hashToFr([]byte("abc"), dst, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MapToCurve(u[0])
+	p, _ := EncodeToCurve([]byte("abc"), dst)
+	if !p.Equal(&expected) {
+		t.Fatal("EncodeToCurve and MapToCurve mismatch")
+	}
+
+	// HashToCurve(msg) = MapToCurve(u0) + MapToCurve(u1), with u0, u1 = hash_to_field(msg)
+	if u, err = hashToFr([]byte("abc"), dst, 2); err != nil {
+		t.Fatal(err)
+	}
+	q0, q1 := MapToCurve(u[0]), MapToCurve(u[1])
+	expected.Add(&q0, &q1)
+	p, _ = HashToCurve([]byte("abc"), dst)
+	if !p.Equal(&expected) {
+		t.Fatal("HashToCurve and MapToCurve mismatch")
+	}
+}
+
+// TestHashToCurveVectors checks hash_to_field, the Elligator 2 map and HashToCurve against known answers,
+// computed with an independent implementation of the generic steps of RFC 9380 (expand_message_xmd
+// with SHA-256, hash_to_field, map_to_curve_elligator2 on the Montgomery curve, the rational map of
+// appendix D and clear_cofactor). q0 and q1 are the outputs of the map before the cofactor is cleared.
+func TestHashToCurveVectors(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+	vectors := []struct {
+		msg       string
+		u0, u1    string
+		q0, q1, p [2]string
+	}{
+		{
+			msg: "",
+			u0:  "7921601632749152035293682241279399034540817470720409890152251837321820666975",
+			u1:  "5320029434271647680656014401632824364085264631667774178670788501807646984814",
+			q0:  [2]string{"5501589757827358248063054550793195999808082337134333394964080912132749115082", "3227092513118935541204917788112507968795122435279013983447834759182648531884"},
+			q1:  [2]string{"7555444007962766172650516529346571148157798633443887587996769159661987315942", "5292187706604933476142850317032756817186243216442156599353538792278279973871"},
+			p:   [2]string{"1011901120144017644678036746910109127817510792450280435202988981601560990820", "18154019585524183228631211948799710813567652953459631482542152168760967425"},
+		},
+		{
+			msg: "abc",
+			u0:  "1409809705378000873498046406884713259255879689374917197109807282644544894669",
+			u1:  "6932209688436374671392836633733831897505013675964024401280169167137611762441",
+			q0:  [2]string{"6082709325653791564088009733711639372129367667524846752036328513072347229622", "2475675514070269726744364315188276006929436203518611790351278239150959775331"},
+			q1:  [2]string{"1842534790588955901798220416833499990589237259020257927211054157530509352131", "1222271430353664507980549971059097741300404420929587955941830450060173436789"},
+			p:   [2]string{"3600596470705561277437872776481552943077448892665558011937755947913358321254", "2273052701861231849349123179261484736354173566504086298156356984973448645282"},
+		},
+		{
+			msg: "abcdef0123456789",
+			u0:  "6645844303304939341121007603508414943127319936244083825302532618665824566871",
+			u1:  "687955189223449263867349019614267696459762169782644523202129018429498860613",
+			q0:  [2]string{"7579448156584189847904131366572374129163387782643052432305052601379346673709", "2270417738903693911401170354017503901892971152722680511608706630093622472591"},
+			q1:  [2]string{"4979333236013720165692811476205931139185460319291268131478135716826443727066", "1195346993901962325203417933150023500184696817069245369411106145199914193502"},
+			p:   [2]string{"588234282370489074383711647205451821253423785125623427344504747552548273608", "1765635577087636909323325380426952878558966797570015869174237823137100057355"},
+		},
+	}
+
+	for _, v := range vectors {
+		u, err := hashToFr([]byte(v.msg), dst, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var u0, u1 fr.Element
+		u0.SetString(v.u0)
+		u1.SetString(v.u1)
+		if !u[0].Equal(&u0) || !u[1].Equal(&u1) {
+			t.Fatalf("hashToFr(%q) mismatch", v.msg)
+		}
+
+		var q0, q1, expected PointAffine
+		q0.X.SetString(v.q0[0])
+		q0.Y.SetString(v.q0[1])
+		q1.X.SetString(v.q1[0])
+		q1.Y.SetString(v.q1[1])
+		if p := elligator2Map(u0); !p.Equal(&q0) {
+			t.Fatalf("elligator2Map(u0) mismatch for msg %q", v.msg)
+		}
+		if p := elligator2Map(u1); !p.Equal(&q1) {
+			t.Fatalf("elligator2Map(u1) mismatch for msg %q", v.msg)
+		}
+
+		p, err := HashToCurve([]byte(v.msg), dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected.X.SetString(v.p[0])
+		expected.Y.SetString(v.p[1])
+		if !p.Equal(&expected) {
+			t.Fatalf("HashToCurve(%q) mismatch", v.msg)
+		}
+	}
+}
+
+func BenchmarkHashToCurve(b *testing.B) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	msg := []byte("abc")
+	b.Run("EncodeToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncodeToCurve(msg, dst)
+		}
+	})
+	b.Run("HashToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			HashToCurve(msg, dst)
+		}
+	})
+}
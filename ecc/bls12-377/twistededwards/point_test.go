@@ -0,0 +1,235 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func TestPointExtended(t *testing.T) {
+	ed := GetEdwardsCurve()
+
+	p1, p2 := randomPointAffine(), randomPointAffine()
+	var e1, e2, e PointExtended
+	e1.FromAffine(&p1)
+	e2.FromAffine(&p2)
+
+	// conversions
+	var proj PointProj
+	var p PointAffine
+	proj.FromAffine(&p1)
+	proj.Double(&proj)
+	e.FromProj(&proj)
+	p.Double(&p1)
+	if !e.Equal(e.FromAffine(&p)) {
+		t.Fatal("FromProj and FromAffine mismatch")
+	}
+	proj.FromExtended(&e)
+	var expected PointAffine
+	expected.FromProj(&proj)
+	if !p.Equal(&expected) || !p.Equal(expected.FromExtended(&e)) {
+		t.Fatal("FromExtended mismatch")
+	}
+
+	// group law
+	expected.Add(&p1, &p2)
+	p.FromExtended(e.Add(&e1, &e2))
+	if !p.Equal(&expected) {
+		t.Fatal("Add not consistent with affine addition")
+	}
+	p.FromExtended(e.MixedAdd(&e1, &p2))
+	if !p.Equal(&expected) {
+		t.Fatal("MixedAdd not consistent with affine addition")
+	}
+	expected.Double(&p1)
+	p.FromExtended(e.Double(&e1))
+	if !p.Equal(&expected) {
+		t.Fatal("Double not consistent with affine doubling")
+	}
+	p.FromExtended(e.Add(&e1, &e1))
+	if !p.Equal(&expected) {
+		t.Fatal("Add(p, p) not consistent with Double")
+	}
+	if !e.Add(&e1, e.Neg(&e1)).IsZero() {
+		t.Fatal("p + (-p) should be the identity")
+	}
+	if e1.IsZero() {
+		t.Fatal("IsZero should return false on a random point")
+	}
+
+	// the result's T coordinate is consistent: X*Y = T*Z
+	e.Add(&e1, &e2).Double(&e).MixedAdd(&e, &p1)
+	var xy, tz fr.Element
+	xy.Mul(&e.X, &e.Y)
+	tz.Mul(&e.T, &e.Z)
+	if !xy.Equal(&tz) {
+		t.Fatal("extended coordinates should satisfy X*Y = T*Z")
+	}
+
+	// scalar multiplication
+	var cofactor, order big.Int
+	ed.Cofactor.ToBigInt(&cofactor)
+	order.Mul(&ed.Order, &cofactor)
+
+	var s fr.Element
+	s.SetRandom()
+	var large big.Int
+	large.Lsh(&order, 8*fr.Bytes).Add(&large, big.NewInt(5))
+
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(23902374),
+		new(big.Int).Sub(&order, big.NewInt(1)),
+		s.ToBigIntRegular(new(big.Int)),
+		&large,
+	}
+
+	for _, scalar := range scalars {
+		var expected PointProj
+		expected.FromAffine(&p1)
+		scalarMulNaive(&expected, &expected, scalar)
+
+		var res, resSecret PointExtended
+		res.ScalarMul(&e1, scalar)
+		resSecret.ScalarMulSecret(&e1, scalar)
+		proj.FromExtended(&res)
+		if !proj.Equal(&expected) {
+			t.Fatalf("ScalarMul mismatch for scalar %s", scalar.String())
+		}
+		proj.FromExtended(&resSecret)
+		if !proj.Equal(&expected) {
+			t.Fatalf("ScalarMulSecret mismatch for scalar %s", scalar.String())
+		}
+	}
+}
+
+func TestIsInSubGroup(t *testing.T) {
+	ed := GetEdwardsCurve()
+
+	p := randomPointAffine()
+	if !ed.Base.IsInSubGroup() || !p.IsInSubGroup() {
+		t.Fatal("points of the prime order subgroup should pass the subgroup check")
+	}
+
+	// (0, -1) is a point of order 2
+	var torsion PointAffine
+	torsion.Y.SetOne().Neg(&torsion.Y)
+	if !torsion.IsOnCurve() || torsion.IsInSubGroup() {
+		t.Fatal("(0, -1) should be on the curve but not in the prime order subgroup")
+	}
+	p.Add(&p, &torsion)
+	if !p.IsOnCurve() || p.IsInSubGroup() {
+		t.Fatal("a point with a torsion component should not be in the prime order subgroup")
+	}
+
+	// a point not on the curve
+	p = ed.Base
+	p.X.Double(&p.X)
+	if p.IsInSubGroup() {
+		t.Fatal("a point not on the curve should not be in the prime order subgroup")
+	}
+}
+
+func TestScalarMulTorsion(t *testing.T) {
+	// p1 = Base + (0, -1) is on the curve, but not in the prime order subgroup:
+	// the scalar multiplication must not reduce the scalar modulo the order of the subgroup
+	ed := GetEdwardsCurve()
+	var torsion, p1 PointAffine
+	torsion.Y.SetOne().Neg(&torsion.Y)
+	p1.Add(&ed.Base, &torsion)
+
+	var e1, res PointExtended
+	var expected, proj PointProj
+	e1.FromAffine(&p1)
+	var s fr.Element
+	var scalar big.Int
+	for i := 0; i < 20; i++ {
+		s.SetRandom()
+		s.ToBigIntRegular(&scalar)
+		expected.FromAffine(&p1)
+		scalarMulNaive(&expected, &expected, &scalar)
+
+		res.ScalarMul(&e1, &scalar)
+		proj.FromExtended(&res)
+		if !proj.Equal(&expected) {
+			t.Fatalf("ScalarMul mismatch for p + (0, -1) and scalar %s", scalar.String())
+		}
+		res.ScalarMulSecret(&e1, &scalar)
+		proj.FromExtended(&res)
+		if !proj.Equal(&expected) {
+			t.Fatalf("ScalarMulSecret mismatch for p + (0, -1) and scalar %s", scalar.String())
+		}
+	}
+}
+
+func BenchmarkScalarMul(b *testing.B) {
+	p1 := randomPointAffine()
+	var s fr.Element
+	s.SetRandom()
+	var scalar big.Int
+	s.ToBigIntRegular(&scalar)
+
+	b.Run("projective double-and-add", func(b *testing.B) {
+		var p PointProj
+		p.FromAffine(&p1)
+		for j := 0; j < b.N; j++ {
+			scalarMulNaive(&p, &p, &scalar)
+		}
+	})
+	b.Run("extended window", func(b *testing.B) {
+		var p PointExtended
+		p.FromAffine(&p1)
+		for j := 0; j < b.N; j++ {
+			p.ScalarMul(&p, &scalar)
+		}
+	})
+	b.Run("extended window constant time", func(b *testing.B) {
+		var p PointExtended
+		p.FromAffine(&p1)
+		for j := 0; j < b.N; j++ {
+			p.ScalarMulSecret(&p, &scalar)
+		}
+	})
+}
+
+// randomPointAffine returns a random point of the prime order subgroup
+func randomPointAffine() PointAffine {
+	ed := GetEdwardsCurve()
+	var s fr.Element
+	var bs big.Int
+	s.SetRandom()
+	var p PointAffine
+	p.ScalarMul(&ed.Base, s.ToBigIntRegular(&bs))
+	return p
+}
+
+// scalarMulNaive sets p to scalar*p1 with the double-and-add method in projective coordinates
+func scalarMulNaive(p, p1 *PointProj, scalar *big.Int) *PointProj {
+	var res PointProj
+	res.setInfinity()
+	for i := scalar.BitLen() - 1; i >= 0; i-- {
+		res.Double(&res)
+		if scalar.Bit(i) == 1 {
+			res.Add(&res, p1)
+		}
+	}
+	return p.Set(&res)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © 2021 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bandersnatch implements Bandersnatch, a twisted Edwards curve defined on BLS12-381's Fr,
+// with an efficient degree 2 endomorphism.
+// cf https://eprint.iacr.org/2021/1152.pdf
+package bandersnatch
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// CurveParams curve parameters: ax^2 + y^2 = 1 + d*x^2*y^2
+type CurveParams struct {
+	A, D     fr.Element // in Montgomery form
+	Cofactor fr.Element // not in Montgomery form
+	Order    big.Int
+	Base     PointAffine
+}
+
+var edwards CurveParams
+
+// Parameters useful for the GLV scalar multiplication. endo defines the endomorphism phi
+// (see endomorphism.go). lambdaGLV is the eigenvalue of phi restricted to the prime order subgroup.
+var endo [2]fr.Element
+var lambdaGLV big.Int
+
+// glvBasis stores R-linearly independant vectors (a,b), (c,d)
+// in ker((u,v)->u+vlambda[r]), and their determinant
+var glvBasis ecc.Lattice
+
+// GetEdwardsCurve returns the Bandersnatch curve on BLS12-381's Fr
+func GetEdwardsCurve() CurveParams {
+
+	// copy to keep Order private
+	var res CurveParams
+
+	res.A.Set(&edwards.A)
+	res.D.Set(&edwards.D)
+	res.Cofactor.Set(&edwards.Cofactor)
+	res.Order.Set(&edwards.Order)
+	res.Base.Set(&edwards.Base)
+
+	return res
+}
+
+func init() {
+
+	edwards.A.SetString("52435875175126190479447740508185965837690552500527637822603658699938581184508") // -5
+	edwards.D.SetString("45022363124591815672509500913686876175488063829319466900776701791074614335719") // 138827208126141220649022263972958607803/171449701953573178309673572579671231137
+	edwards.Cofactor.SetUint64(4).FromMont()
+	edwards.Order.SetString("13108968793781547619861935127046491459309155893440570251786403306729687672801", 10)
+
+	edwards.Base.X.SetString("18886178867200960497001835917649091219057080094937609519140440539760939937304")
+	edwards.Base.Y.SetString("19188667384257783945677642223292697773471335439753913231509108946878080696678")
+
+	endo[0].SetString("37446463827641770816307242315180085052603635617490163568005256780843403514036")
+	endo[1].SetString("49199877423542878313146170939139662862850515542392585932876811575731455068989")
+	lambdaGLV.SetString("8913659658109529928382530854484400854125314752504019737736543920008458395397", 10) // sqrt(-2) mod Order
+	ecc.PrecomputeLattice(&edwards.Order, &lambdaGLV, &glvBasis)
+}
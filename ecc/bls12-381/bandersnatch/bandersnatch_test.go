@@ -0,0 +1,214 @@
+package bandersnatch
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestCurveParams(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+	if !ed.Base.IsOnCurve() {
+		t.Fatal("base point should be on the curve")
+	}
+
+	// the base point generates the prime order subgroup
+	var base, p PointProj
+	base.FromAffine(&ed.Base)
+	if scalarMulNaive(&p, &base, &ed.Order); !p.IsZero() {
+		t.Fatal("order*Base should be the identity")
+	}
+
+	// a and d are not squares, the addition law is complete on the prime order subgroup
+	if ed.A.Legendre() != -1 || ed.D.Legendre() != -1 {
+		t.Fatal("a and d should not be squares")
+	}
+
+	// lambdaGLV^2 = -2 mod Order
+	var l big.Int
+	l.Mul(&lambdaGLV, &lambdaGLV).Add(&l, big.NewInt(2)).Mod(&l, &ed.Order)
+	if l.Sign() != 0 {
+		t.Fatal("lambdaGLV should be a square root of -2")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+
+	var point, unmarshalPoint PointAffine
+	point.Set(&edwards.Base)
+	for i := 0; i < 20; i++ {
+		b := point.Marshal()
+		unmarshalPoint.Unmarshal(b)
+		if !point.Equal(&unmarshalPoint) {
+			t.Fatal("error unmarshal(marshal(point))")
+		}
+		point.Add(&point, &edwards.Base)
+	}
+}
+
+func TestAdd(t *testing.T) {
+
+	var p1, p2 PointAffine
+
+	// 5*Base
+	p1.X.SetString("47400841077456466525468168890229032179205558035369210713779001562118013758432")
+	p1.Y.SetString("35472581266748007134508686317722958742961809248534515289421525469382014681656")
+
+	// 7*Base
+	p2.X.SetString("21738734698107447787529115687341612873799903974742174550596292229208743993884")
+	p2.Y.SetString("13392568372097603925002652851880122460788289799270374801847770536367382438885")
+
+	// 12*Base
+	var expected PointAffine
+	expected.X.SetString("15181888741932980430043714666948619163544774193655143886595493943880632105321")
+	expected.Y.SetString("43928147806114152181108130178821096236650754764077071212022458166319538544580")
+
+	var p PointAffine
+	if !p.Add(&p1, &p2).Equal(&expected) {
+		t.Fatal("wrong affine addition")
+	}
+
+	var p1proj, p2proj PointProj
+	p1proj.FromAffine(&p1)
+	p2proj.FromAffine(&p2)
+	p.FromProj(p1proj.Add(&p1proj, &p2proj))
+	if !p.Equal(&expected) {
+		t.Fatal("wrong projective addition")
+	}
+
+	var p1ext, p2ext PointExtended
+	p1ext.FromAffine(&p1)
+	p2ext.FromAffine(&p2)
+	p.FromExtended(p1ext.Add(&p1ext, &p2ext))
+	if !p.Equal(&expected) {
+		t.Fatal("wrong extended addition")
+	}
+
+	// 10*Base
+	expected.X.SetString("1732223056238281071846425580328588139787690307191567119940629880441537293731")
+	expected.Y.SetString("19537476461276143909621088169811326602722958640360412862895502721105019895898")
+	if !p.Double(&p1).Equal(&expected) {
+		t.Fatal("wrong affine doubling")
+	}
+	p1ext.FromAffine(&p1)
+	p.FromExtended(p1ext.Double(&p1ext))
+	if !p.Equal(&expected) {
+		t.Fatal("wrong extended doubling")
+	}
+}
+
+func TestScalarMul(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+
+	var expected PointAffine
+	expected.X.SetString("28999347810225914575940661798059950360805201109763086376525047477131326858536")
+	expected.Y.SetString("48899627862488200914761291978390748754227221620694367153422022027302110496800")
+
+	var scalar big.Int
+	scalar.SetString("1606938044258990275541962092341162602522215339461694069869266", 10)
+
+	var p PointAffine
+	if !p.ScalarMul(&ed.Base, &scalar).Equal(&expected) {
+		t.Fatal("wrong scalar multiplication")
+	}
+	if !p.ScalarMulSecret(&ed.Base, &scalar).Equal(&expected) {
+		t.Fatal("wrong constant time scalar multiplication")
+	}
+}
+
+func TestPhi(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+
+	var base, p, expected PointExtended
+	base.FromAffine(&ed.Base)
+	p.phi(&base)
+	expected.scalarMulWindowed(&base, &lambdaGLV)
+	if !p.Equal(&expected) {
+		t.Fatal("phi should be the multiplication by lambdaGLV on the prime order subgroup")
+	}
+
+	// (0, -1) is an exceptional point of phi
+	var q PointAffine
+	q.Y.SetOne().Neg(&q.Y)
+	var qExt PointExtended
+	qExt.FromAffine(&q)
+	if !p.phi(&qExt).Z.IsZero() {
+		t.Fatal("phi((0, -1)) should be exceptional")
+	}
+}
+
+func TestScalarMulGLV(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+
+	var s fr.Element
+	s.SetRandom()
+	var large big.Int
+	large.Lsh(&ed.Order, 8*fr.Bytes).Add(&large, big.NewInt(5))
+
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(23902374),
+		new(big.Int).Set(&lambdaGLV),
+		new(big.Int).Sub(&ed.Order, big.NewInt(1)),
+		s.ToBigIntRegular(new(big.Int)),
+		&large,
+		big.NewInt(-42),
+	}
+
+	p1 := randomPointAffine()
+	var e1 PointExtended
+	e1.FromAffine(&p1)
+
+	for _, scalar := range scalars {
+		var expected, p PointExtended
+		var s big.Int
+		s.Mod(scalar, &ed.Order)
+		expected.scalarMulWindowed(&e1, &s)
+		p.ScalarMulGLV(&e1, scalar)
+		if !p.Equal(&expected) {
+			t.Fatalf("ScalarMulGLV and scalarMulWindowed mismatch for scalar %s", scalar.String())
+		}
+
+		var expectedAffine, pAffine PointAffine
+		expectedAffine.ScalarMul(&p1, &s)
+		if !pAffine.ScalarMulGLV(&p1, scalar).Equal(&expectedAffine) {
+			t.Fatalf("PointAffine.ScalarMulGLV and ScalarMul mismatch for scalar %s", scalar.String())
+		}
+	}
+
+	// the exceptional points of phi fall back to scalarMulWindowed
+	var q PointAffine
+	q.Y.SetOne().Neg(&q.Y)
+	var qExt, p PointExtended
+	qExt.FromAffine(&q)
+	if !p.ScalarMulGLV(&qExt, big.NewInt(3)).Equal(&qExt) {
+		t.Fatal("3*(0, -1) should be (0, -1)")
+	}
+}
+
+func BenchmarkScalarMulGLV(b *testing.B) {
+	p1 := randomPointAffine()
+	var s fr.Element
+	s.SetRandom()
+	var scalar big.Int
+	s.ToBigIntRegular(&scalar)
+
+	var p PointExtended
+	p.FromAffine(&p1)
+	b.Run("window", func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			p.scalarMulWindowed(&p, &scalar)
+		}
+	})
+	b.Run("GLV", func(b *testing.B) {
+		for j := 0; j < b.N; j++ {
+			p.ScalarMulGLV(&p, &scalar)
+		}
+	})
+}
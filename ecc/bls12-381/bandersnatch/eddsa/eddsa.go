@@ -0,0 +1,406 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/crypto/signature"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/internal/ctmod"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+var errNotOnCurve = errors.New("point not on curve")
+
+const (
+	sizeFr         = 32
+	sizePublicKey  = sizeFr
+	sizeSignature  = 2 * sizeFr
+	sizePrivateKey = 2*sizeFr + 32
+)
+
+// PublicKey eddsa signature object
+// cf https://en.wikipedia.org/wiki/EdDSA for notation
+type PublicKey struct {
+	A bandersnatch.PointAffine
+}
+
+// PrivateKey private key of an eddsa instance
+type PrivateKey struct {
+	PublicKey PublicKey    // copy of the associated public key
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+	randSrc   [32]byte     // source
+}
+
+// Signature represents an eddsa signature
+// cf https://en.wikipedia.org/wiki/EdDSA for notation
+type Signature struct {
+	R bandersnatch.PointAffine
+	S [sizeFr]byte
+}
+
+func init() {
+	signature.Register(signature.EDDSA_BLS12_381_BANDERSNATCH, GenerateKeyInterfaces)
+}
+
+// GenerateKey generates a public and private key pair.
+func GenerateKey(r io.Reader) (PrivateKey, error) {
+
+	c := bandersnatch.GetEdwardsCurve()
+
+	var pub PublicKey
+	var priv PrivateKey
+
+	// hash(h) = private_key || random_source, on 32 bytes each
+	seed := make([]byte, 32)
+	_, err := r.Read(seed)
+	if err != nil {
+		return priv, err
+	}
+	h := blake2b.Sum512(seed[:])
+	for i := 0; i < 32; i++ {
+		priv.randSrc[i] = h[i+32]
+	}
+
+	// prune the key
+	// https://tools.ietf.org/html/rfc8032#section-5.1.5, key generation
+
+	h[0] &= 0xF8
+	h[31] &= 0x7F
+	h[31] |= 0x40
+
+	// reverse first bytes because setBytes interpret stream as big endian
+	// but in eddsa specs s is the first 32 bytes in little endian
+	for i, j := 0, sizeFr; i < j; i, j = i+1, j-1 {
+
+		h[i], h[j] = h[j], h[i]
+
+	}
+
+	copy(priv.scalar[:], h[:sizeFr])
+
+	var bscalar big.Int
+	bscalar.SetBytes(priv.scalar[:])
+	pub.A.ScalarMulSecret(&c.Base, &bscalar)
+
+	priv.PublicKey = pub
+
+	return priv, nil
+}
+
+// GenerateKeyInterfaces generate interfaces for the public/private key.
+// This purpose of this function is to be registered in the list of signature schemes.
+func GenerateKeyInterfaces(r io.Reader) (signature.Signer, error) {
+	priv, err := GenerateKey(r)
+	return &priv, err
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(other signature.PublicKey) bool {
+	bpk := pub.Bytes()
+	bother := other.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bother) == 1
+}
+
+// Public returns the public key associated to the private key.
+// From Signer interface defined in gnark/crypto/signature.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// Sign sign a message
+// Pure Eddsa version (see https://tools.ietf.org/html/rfc8032#page-8)
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+
+	curveParams := bandersnatch.GetEdwardsCurve()
+
+	var res Signature
+
+	// blinding factor for the private key
+	// blindingFactorBigInt must be the same size as the private key,
+	// blindingFactorBigInt = h(randomness_source||message)[:sizeFr]
+	var blindingFactorBigInt big.Int
+
+	// randSrc = privKey.randSrc || msg (-> message = MSB message .. LSB message)
+	randSrc := make([]byte, 32+len(message))
+	for i, v := range privKey.randSrc {
+		randSrc[i] = v
+	}
+	copy(randSrc[32:], message)
+
+	// randBytes = H(randSrc)
+	blindingFactorBytes := blake2b.Sum512(randSrc[:]) // TODO ensures that the hash used to build the key and the one used here is the same
+	blindingFactorBigInt.SetBytes(blindingFactorBytes[:sizeFr])
+
+	// compute R = randScalar*Base
+	res.R.ScalarMulSecret(&curveParams.Base, &blindingFactorBigInt)
+	if !res.R.IsOnCurve() {
+		return nil, errNotOnCurve
+	}
+
+	// compute H(R, A, M), all parameters in data are in Montgomery form
+	resRX := res.R.X.Bytes()
+	resRY := res.R.Y.Bytes()
+	resAX := privKey.PublicKey.A.X.Bytes()
+	resAY := privKey.PublicKey.A.Y.Bytes()
+	sizeDataToHash := 4*sizeFr + len(message)
+	dataToHash := make([]byte, sizeDataToHash)
+	copy(dataToHash[:], resRX[:])
+	copy(dataToHash[sizeFr:], resRY[:])
+	copy(dataToHash[2*sizeFr:], resAX[:])
+	copy(dataToHash[3*sizeFr:], resAY[:])
+	copy(dataToHash[4*sizeFr:], message)
+	hFunc.Reset()
+	_, err := hFunc.Write(dataToHash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	hramBin := hFunc.Sum(nil)
+
+	// Compute s = randScalar + H(R,A,M)*S mod the order of the curve, with fixed width
+	// operations, in constant time with respect to randScalar and S
+	order := ctmod.NewModulus(&curveParams.Order)
+	n := order.Words()
+	randScalar := order.SetBytes(make([]uint64, n), blindingFactorBytes[:sizeFr])
+	scalar := order.SetBytes(make([]uint64, n), privKey.scalar[:])
+	hram := order.SetBytes(make([]uint64, n), hramBin)
+	s := order.MulAdd(make([]uint64, n), hram, scalar, randScalar)
+	ctmod.FillBytes(res.S[:], s)
+
+	return res.Bytes(), nil
+}
+
+// Verify verifies an eddsa signature
+func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+
+	curveParams := bandersnatch.GetEdwardsCurve()
+
+	// verify that pubKey and R are on the curve
+	if !pub.A.IsOnCurve() {
+		return false, errNotOnCurve
+	}
+
+	// Deserialize the signature
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+
+	// compute H(R, A, M), all parameters in data are in Montgomery form
+	sigRX := sig.R.X.Bytes()
+	sigRY := sig.R.Y.Bytes()
+	sigAX := pub.A.X.Bytes()
+	sigAY := pub.A.Y.Bytes()
+	sizeDataToHash := 4*sizeFr + len(message)
+	dataToHash := make([]byte, sizeDataToHash)
+	copy(dataToHash[:], sigRX[:])
+	copy(dataToHash[sizeFr:], sigRY[:])
+	copy(dataToHash[2*sizeFr:], sigAX[:])
+	copy(dataToHash[3*sizeFr:], sigAY[:])
+	copy(dataToHash[4*sizeFr:], message)
+	hFunc.Reset()
+	if _, err := hFunc.Write(dataToHash[:]); err != nil {
+		return false, err
+	}
+
+	var hramInt big.Int
+	hramBin := hFunc.Sum(nil)
+	hramInt.SetBytes(hramBin)
+
+	// lhs = S*Base
+	var lhs bandersnatch.PointExtended
+	var bs big.Int
+	bs.SetBytes(sig.S[:])
+	lhs.FromAffine(&curveParams.Base)
+	lhs.ScalarMulGLV(&lhs, &bs) // Base is in the prime order subgroup
+
+	// rhs = R + H(R,A,M)*A
+	var rhs bandersnatch.PointExtended
+	rhs.FromAffine(&pub.A)
+	rhs.ScalarMul(&rhs, &hramInt).
+		MixedAdd(&rhs, &sig.R)
+
+	// verifies that cofactor*(S*Base - (R + H(R,A,M)*A)) is the identity
+	lhs.Add(&lhs, rhs.Neg(&rhs)).
+		ClearCofactor(&lhs)
+	return lhs.IsZero(), nil
+}
+
+// BatchVerify verifies the signatures sigs[i] of the messages msgs[i] by the public keys pubs[i].
+// It returns true if all the signatures are valid; otherwise, it returns false and the indices
+// of the invalid signatures (including malformed ones).
+//
+// The signatures are checked at once, with a random linear combination of the verification
+// equations, cofactor*(Σ z_i*S_i*Base - Σ z_i*R_i - Σ z_i*H(R_i,A_i,M_i)*A_i) = 0, where z_i are
+// random 128-bit scalars. If this check fails, the batch is split in halves, which are checked
+// recursively to find the invalid signatures.
+func BatchVerify(pubs []PublicKey, sigs, msgs [][]byte, hFunc hash.Hash) (bool, []int, error) {
+	if len(pubs) != len(sigs) || len(pubs) != len(msgs) {
+		return false, nil, errBatchSizeMismatch
+	}
+
+	var invalid []int
+	entries := make([]batchEntry, 0, len(pubs))
+	for i := range pubs {
+		var e batchEntry
+		ok, err := e.set(i, &pubs[i], sigs[i], msgs[i], hFunc)
+		if err != nil {
+			return false, nil, err
+		}
+		if !ok {
+			invalid = append(invalid, i)
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	res, err := findInvalid(entries)
+	if err != nil {
+		return false, nil, err
+	}
+	invalid = append(invalid, res...)
+	sort.Ints(invalid)
+
+	return len(invalid) == 0, invalid, nil
+}
+
+var errBatchSizeMismatch = errors.New("the numbers of public keys, signatures and messages must be equal")
+
+// batchEntry a deserialized signature, with its challenge H(R, A, M)
+type batchEntry struct {
+	index int
+	A, R  bandersnatch.PointAffine
+	S     big.Int
+	hram  big.Int
+}
+
+// set deserializes the signature sigBin of message by pub, and computes its challenge.
+// It returns false if the signature or the public key is malformed.
+func (e *batchEntry) set(index int, pub *PublicKey, sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	e.index = index
+	if !pub.A.IsOnCurve() {
+		return false, nil
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, nil
+	}
+	e.A.Set(&pub.A)
+	e.R.Set(&sig.R)
+	e.S.SetBytes(sig.S[:])
+
+	// compute H(R, A, M), all parameters in data are in Montgomery form
+	sigRX := sig.R.X.Bytes()
+	sigRY := sig.R.Y.Bytes()
+	sigAX := pub.A.X.Bytes()
+	sigAY := pub.A.Y.Bytes()
+	sizeDataToHash := 4*sizeFr + len(message)
+	dataToHash := make([]byte, sizeDataToHash)
+	copy(dataToHash[:], sigRX[:])
+	copy(dataToHash[sizeFr:], sigRY[:])
+	copy(dataToHash[2*sizeFr:], sigAX[:])
+	copy(dataToHash[3*sizeFr:], sigAY[:])
+	copy(dataToHash[4*sizeFr:], message)
+	hFunc.Reset()
+	if _, err := hFunc.Write(dataToHash[:]); err != nil {
+		return false, err
+	}
+	e.hram.SetBytes(hFunc.Sum(nil))
+
+	return true, nil
+}
+
+// findInvalid returns the indices of the invalid signatures of entries, by checking
+// halves of the batch recursively when the batch check fails
+func findInvalid(entries []batchEntry) ([]int, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	ok, err := batchCheck(entries)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return nil, nil
+	}
+	if len(entries) == 1 {
+		return []int{entries[0].index}, nil
+	}
+	left, err := findInvalid(entries[:len(entries)/2])
+	if err != nil {
+		return nil, err
+	}
+	right, err := findInvalid(entries[len(entries)/2:])
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+// batchCheck returns true if cofactor*(Σ z_i*S_i*Base - Σ z_i*R_i - Σ z_i*H(R_i,A_i,M_i)*A_i)
+// is the identity, for random 128-bit z_i
+func batchCheck(entries []batchEntry) (bool, error) {
+	curveParams := bandersnatch.GetEdwardsCurve()
+
+	points := make([]bandersnatch.PointAffine, 0, 2*len(entries)+1)
+	scalars := make([]big.Int, 0, 2*len(entries)+1)
+
+	var zBytes [16]byte
+	var s, z, zh big.Int
+	for i := range entries {
+		if _, err := rand.Read(zBytes[:]); err != nil {
+			return false, err
+		}
+		z.SetBytes(zBytes[:])
+
+		// Σ z_i*S_i
+		s.Add(&s, zh.Mul(&z, &entries[i].S))
+
+		// -z_i*R_i
+		var negR bandersnatch.PointAffine
+		negR.Neg(&entries[i].R)
+		points = append(points, negR)
+		scalars = append(scalars, *new(big.Int).Set(&z))
+
+		// -z_i*H(R_i,A_i,M_i)*A_i
+		var negA bandersnatch.PointAffine
+		negA.Neg(&entries[i].A)
+		zh.Mul(&z, &entries[i].hram).Mod(&zh, &curveParams.Order)
+		points = append(points, negA)
+		scalars = append(scalars, *new(big.Int).Set(&zh))
+	}
+	s.Mod(&s, &curveParams.Order)
+	points = append(points, curveParams.Base)
+	scalars = append(scalars, s)
+
+	var res bandersnatch.PointProj
+	res.MultiExp(points, scalars).
+		ClearCofactor(&res)
+
+	return res.IsZero(), nil
+}
@@ -0,0 +1,291 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"crypto/sha256"
+	gohash "hash"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/crypto/hash"
+	"github.com/consensys/gnark-crypto/crypto/signature"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestSerialization(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src)
+
+	privKey1, err := signature.EDDSA_BLS12_381_BANDERSNATCH.New(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey1 := privKey1.Public()
+
+	privKey2, err := signature.EDDSA_BLS12_381_BANDERSNATCH.New(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey2 := privKey2.Public()
+
+	pubKeyBin1 := pubKey1.Bytes()
+	pubKey2.SetBytes(pubKeyBin1)
+	pubKeyBin2 := pubKey2.Bytes()
+	if len(pubKeyBin1) != len(pubKeyBin2) {
+		t.Fatal("Inconistent size")
+	}
+	for i := 0; i < len(pubKeyBin1); i++ {
+		if pubKeyBin1[i] != pubKeyBin2[i] {
+			t.Fatal("Error serialize(deserialize(.))")
+		}
+	}
+
+	privKeyBin1 := privKey1.Bytes()
+	privKey2.SetBytes(privKeyBin1)
+	privKeyBin2 := privKey2.Bytes()
+	if len(privKeyBin1) != len(privKeyBin2) {
+		t.Fatal("Inconistent size")
+	}
+	for i := 0; i < len(privKeyBin1); i++ {
+		if privKeyBin1[i] != privKeyBin2[i] {
+			t.Fatal("Error serialize(deserialize(.))")
+		}
+	}
+}
+
+func TestEddsaMIMC(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src)
+
+	// create eddsa obj and sign a message
+	privKey, err := signature.EDDSA_BLS12_381_BANDERSNATCH.New(r)
+	if err != nil {
+		t.Fatal(nil)
+	}
+	pubKey := privKey.Public()
+	hFunc := hash.MIMC_BLS12_381.New("seed")
+
+	var frMsg fr.Element
+	frMsg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035978")
+	msgBin := frMsg.Bytes()
+	signature, err := privKey.Sign(msgBin[:], hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verifies correct msg
+	res, err := pubKey.Verify(signature, msgBin[:], hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verifiy correct signature should return true")
+	}
+
+	// verifies wrong msg
+	frMsg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035979")
+	msgBin = frMsg.Bytes()
+	res, err = pubKey.Verify(signature, msgBin[:], hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Fatal("Verfiy wrong signature should be false")
+	}
+
+}
+
+func TestEddsaSHA256(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src)
+
+	hFunc := sha256.New()
+
+	// create eddsa obj and sign a message
+	// create eddsa obj and sign a message
+
+	privKey, err := signature.EDDSA_BLS12_381_BANDERSNATCH.New(r)
+	pubKey := privKey.Public()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := privKey.Sign([]byte("message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verifies correct msg
+	res, err := pubKey.Verify(signature, []byte("message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verifiy correct signature should return true")
+	}
+
+	// verifies wrong msg
+	res, err = pubKey.Verify(signature, []byte("wrong_message"), hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Fatal("Verfiy wrong signature should be false")
+	}
+
+}
+
+// newBatch returns n key pairs, messages and signatures
+func newBatch(tb testing.TB, n int, hFunc gohash.Hash) ([]PublicKey, [][]byte, [][]byte) {
+	r := rand.New(rand.NewSource(0))
+	pubs := make([]PublicKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		pubs[i] = privKey.PublicKey
+		var frMsg fr.Element
+		frMsg.SetUint64(uint64(i))
+		msgBin := frMsg.Bytes()
+		msgs[i] = msgBin[:]
+		if sigs[i], err = privKey.Sign(msgs[i], hFunc); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return pubs, sigs, msgs
+}
+
+func TestBatchVerify(t *testing.T) {
+
+	hFunc := hash.MIMC_BLS12_381.New("seed")
+	const n = 11
+	pubs, sigs, msgs := newBatch(t, n, hFunc)
+
+	// all signatures are valid
+	ok, invalid, err := BatchVerify(pubs, sigs, msgs, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || len(invalid) != 0 {
+		t.Fatal("BatchVerify of valid signatures should return true")
+	}
+
+	// wrong message, swapped signatures, and malformed signature
+	msgs[2] = msgs[3]
+	sigs[5], sigs[6] = sigs[6], sigs[5]
+	sigs[9] = sigs[9][:len(sigs[9])-1]
+	ok, invalid, err = BatchVerify(pubs, sigs, msgs, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{2, 5, 6, 9}
+	if ok || len(invalid) != len(expected) {
+		t.Fatalf("BatchVerify should return the invalid signatures %v, got %v", expected, invalid)
+	}
+	for i := range expected {
+		if invalid[i] != expected[i] {
+			t.Fatalf("BatchVerify should return the invalid signatures %v, got %v", expected, invalid)
+		}
+	}
+
+	// consistency with Verify
+	for i := 0; i < n; i++ {
+		res, _ := pubs[i].Verify(sigs[i], msgs[i], hFunc)
+		isInvalid := i == 2 || i == 5 || i == 6 || i == 9
+		if res == isInvalid {
+			t.Fatalf("Verify and BatchVerify mismatch for signature %d", i)
+		}
+	}
+
+	if _, _, err := BatchVerify(pubs, sigs[1:], msgs, hFunc); err == nil {
+		t.Fatal("BatchVerify should fail if the sizes mismatch")
+	}
+}
+
+// benchmarks
+
+func BenchmarkSign(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src)
+
+	hFunc := hash.MIMC_BLS12_381.New("seed")
+
+	privKey, err := signature.EDDSA_BLS12_381_BANDERSNATCH.New(r)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var frMsg fr.Element
+	frMsg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035978")
+	msgBin := frMsg.Bytes()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign(msgBin[:], hFunc)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src)
+
+	hFunc := hash.MIMC_BLS12_381.New("seed")
+
+	// create eddsa obj and sign a message
+	privKey, err := signature.EDDSA_BLS12_381_BANDERSNATCH.New(r)
+	pubKey := privKey.Public()
+	if err != nil {
+		b.Fatal(err)
+	}
+	var frMsg fr.Element
+	frMsg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035978")
+	msgBin := frMsg.Bytes()
+	signature, _ := privKey.Sign(msgBin[:], hFunc)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pubKey.Verify(signature, msgBin[:], hFunc)
+	}
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+
+	hFunc := hash.MIMC_BLS12_381.New("seed")
+	const n = 64
+	pubs, sigs, msgs := newBatch(b, n, hFunc)
+
+	b.Run("individual", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < n; j++ {
+				pubs[j].Verify(sigs[j], msgs[j], hFunc)
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchVerify(pubs, sigs, msgs, hFunc)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2021 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bandersnatch
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// phi sets p to phi(p1), where phi is the degree 2 endomorphism of Bandersnatch, and returns it.
+// On the prime order subgroup, phi is the multiplication by lambdaGLV.
+// If p1 is an exceptional point of phi (x*y = 0 or y^2 = endo[0]*z^2), then p.Z = 0.
+// cf section 3 of https://eprint.iacr.org/2021/1152.pdf
+func (p *PointExtended) phi(p1 *PointExtended) *PointExtended {
+
+	var zz, yy, xy, f, g, h fr.Element
+	zz.Square(&p1.Z)
+	yy.Square(&p1.Y)
+	xy.Mul(&p1.X, &p1.Y)
+	f.Sub(&zz, &yy).Mul(&f, &endo[1])
+	zz.Mul(&zz, &endo[0])
+	g.Add(&yy, &zz).Mul(&g, &endo[0])
+	h.Sub(&yy, &zz)
+
+	var res PointProj
+	res.X.Mul(&f, &h)
+	res.Y.Mul(&g, &xy)
+	res.Z.Mul(&h, &xy)
+
+	return p.FromProj(&res)
+}
+
+// ScalarMulGLV sets p to scalar*p1 using the GLV method, and returns it.
+// the scalar is decomposed as k1 + k2*lambdaGLV modulo the order of the prime order subgroup,
+// where k1, k2 are about half the size of the scalar, and p is set to k1*p1 + k2*phi(p1).
+// see https://www.iacr.org/archive/crypto2001/21390189.pdf
+//
+// p1 must be in the prime order subgroup (see PointAffine.IsInSubGroup): for p1 = P + T, with T
+// a torsion point, the torsion part of the result is wrong. Use ScalarMul for other points.
+// scalar NOT in Montgomery form
+// modifies p
+func (p *PointExtended) ScalarMulGLV(p1 *PointExtended, scalar *big.Int) *PointExtended {
+
+	var table [15]PointExtended
+
+	// table[b3b2b1b0-1] = b3b2*phi(p1) + b1b0*p1
+	table[0].Set(p1)
+	table[3].phi(p1)
+	if table[3].Z.IsZero() {
+		// p1 is an exceptional point of phi, a torsion point
+		return p.scalarMulWindowed(p1, scalar)
+	}
+
+	// split the scalar, modifies +-p1, phi(p1) accordingly
+	var s big.Int
+	s.Mod(scalar, &edwards.Order)
+	k := ecc.SplitScalar(&s, &glvBasis)
+
+	if k[0].Sign() == -1 {
+		k[0].Neg(&k[0])
+		table[0].Neg(&table[0])
+	}
+	if k[1].Sign() == -1 {
+		k[1].Neg(&k[1])
+		table[3].Neg(&table[3])
+	}
+
+	// precompute table (2 bits sliding window)
+	table[1].Double(&table[0])
+	table[2].Add(&table[1], &table[0])
+	table[4].Add(&table[3], &table[0])
+	table[5].Add(&table[3], &table[1])
+	table[6].Add(&table[3], &table[2])
+	table[7].Double(&table[3])
+	table[8].Add(&table[7], &table[0])
+	table[9].Add(&table[7], &table[1])
+	table[10].Add(&table[7], &table[2])
+	table[11].Add(&table[7], &table[3])
+	table[12].Add(&table[11], &table[0])
+	table[13].Add(&table[11], &table[1])
+	table[14].Add(&table[11], &table[2])
+
+	nbBits := k[0].BitLen()
+	if k[1].BitLen() > nbBits {
+		nbBits = k[1].BitLen()
+	}
+
+	var res PointExtended
+	res.setInfinity()
+	for i := nbBits + nbBits%2 - 2; i >= 0; i -= 2 {
+		res.Double(&res).Double(&res)
+		b1 := k[0].Bit(i+1)<<1 | k[0].Bit(i)
+		b2 := k[1].Bit(i+1)<<1 | k[1].Bit(i)
+		if b1|b2 != 0 {
+			res.Add(&res, &table[b2<<2|b1-1])
+		}
+	}
+
+	return p.Set(&res)
+}
+
+// ScalarMulGLV sets p to scalar*p1 using the GLV method (see PointExtended.ScalarMulGLV), and returns it.
+// p1 must be in the prime order subgroup.
+// scalar NOT in Montgomery form
+// modifies p
+func (p *PointAffine) ScalarMulGLV(p1 *PointAffine, scalar *big.Int) *PointAffine {
+	var _p PointExtended
+	_p.FromAffine(p1)
+	_p.ScalarMulGLV(&_p, scalar)
+	p.FromExtended(&_p)
+	return p
+}
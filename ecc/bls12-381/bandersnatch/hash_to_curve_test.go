@@ -0,0 +1,207 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bandersnatch
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestElligator2Map(t *testing.T) {
+
+	if elligator2Z.Legendre() != -1 {
+		t.Fatal("Z should not be a square")
+	}
+
+	// the denominator 1 + Z*u^2 of x1 never vanishes, as -1/Z is not a square (-1 is a square in fr)
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne)
+	if minusOne.Legendre() != 1 {
+		t.Fatal("-1 should be a square")
+	}
+
+	// u = 0 is mapped with x1 = -A
+	var zero fr.Element
+	inputs := []fr.Element{zero}
+	for i := 0; i < 20; i++ {
+		var u fr.Element
+		u.SetRandom()
+		inputs = append(inputs, u)
+	}
+
+	for _, u := range inputs {
+		p := elligator2Map(u)
+		if !p.IsOnCurve() {
+			t.Fatalf("elligator2Map(%s) is not on the curve", u.String())
+		}
+
+		// u and -u are mapped to the same point, as only u^2 is used to select x
+		var v fr.Element
+		v.Neg(&u)
+		if q := elligator2Map(v); !q.Equal(&p) {
+			t.Fatal("elligator2Map(u) and elligator2Map(-u) should be equal")
+		}
+	}
+}
+
+func TestHashToCurve(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+	for i := 0; i < 10; i++ {
+		msg := []byte(fmt.Sprintf("msg %d", i))
+
+		for _, hashToCurve := range []func(msg, dst []byte) (PointAffine, error){HashToCurve, EncodeToCurve} {
+			p, err := hashToCurve(msg, dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !p.IsOnCurve() {
+				t.Fatal("hash to curve output should be on the curve")
+			}
+
+			// the output is in the prime order subgroup
+			var pProj, q PointProj
+			pProj.FromAffine(&p)
+			if scalarMulNaive(&q, &pProj, &ed.Order); !q.IsZero() || pProj.IsZero() {
+				t.Fatal("hash to curve output should be in the prime order subgroup")
+			}
+
+			// the output is deterministic, and depends on the message and on the domain separation tag
+			p2, _ := hashToCurve(msg, dst)
+			p3, _ := hashToCurve(append(msg, 0), dst)
+			p4, _ := hashToCurve(msg, []byte("another DST"))
+			if !p.Equal(&p2) || p.Equal(&p3) || p.Equal(&p4) {
+				t.Fatal("hash to curve output should depend only on the message and on the domain separation tag")
+			}
+		}
+	}
+
+	// EncodeToCurve(msg) = MapToCurve(u), with u = hash_to_field(msg)
+	u, err := hashToFr([]byte("abc"), dst, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MapToCurve(u[0])
+	p, _ := EncodeToCurve([]byte("abc"), dst)
+	if !p.Equal(&expected) {
+		t.Fatal("EncodeToCurve and MapToCurve mismatch")
+	}
+
+	// HashToCurve(msg) = MapToCurve(u0) + MapToCurve(u1), with u0, u1 = hash_to_field(msg)
+	if u, err = hashToFr([]byte("abc"), dst, 2); err != nil {
+		t.Fatal(err)
+	}
+	q0, q1 := MapToCurve(u[0]), MapToCurve(u[1])
+	expected.Add(&q0, &q1)
+	p, _ = HashToCurve([]byte("abc"), dst)
+	if !p.Equal(&expected) {
+		t.Fatal("HashToCurve and MapToCurve mismatch")
+	}
+}
+
+// TestHashToCurveVectors checks hash_to_field, the Elligator 2 map and HashToCurve against known answers,
+// computed with an independent implementation of the generic steps of RFC 9380 (expand_message_xmd
+// with SHA-256, hash_to_field, map_to_curve_elligator2 on the Montgomery curve, the rational map of
+// appendix D and clear_cofactor). q0 and q1 are the outputs of the map before the cofactor is cleared.
+func TestHashToCurveVectors(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+	vectors := []struct {
+		msg       string
+		u0, u1    string
+		q0, q1, p [2]string
+	}{
+		{
+			msg: "",
+			u0:  "28278357814166947568488584426491389479439390112357611701312376267537105050041",
+			u1:  "49308309320967913505919568061414385396948656328546067415362443647623667680601",
+			q0:  [2]string{"25778725454212042087841129298765489733210270388932150627000070346074828039267", "44270767009564362420579502108316194684168551649006123120597081963212529550682"},
+			q1:  [2]string{"50393582757105629958653456039840915207383524603564707101526081730457917725207", "9761100617897303305302408701393263669912733237940087529158073376088751738719"},
+			p:   [2]string{"2853770623252755743033131241101787493138906948083073173612071663099211180230", "32018686263777541841912654904735818101564576365656516267512439399777775659311"},
+		},
+		{
+			msg: "abc",
+			u0:  "8806368777326611730071432931469475601663107643044540149455546132280892900883",
+			u1:  "330432291611276014638773701200383811214971228698308470022688781867633840072",
+			q0:  [2]string{"27412896876807191627540905246257706760990591015483507795625366439766949554016", "35547396219685936167301776809275580046208776149618673341327041537656659964859"},
+			q1:  [2]string{"26491215373673191227106892027999699297151103931297838688469817033581981528098", "7550166593602690650059432938531422141918361608944869741388141148872128267265"},
+			p:   [2]string{"37726342499538830152004429711578598219853943359595518662827436719256060247768", "35449958819026328507073646388870964039329825023611313641912524823620921242547"},
+		},
+		{
+			msg: "abcdef0123456789",
+			u0:  "38772386909081436743329575645801769926301001313287835297014683976293361251597",
+			u1:  "5931881820503667569547080286518883897309992521973793238071429988488969071916",
+			q0:  [2]string{"21113026092412454603297447576533903016978115122158967775219236471023601373808", "44076892215947090549505843550771403492053669341337532477634179124803289107965"},
+			q1:  [2]string{"32786703425461247331472073083626005224448337569028748261378728998997095483298", "850340230198763081095289141951610045289064466622894947849427612605247828921"},
+			p:   [2]string{"48182555987451139131174222150907661298160918721794135573597376151929150255617", "39285925721258831631673893995230141098900536447319478266794391621118761943597"},
+		},
+	}
+
+	for _, v := range vectors {
+		u, err := hashToFr([]byte(v.msg), dst, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var u0, u1 fr.Element
+		u0.SetString(v.u0)
+		u1.SetString(v.u1)
+		if !u[0].Equal(&u0) || !u[1].Equal(&u1) {
+			t.Fatalf("hashToFr(%q) mismatch", v.msg)
+		}
+
+		var q0, q1, expected PointAffine
+		q0.X.SetString(v.q0[0])
+		q0.Y.SetString(v.q0[1])
+		q1.X.SetString(v.q1[0])
+		q1.Y.SetString(v.q1[1])
+		if p := elligator2Map(u0); !p.Equal(&q0) {
+			t.Fatalf("elligator2Map(u0) mismatch for msg %q", v.msg)
+		}
+		if p := elligator2Map(u1); !p.Equal(&q1) {
+			t.Fatalf("elligator2Map(u1) mismatch for msg %q", v.msg)
+		}
+
+		p, err := HashToCurve([]byte(v.msg), dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected.X.SetString(v.p[0])
+		expected.Y.SetString(v.p[1])
+		if !p.Equal(&expected) {
+			t.Fatalf("HashToCurve(%q) mismatch", v.msg)
+		}
+	}
+}
+
+func BenchmarkHashToCurve(b *testing.B) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	msg := []byte("abc")
+	b.Run("EncodeToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncodeToCurve(msg, dst)
+		}
+	})
+	b.Run("HashToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			HashToCurve(msg, dst)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bandersnatch
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestMultiExp(t *testing.T) {
+	ed := GetEdwardsCurve()
+
+	var cofactor, order big.Int
+	ed.Cofactor.ToBigInt(&cofactor)
+	order.Mul(&ed.Order, &cofactor)
+
+	const nbSamples = 73
+	points, scalars := randomMultiExpInputs(t, nbSamples)
+
+	// edge cases: small, negative and large scalars, and a point of order 2
+	scalars[0].SetUint64(0)
+	scalars[1].SetUint64(1)
+	scalars[2].Sub(&order, big.NewInt(1))
+	scalars[3].Neg(&scalars[3])
+	scalars[4].Lsh(&order, 3).Add(&scalars[4], big.NewInt(42))
+	points[5].X.SetZero()
+	points[5].Y.SetOne().Neg(&points[5].Y)
+
+	var expected PointProj
+	multiExpNaive(&expected, points, scalars)
+
+	for _, nbCpus := range []int{1, runtime.NumCPU()} {
+		var p PointProj
+		p.MultiExp(points, scalars, NewCPUSemaphore(nbCpus))
+		if !p.Equal(&expected) {
+			t.Fatalf("MultiExp with %d cpus not consistent with the naive method", nbCpus)
+		}
+	}
+
+	// small sizes select the smallest windows
+	for n := 0; n <= 4; n++ {
+		multiExpNaive(&expected, points[:n], scalars[:n])
+		var p, e PointAffine
+		p.MultiExp(points[:n], scalars[:n])
+		e.FromProj(&expected)
+		if !p.Equal(&e) {
+			t.Fatalf("MultiExp of size %d not consistent with the naive method", n)
+		}
+	}
+}
+
+func TestMultiExpSharedSemaphore(t *testing.T) {
+	points, scalars := randomMultiExpInputs(t, 32)
+
+	var expected PointProj
+	expected.MultiExp(points, scalars, NewCPUSemaphore(1))
+
+	// the semaphore can be shared between concurrent calls
+	opt := NewCPUSemaphore(2)
+	results := make([]PointProj, 4)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i].MultiExp(points, scalars, opt)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range results {
+		if !results[i].Equal(&expected) {
+			t.Fatal("concurrent MultiExp with a shared semaphore mismatch")
+		}
+	}
+}
+
+func BenchmarkMultiExp(b *testing.B) {
+	const maxSize = 1 << 12
+	points, scalars := randomMultiExpInputs(b, maxSize)
+
+	var res PointProj
+	for size := 1 << 4; size <= maxSize; size <<= 2 {
+		b.Run(fmt.Sprintf("%d points", size), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				res.MultiExp(points[:size], scalars[:size])
+			}
+		})
+	}
+}
+
+// multiExpNaive sets p to Σ scalars[i]*points[i], with a double-and-add for each point
+func multiExpNaive(p *PointProj, points []PointAffine, scalars []big.Int) *PointProj {
+	p.setInfinity()
+	for i := range points {
+		var tmp PointProj
+		var s big.Int
+		tmp.FromAffine(&points[i])
+		scalarMulNaive(&tmp, &tmp, s.Abs(&scalars[i]))
+		if scalars[i].Sign() < 0 {
+			tmp.Neg(&tmp)
+		}
+		p.Add(p, &tmp)
+	}
+	return p
+}
+
+// randomMultiExpInputs returns n random points of the prime order subgroup, and n random scalars
+func randomMultiExpInputs(tb testing.TB, n int) ([]PointAffine, []big.Int) {
+	ed := GetEdwardsCurve()
+
+	points := make([]PointAffine, n)
+	scalars := make([]big.Int, n)
+	for i := 0; i < n; i++ {
+		points[i] = randomPointAffine()
+		r, err := rand.Int(rand.Reader, &ed.Order)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		scalars[i].Set(r)
+	}
+	return points, scalars
+}
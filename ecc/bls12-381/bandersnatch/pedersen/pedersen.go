@@ -0,0 +1,140 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package pedersen provides a Pedersen vector commitment scheme on the twisted Edwards curve.
+package pedersen
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+)
+
+var (
+	ErrInvalidKeySize  = errors.New("invalid key size (== 0)")
+	ErrInvalidNbValues = errors.New("number of values is larger than the key size")
+	ErrVerifyOpening   = errors.New("can't verify pedersen opening")
+)
+
+// Key stores the generators of a Pedersen vector commitment
+//
+// The generators are obtained by hashing to the prime order subgroup of the twisted Edwards curve,
+// hence their discrete logarithms relatively to each other are unknown.
+//
+// implements io.ReaderFrom and io.WriterTo
+type Key struct {
+	G []bandersnatch.PointAffine // [G_0, G_1, ... ], generators of the committed values
+	H bandersnatch.PointAffine   // generator of the blinding factor
+}
+
+// Commitment Pedersen commitment Σ values[i]*G_i + randomness*H to a vector of values.
+//
+// implements io.ReaderFrom and io.WriterTo
+type Commitment bandersnatch.PointAffine
+
+// NewKey returns a key to commit to vectors of at most size values
+//
+// G_i is HashToCurve(i, dst), with i encoded as a big endian uint64,
+// and H is HashToCurve("H", dst).
+func NewKey(size int, dst []byte) (*Key, error) {
+	if size <= 0 {
+		return nil, ErrInvalidKeySize
+	}
+	var key Key
+	var err error
+	key.H, err = bandersnatch.HashToCurve([]byte("H"), dst)
+	if err != nil {
+		return nil, err
+	}
+
+	key.G = make([]bandersnatch.PointAffine, size)
+	var msg [8]byte
+	for i := 0; i < size; i++ {
+		binary.BigEndian.PutUint64(msg[:], uint64(i))
+		if key.G[i], err = bandersnatch.HashToCurve(msg[:], dst); err != nil {
+			return nil, err
+		}
+	}
+
+	return &key, nil
+}
+
+// Commit returns the commitment Σ values[i]*G_i + randomness*H
+//
+// values may be shorter than the key, in which case the missing values are 0.
+// The values and the randomness are scalars modulo the order of the prime order subgroup,
+// negative scalars are supported.
+func (key *Key) Commit(values []big.Int, randomness *big.Int) (Commitment, error) {
+	if len(values) > len(key.G) {
+		return Commitment{}, ErrInvalidNbValues
+	}
+
+	points := make([]bandersnatch.PointAffine, len(values)+1)
+	scalars := make([]big.Int, len(values)+1)
+	copy(points, key.G[:len(values)])
+	for i := 0; i < len(values); i++ {
+		scalars[i].Set(&values[i])
+	}
+	points[len(values)] = key.H
+	scalars[len(values)].Set(randomness)
+
+	var res bandersnatch.PointAffine
+	res.MultiExp(points, scalars)
+
+	return Commitment(res), nil
+}
+
+// Verify checks that commitment opens to values with the given randomness
+func (key *Key) Verify(commitment *Commitment, values []big.Int, randomness *big.Int) error {
+	expected, err := key.Commit(values, randomness)
+	if err != nil {
+		return err
+	}
+	if !expected.Equal(commitment) {
+		return ErrVerifyOpening
+	}
+	return nil
+}
+
+// Add sets c to a + b and returns it
+//
+// c opens to the sum of the values and of the randomness of a and b.
+func (c *Commitment) Add(a, b *Commitment) *Commitment {
+	var _c bandersnatch.PointExtended
+	_c.FromAffine((*bandersnatch.PointAffine)(a))
+	_c.MixedAdd(&_c, (*bandersnatch.PointAffine)(b))
+	(*bandersnatch.PointAffine)(c).FromExtended(&_c)
+	return c
+}
+
+// ScalarMul sets c to s*a and returns it
+//
+// c opens to the values and the randomness of a multiplied by s.
+func (c *Commitment) ScalarMul(a *Commitment, s *big.Int) *Commitment {
+	// the commitments are in the prime order subgroup, s can be reduced modulo its order
+	curveParams := bandersnatch.GetEdwardsCurve()
+	var _s big.Int
+	_s.Mod(s, &curveParams.Order)
+	(*bandersnatch.PointAffine)(c).ScalarMul((*bandersnatch.PointAffine)(a), &_s)
+	return c
+}
+
+// Equal returns true if c and a are the same commitment
+func (c *Commitment) Equal(a *Commitment) bool {
+	return (*bandersnatch.PointAffine)(c).Equal((*bandersnatch.PointAffine)(a))
+}
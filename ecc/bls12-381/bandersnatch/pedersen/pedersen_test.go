@@ -0,0 +1,266 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pedersen
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+)
+
+// testKey re-used accross tests of the Pedersen commitment
+var testKey *Key
+
+func init() {
+	const keySize = 16
+	testKey, _ = NewKey(keySize, []byte("pedersen test"))
+}
+
+// randomValues returns size random scalars modulo the order of the prime order subgroup
+func randomValues(size int) []big.Int {
+	curveParams := bandersnatch.GetEdwardsCurve()
+	values := make([]big.Int, size)
+	for i := 0; i < size; i++ {
+		v, _ := rand.Int(rand.Reader, &curveParams.Order)
+		values[i].Set(v)
+	}
+	return values
+}
+
+func TestNewKey(t *testing.T) {
+
+	if _, err := NewKey(0, []byte("pedersen test")); err != ErrInvalidKeySize {
+		t.Fatal("NewKey should fail on an empty key")
+	}
+
+	// the generators are deterministic and distinct
+	key, err := NewKey(len(testKey.G), []byte("pedersen test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(key, testKey) {
+		t.Fatal("NewKey is not deterministic")
+	}
+	for i := 0; i < len(key.G); i++ {
+		if !key.G[i].IsInSubGroup() || key.G[i].Equal(&key.H) {
+			t.Fatal("invalid generator")
+		}
+		for j := 0; j < i; j++ {
+			if key.G[i].Equal(&key.G[j]) {
+				t.Fatal("generators should be distinct")
+			}
+		}
+	}
+
+	// the generators depend on the domain separation tag
+	other, err := NewKey(1, []byte("other pedersen test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.G[0].Equal(&key.G[0]) || other.H.Equal(&key.H) {
+		t.Fatal("generators should depend on the domain separation tag")
+	}
+}
+
+func TestCommit(t *testing.T) {
+
+	values := randomValues(len(testKey.G) - 3)
+	randomness := randomValues(1)[0]
+
+	commitment, err := testKey.Commit(values, &randomness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// check the commitment against Σ values[i]*G_i + randomness*H
+	var expected, tmp bandersnatch.PointAffine
+	expected.Y.SetOne()
+	for i := 0; i < len(values); i++ {
+		tmp.ScalarMul(&testKey.G[i], &values[i])
+		expected.Add(&expected, &tmp)
+	}
+	tmp.ScalarMul(&testKey.H, &randomness)
+	expected.Add(&expected, &tmp)
+	if !expected.Equal((*bandersnatch.PointAffine)(&commitment)) {
+		t.Fatal("commitment doesn't match Σ values[i]*G_i + randomness*H")
+	}
+
+	// valid opening
+	if err := testKey.Verify(&commitment, values, &randomness); err != nil {
+		t.Fatal(err)
+	}
+
+	// wrong value
+	wrongValues := make([]big.Int, len(values))
+	for i := 0; i < len(values); i++ {
+		wrongValues[i].Set(&values[i])
+	}
+	wrongValues[1].Add(&wrongValues[1], big.NewInt(1))
+	if err := testKey.Verify(&commitment, wrongValues, &randomness); err != ErrVerifyOpening {
+		t.Fatal("verifying a wrong value should fail")
+	}
+
+	// wrong randomness
+	var wrongRandomness big.Int
+	wrongRandomness.Add(&randomness, big.NewInt(1))
+	if err := testKey.Verify(&commitment, values, &wrongRandomness); err != ErrVerifyOpening {
+		t.Fatal("verifying a wrong randomness should fail")
+	}
+
+	// too many values
+	if _, err := testKey.Commit(randomValues(len(testKey.G)+1), &randomness); err != ErrInvalidNbValues {
+		t.Fatal("committing to more values than the key size should fail")
+	}
+}
+
+func TestHomomorphism(t *testing.T) {
+
+	curveParams := bandersnatch.GetEdwardsCurve()
+
+	a, b := randomValues(len(testKey.G)), randomValues(len(testKey.G)-1)
+	r := randomValues(3)
+	ra, rb, s := &r[0], &r[1], &r[2]
+
+	ca, err := testKey.Commit(a, ra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb, err := testKey.Commit(b, rb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Commit(a) + Commit(b) opens to a + b
+	sum := make([]big.Int, len(a))
+	for i := 0; i < len(a); i++ {
+		sum[i].Set(&a[i])
+	}
+	for i := 0; i < len(b); i++ {
+		sum[i].Add(&sum[i], &b[i])
+	}
+	var rSum big.Int
+	rSum.Add(ra, rb)
+	var cSum Commitment
+	cSum.Add(&ca, &cb)
+	if err := testKey.Verify(&cSum, sum, &rSum); err != nil {
+		t.Fatal(err)
+	}
+
+	// s*Commit(a) opens to s*a, with negative scalars
+	s.Sub(s, &curveParams.Order)
+	scaled := make([]big.Int, len(a))
+	for i := 0; i < len(a); i++ {
+		scaled[i].Mul(&a[i], s)
+	}
+	var rScaled big.Int
+	rScaled.Mul(ra, s)
+	var cScaled Commitment
+	cScaled.ScalarMul(&ca, s)
+	if err := testKey.Verify(&cScaled, scaled, &rScaled); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSerialization(t *testing.T) {
+
+	// serialize the key...
+	var buf bytes.Buffer
+	if _, err := testKey.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// ... and reconstruct it
+	var key Key
+	if _, err := key.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(testKey, &key) {
+		t.Fatal("key serialization failed")
+	}
+
+	// a key with an oversized number of generators is rejected
+	var empty Key
+	empty.H = testKey.H
+	buf.Reset()
+	if _, err := empty.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[len(data)-4:], math.MaxUint32)
+	if _, err := key.ReadFrom(bytes.NewReader(data)); err == nil {
+		t.Fatal("decoding a key with an oversized number of generators should fail")
+	}
+
+	randomness := randomValues(1)[0]
+	commitment, err := testKey.Commit(randomValues(len(testKey.G)), &randomness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// io.WriterTo and io.ReaderFrom
+	buf.Reset()
+	if _, err := commitment.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _commitment Commitment
+	if _, err := _commitment.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !_commitment.Equal(&commitment) {
+		t.Fatal("commitment serialization failed")
+	}
+
+	// Bytes and SetBytes
+	_commitment = Commitment{}
+	if _, err := _commitment.SetBytes(commitment.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !_commitment.Equal(&commitment) {
+		t.Fatal("commitment serialization failed")
+	}
+
+	// a point outside of the prime order subgroup is rejected
+	var torsion bandersnatch.PointAffine
+	torsion.Y.SetOne().Neg(&torsion.Y)
+	bin := torsion.Bytes()
+	if _, err := _commitment.SetBytes(bin[:]); err != errInvalidPoint {
+		t.Fatal("SetBytes should fail on a point outside of the prime order subgroup")
+	}
+}
+
+// benchmarks
+
+func BenchmarkCommit(b *testing.B) {
+	const keySize = 1 << 10
+	key, err := NewKey(keySize, []byte("pedersen benchmark"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	values := randomValues(keySize)
+	randomness := randomValues(1)[0]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key.Commit(values, &randomness)
+	}
+}
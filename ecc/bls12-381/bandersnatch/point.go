@@ -0,0 +1,700 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bandersnatch
+
+import (
+	"crypto/subtle"
+	"io"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/internal/ctmod"
+)
+
+// PointAffine point on a twisted Edwards curve
+type PointAffine struct {
+	X, Y fr.Element
+}
+
+// PointProj point in projective coordinates
+type PointProj struct {
+	X, Y, Z fr.Element
+}
+
+const (
+	//following https://tools.ietf.org/html/rfc8032#section-3.1,
+	// an fr element x is negative if its binary encoding is
+	// lexicographically larger than -x.
+	mCompressedNegative = 0x80
+	mCompressedPositive = 0x00
+	mUnmask             = 0x7f
+
+	// size in byte of a compressed point (point.Y --> fr.Element)
+	sizePointCompressed = fr.Limbs * 8
+)
+
+// Bytes returns the compressed point as a byte array
+// Follows https://tools.ietf.org/html/rfc8032#section-3.1,
+// as the twisted Edwards implementation is primarily used
+// for eddsa.
+func (p *PointAffine) Bytes() [sizePointCompressed]byte {
+
+	var res [sizePointCompressed]byte
+	var mask uint
+
+	y := p.Y.Bytes()
+
+	if p.X.LexicographicallyLargest() {
+		mask = mCompressedNegative
+	} else {
+		mask = mCompressedPositive
+	}
+	// p.Y must be in little endian
+	y[0] |= byte(mask) // msb of y
+	for i, j := 0, sizePointCompressed-1; i < j; i, j = i+1, j-1 {
+		y[i], y[j] = y[j], y[i]
+	}
+	subtle.ConstantTimeCopy(1, res[:], y[:])
+	return res
+}
+
+// Marshal converts p to a byte slice
+func (p *PointAffine) Marshal() []byte {
+	b := p.Bytes()
+	return b[:]
+}
+
+func computeX(y *fr.Element) (x fr.Element) {
+	var one, num, den fr.Element
+	one.SetOne()
+	num.Square(y)
+	den.Mul(&num, &edwards.D)
+	num.Sub(&one, &num)
+	den.Sub(&edwards.A, &den)
+	x.Div(&num, &den)
+	x.Sqrt(&x)
+	return
+}
+
+// SetBytes sets p from buf
+// len(buf) >= sizePointCompressed
+// buf contains the Y coordinate masked with a parity bit to recompute the X coordinate
+// from the curve equation. See Bytes() and https://tools.ietf.org/html/rfc8032#section-3.1
+// Returns the number of read bytes and an error if the buffer is too short.
+func (p *PointAffine) SetBytes(buf []byte) (int, error) {
+
+	if len(buf) < sizePointCompressed {
+		return 0, io.ErrShortBuffer
+	}
+	bufCopy := make([]byte, sizePointCompressed)
+	subtle.ConstantTimeCopy(1, bufCopy, buf[:sizePointCompressed])
+	for i, j := 0, sizePointCompressed-1; i < j; i, j = i+1, j-1 {
+		bufCopy[i], bufCopy[j] = bufCopy[j], bufCopy[i]
+	}
+	isLexicographicallyLargest := (mCompressedNegative&bufCopy[0])>>7 == 1
+	bufCopy[0] &= mUnmask
+	p.Y.SetBytes(bufCopy)
+	p.X = computeX(&p.Y)
+	if isLexicographicallyLargest {
+		if !p.X.LexicographicallyLargest() {
+			p.X.Neg(&p.X)
+		}
+	} else {
+		if p.X.LexicographicallyLargest() {
+			p.X.Neg(&p.X)
+		}
+	}
+
+	return sizePointCompressed, nil
+}
+
+// Unmarshal alias to SetBytes()
+func (p *PointAffine) Unmarshal(b []byte) error {
+	_, err := p.SetBytes(b)
+	return err
+}
+
+// Set sets p to p1 and return it
+func (p *PointProj) Set(p1 *PointProj) *PointProj {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.Set(&p1.Z)
+	return p
+}
+
+// Set sets p to p1 and return it
+func (p *PointAffine) Set(p1 *PointAffine) *PointAffine {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	return p
+}
+
+// Equal returns true if p=p1 false otherwise
+func (p *PointAffine) Equal(p1 *PointAffine) bool {
+	return p.X.Equal(&p1.X) && p.Y.Equal(&p1.Y)
+}
+
+// Equal returns true if p=p1 false otherwise
+// If one point is on the affine chart Z=0 it returns false
+func (p *PointProj) Equal(p1 *PointProj) bool {
+	if p.Z.IsZero() || p1.Z.IsZero() {
+		return false
+	}
+	var pAffine, p1Affine PointAffine
+	pAffine.FromProj(p)
+	p1Affine.FromProj(p1)
+	return pAffine.Equal(&p1Affine)
+}
+
+// NewPointAffine creates a new instance of PointAffine
+func NewPointAffine(x, y fr.Element) PointAffine {
+	return PointAffine{x, y}
+}
+
+// IsOnCurve checks if a point is on the twisted Edwards curve
+func (p *PointAffine) IsOnCurve() bool {
+
+	ecurve := GetEdwardsCurve()
+
+	var lhs, rhs, tmp fr.Element
+
+	tmp.Mul(&p.Y, &p.Y)
+	lhs.Mul(&p.X, &p.X).
+		Mul(&lhs, &ecurve.A).
+		Add(&lhs, &tmp)
+
+	tmp.Mul(&p.X, &p.X).
+		Mul(&tmp, &p.Y).
+		Mul(&tmp, &p.Y).
+		Mul(&tmp, &ecurve.D)
+	rhs.SetOne().Add(&rhs, &tmp)
+
+	return lhs.Equal(&rhs)
+}
+
+// IsInSubGroup returns true if p is on the curve and in the prime order subgroup, false otherwise
+func (p *PointAffine) IsInSubGroup() bool {
+	if !p.IsOnCurve() {
+		return false
+	}
+	var _p PointExtended
+	_p.FromAffine(p)
+	_p.scalarMulWindowed(&_p, &edwards.Order)
+	return _p.IsZero()
+}
+
+// Add adds two points (x,y), (u,v) on a twisted Edwards curve with parameters a, d
+// modifies p
+func (p *PointAffine) Add(p1, p2 *PointAffine) *PointAffine {
+
+	ecurve := GetEdwardsCurve()
+
+	var xu, yv, xv, yu, dxyuv, one, denx, deny fr.Element
+	pRes := new(PointAffine)
+	xv.Mul(&p1.X, &p2.Y)
+	yu.Mul(&p1.Y, &p2.X)
+	pRes.X.Add(&xv, &yu)
+
+	xu.Mul(&p1.X, &p2.X).Mul(&xu, &ecurve.A)
+	yv.Mul(&p1.Y, &p2.Y)
+	pRes.Y.Sub(&yv, &xu)
+
+	dxyuv.Mul(&xv, &yu).Mul(&dxyuv, &ecurve.D)
+	one.SetOne()
+	denx.Add(&one, &dxyuv)
+	deny.Sub(&one, &dxyuv)
+
+	p.X.Div(&pRes.X, &denx)
+	p.Y.Div(&pRes.Y, &deny)
+
+	return p
+}
+
+// Double doubles point (x,y) on a twisted Edwards curve with parameters a, d
+// modifies p
+func (p *PointAffine) Double(p1 *PointAffine) *PointAffine {
+	p.Add(p1, p1)
+	return p
+}
+
+// Neg negates point (x,y) on a twisted Edwards curve with parameters a, d
+// modifies p
+func (p *PointAffine) Neg(p1 *PointAffine) *PointAffine {
+	p.X.Neg(&p1.X)
+	p.Y = p1.Y
+	return p
+}
+
+// FromProj sets p in affine from p in projective
+func (p *PointAffine) FromProj(p1 *PointProj) *PointAffine {
+	p.X.Div(&p1.X, &p1.Z)
+	p.Y.Div(&p1.Y, &p1.Z)
+	return p
+}
+
+// FromAffine sets p in projective from p in affine
+func (p *PointProj) FromAffine(p1 *PointAffine) *PointProj {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.SetOne()
+	return p
+}
+
+// Add adds points in projective coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-projective.html
+func (p *PointProj) Add(p1, p2 *PointProj) *PointProj {
+
+	var res PointProj
+
+	ecurve := GetEdwardsCurve()
+
+	var A, B, C, D, E, F, G, H, I fr.Element
+	A.Mul(&p1.Z, &p2.Z)
+	B.Square(&A)
+	C.Mul(&p1.X, &p2.X)
+	D.Mul(&p1.Y, &p2.Y)
+	E.Mul(&ecurve.D, &C).Mul(&E, &D)
+	F.Sub(&B, &E)
+	G.Add(&B, &E)
+	H.Add(&p1.X, &p1.Y)
+	I.Add(&p2.X, &p2.Y)
+	res.X.Mul(&H, &I).
+		Sub(&res.X, &C).
+		Sub(&res.X, &D).
+		Mul(&res.X, &A).
+		Mul(&res.X, &F)
+	H.Mul(&ecurve.A, &C)
+	res.Y.Sub(&D, &H).
+		Mul(&res.Y, &A).
+		Mul(&res.Y, &G)
+	res.Z.Mul(&F, &G)
+
+	p.Set(&res)
+	return p
+}
+
+// MixedAdd adds a point in projective coordinates and a point in affine coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-projective.html#addition-madd-2008-bbjlp
+func (p *PointProj) MixedAdd(p1 *PointProj, p2 *PointAffine) *PointProj {
+
+	var B, C, D, E, F, G, H, I fr.Element
+	B.Square(&p1.Z)
+	C.Mul(&p1.X, &p2.X)
+	D.Mul(&p1.Y, &p2.Y)
+	E.Mul(&edwards.D, &C).Mul(&E, &D)
+	F.Sub(&B, &E)
+	G.Add(&B, &E)
+	H.Add(&p1.X, &p1.Y)
+	I.Add(&p2.X, &p2.Y)
+	p.X.Mul(&H, &I).
+		Sub(&p.X, &C).
+		Sub(&p.X, &D).
+		Mul(&p.X, &p1.Z).
+		Mul(&p.X, &F)
+	H.Mul(&edwards.A, &C)
+	p.Y.Sub(&D, &H).
+		Mul(&p.Y, &p1.Z).
+		Mul(&p.Y, &G)
+	p.Z.Mul(&F, &G)
+
+	return p
+}
+
+// Double adds points in projective coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-projective.html
+func (p *PointProj) Double(p1 *PointProj) *PointProj {
+
+	var res PointProj
+
+	ecurve := GetEdwardsCurve()
+
+	var B, C, D, E, F, H, J, tmp fr.Element
+
+	B.Add(&p1.X, &p1.Y).Square(&B)
+	C.Square(&p1.X)
+	D.Square(&p1.Y)
+	E.Mul(&ecurve.A, &C)
+	F.Add(&E, &D)
+	H.Square(&p1.Z)
+	tmp.Double(&H)
+	J.Sub(&F, &tmp)
+	res.X.Sub(&B, &C).
+		Sub(&res.X, &D).
+		Mul(&res.X, &J)
+	res.Y.Sub(&E, &D).Mul(&res.Y, &F)
+	res.Z.Mul(&F, &J)
+
+	p.Set(&res)
+	return p
+}
+
+// Neg sets p to -p1 and returns it
+func (p *PointProj) Neg(p1 *PointProj) *PointProj {
+	p.X.Neg(&p1.X)
+	p.Y = p1.Y
+	p.Z = p1.Z
+	return p
+}
+
+// IsZero returns true if p is the identity (0:1:1)
+func (p *PointProj) IsZero() bool {
+	return !p.Z.IsZero() && p.X.IsZero() && p.Y.Equal(&p.Z)
+}
+
+// ClearCofactor sets p to cofactor*p1 and returns it
+func (p *PointProj) ClearCofactor(p1 *PointProj) *PointProj {
+	var cofactor big.Int
+	edwards.Cofactor.ToBigInt(&cofactor)
+
+	var res PointProj
+	res.Set(p1)
+	for i := cofactor.BitLen() - 2; i >= 0; i-- {
+		res.Double(&res)
+		if cofactor.Bit(i) == 1 {
+			res.Add(&res, p1)
+		}
+	}
+
+	return p.Set(&res)
+}
+
+// ScalarMul scalar multiplication of a point
+// p1 points on the twisted Edwards curve
+// c parameters of the twisted Edwards curve
+// scal scalar NOT in Montgomery form
+// For points known to be in the prime order subgroup, ScalarMulGLV is faster.
+// modifies p
+// func (p *PointAffine) ScalarMul(p1 *PointAffine, scalar fr.Element) *PointAffine {
+func (p *PointAffine) ScalarMul(p1 *PointAffine, scalar *big.Int) *PointAffine {
+	var _p PointExtended
+	_p.FromAffine(p1)
+	_p.ScalarMul(&_p, scalar)
+	p.FromExtended(&_p)
+	return p
+}
+
+// ScalarMulSecret scalar multiplication of a point, in constant time with respect to the scalar.
+// It must be used instead of ScalarMul when the scalar is secret (for example, a private key or
+// a signature nonce), as the running time of ScalarMul depends on the bits of the scalar.
+//
+// See PointExtended.ScalarMulSecret.
+// modifies p
+func (p *PointAffine) ScalarMulSecret(p1 *PointAffine, scalar *big.Int) *PointAffine {
+	var _p PointExtended
+	_p.FromAffine(p1)
+	_p.ScalarMulSecret(&_p, scalar)
+	p.fromExtendedSecret(&_p)
+	return p
+}
+
+// fromExtendedSecret is FromExtended, with a constant time inversion of p1.Z (by Fermat's little theorem)
+func (p *PointAffine) fromExtendedSecret(p1 *PointExtended) *PointAffine {
+	var e big.Int
+	e.Sub(fr.Modulus(), big.NewInt(2))
+	var zInv fr.Element
+	zInv.Exp(p1.Z, &e)
+	p.X.Mul(&p1.X, &zInv)
+	p.Y.Mul(&p1.Y, &zInv)
+	return p
+}
+
+// PointExtended point in extended coordinates (X:Y:T:Z), with x=X/Z, y=Y/Z and x*y=T/Z
+// cf https://eprint.iacr.org/2008/522.pdf
+type PointExtended struct {
+	X, Y, Z, T fr.Element
+}
+
+// Set sets p to p1 and returns it
+func (p *PointExtended) Set(p1 *PointExtended) *PointExtended {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.Set(&p1.Z)
+	p.T.Set(&p1.T)
+	return p
+}
+
+// Equal returns true if p=p1 false otherwise
+// If one point is on the affine chart Z=0 it returns false
+func (p *PointExtended) Equal(p1 *PointExtended) bool {
+	if p.Z.IsZero() || p1.Z.IsZero() {
+		return false
+	}
+	var pAffine, p1Affine PointAffine
+	pAffine.FromExtended(p)
+	p1Affine.FromExtended(p1)
+	return pAffine.Equal(&p1Affine)
+}
+
+// IsZero returns true if p is the identity (0:1:0:1)
+func (p *PointExtended) IsZero() bool {
+	return !p.Z.IsZero() && p.X.IsZero() && p.Y.Equal(&p.Z)
+}
+
+// setInfinity sets p to the identity (0:1:0:1)
+func (p *PointExtended) setInfinity() *PointExtended {
+	p.X.SetZero()
+	p.Y.SetOne()
+	p.Z.SetOne()
+	p.T.SetZero()
+	return p
+}
+
+// FromAffine sets p in extended from p in affine
+func (p *PointExtended) FromAffine(p1 *PointAffine) *PointExtended {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.SetOne()
+	p.T.Mul(&p1.X, &p1.Y)
+	return p
+}
+
+// FromProj sets p in extended from p in projective
+func (p *PointExtended) FromProj(p1 *PointProj) *PointExtended {
+	var X, Y fr.Element
+	X.Mul(&p1.X, &p1.Z)
+	Y.Mul(&p1.Y, &p1.Z)
+	p.T.Mul(&p1.X, &p1.Y)
+	p.Z.Square(&p1.Z)
+	p.X.Set(&X)
+	p.Y.Set(&Y)
+	return p
+}
+
+// FromExtended sets p in affine from p in extended
+func (p *PointAffine) FromExtended(p1 *PointExtended) *PointAffine {
+	var zInv fr.Element
+	zInv.Inverse(&p1.Z)
+	p.X.Mul(&p1.X, &zInv)
+	p.Y.Mul(&p1.Y, &zInv)
+	return p
+}
+
+// FromExtended sets p in projective from p in extended
+func (p *PointProj) FromExtended(p1 *PointExtended) *PointProj {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.Set(&p1.Z)
+	return p
+}
+
+// Neg sets p to -p1 and returns it
+func (p *PointExtended) Neg(p1 *PointExtended) *PointExtended {
+	p.X.Neg(&p1.X)
+	p.Y = p1.Y
+	p.Z = p1.Z
+	p.T.Neg(&p1.T)
+	return p
+}
+
+// Add adds points in extended coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-extended.html#addition-add-2008-hwcd
+func (p *PointExtended) Add(p1, p2 *PointExtended) *PointExtended {
+
+	var A, B, C, D, E, F, G, H fr.Element
+	A.Mul(&p1.X, &p2.X)
+	B.Mul(&p1.Y, &p2.Y)
+	C.Mul(&p1.T, &p2.T).Mul(&C, &edwards.D)
+	D.Mul(&p1.Z, &p2.Z)
+	E.Add(&p1.X, &p1.Y)
+	F.Add(&p2.X, &p2.Y)
+	E.Mul(&E, &F).
+		Sub(&E, &A).
+		Sub(&E, &B)
+	F.Sub(&D, &C)
+	G.Add(&D, &C)
+	H.Mul(&edwards.A, &A)
+	H.Sub(&B, &H)
+
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
+
+	return p
+}
+
+// MixedAdd adds a point in extended coordinates and a point in affine coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-extended.html#addition-madd-2008-hwcd
+func (p *PointExtended) MixedAdd(p1 *PointExtended, p2 *PointAffine) *PointExtended {
+
+	var A, B, C, D, E, F, G, H fr.Element
+	A.Mul(&p1.X, &p2.X)
+	B.Mul(&p1.Y, &p2.Y)
+	C.Mul(&p2.X, &p2.Y).Mul(&C, &p1.T).Mul(&C, &edwards.D)
+	D.Set(&p1.Z)
+	E.Add(&p1.X, &p1.Y)
+	F.Add(&p2.X, &p2.Y)
+	E.Mul(&E, &F).
+		Sub(&E, &A).
+		Sub(&E, &B)
+	F.Sub(&D, &C)
+	G.Add(&D, &C)
+	H.Mul(&edwards.A, &A)
+	H.Sub(&B, &H)
+
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
+
+	return p
+}
+
+// Double doubles a point in extended coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-extended.html#doubling-dbl-2008-hwcd
+func (p *PointExtended) Double(p1 *PointExtended) *PointExtended {
+
+	var A, B, C, D, E, F, G, H fr.Element
+	A.Square(&p1.X)
+	B.Square(&p1.Y)
+	C.Square(&p1.Z).Double(&C)
+	D.Mul(&edwards.A, &A)
+	E.Add(&p1.X, &p1.Y).
+		Square(&E).
+		Sub(&E, &A).
+		Sub(&E, &B)
+	G.Add(&D, &B)
+	F.Sub(&G, &C)
+	H.Sub(&D, &B)
+
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
+
+	return p
+}
+
+// ClearCofactor sets p to cofactor*p1 and returns it
+func (p *PointExtended) ClearCofactor(p1 *PointExtended) *PointExtended {
+	var cofactor big.Int
+	edwards.Cofactor.ToBigInt(&cofactor)
+
+	var res PointExtended
+	res.Set(p1)
+	for i := cofactor.BitLen() - 2; i >= 0; i-- {
+		res.Double(&res)
+		if cofactor.Bit(i) == 1 {
+			res.Add(&res, p1)
+		}
+	}
+
+	return p.Set(&res)
+}
+
+// ScalarMul scalar multiplication of a point in extended coordinates,
+// with a fixed 4-bit window (see scalarMulWindowed)
+// For points known to be in the prime order subgroup, ScalarMulGLV is faster.
+// scalar NOT in Montgomery form
+// modifies p
+func (p *PointExtended) ScalarMul(p1 *PointExtended, scalar *big.Int) *PointExtended {
+	return p.scalarMulWindowed(p1, scalar)
+}
+
+// scalarMulWindowed scalar multiplication of a point in extended coordinates,
+// with a fixed 4-bit window
+func (p *PointExtended) scalarMulWindowed(p1 *PointExtended, scalar *big.Int) *PointExtended {
+	const c = 4
+	const wordSize = bits.UintSize
+
+	// table[i] = i*p1
+	var table [1 << c]PointExtended
+	table[0].setInfinity()
+	table[1].Set(p1)
+	for i := 2; i < len(table); i++ {
+		table[i].Add(&table[i-1], p1)
+	}
+
+	var res PointExtended
+	res.setInfinity()
+
+	sWords := scalar.Bits()
+	for i := len(sWords) - 1; i >= 0; i-- {
+		ithWord := sWords[i]
+		for k := wordSize - c; k >= 0; k -= c {
+			for j := 0; j < c; j++ {
+				res.Double(&res)
+			}
+			if w := (ithWord >> k) & (1<<c - 1); w != 0 {
+				res.Add(&res, &table[w])
+			}
+		}
+	}
+
+	return p.Set(&res)
+}
+
+// ScalarMulSecret scalar multiplication of a point in extended coordinates,
+// in constant time with respect to the scalar.
+//
+// It is a fixed 4-bit window scalar multiplication over a fixed number of bits, where the
+// multiples of p1 are selected in constant time, which relies on the completeness of the
+// twisted Edwards addition law.
+// The scalar is always read over 2*fr.Bytes bytes, and reduced in constant time modulo the order
+// of the curve (the cofactor times the order of the subgroup): it must be smaller than 2^(16*fr.Bytes).
+// modifies p
+func (p *PointExtended) ScalarMulSecret(p1 *PointExtended, scalar *big.Int) *PointExtended {
+	const c = 4
+
+	var order big.Int
+	edwards.Cofactor.ToBigInt(&order)
+	order.Mul(&order, &edwards.Order)
+	m := ctmod.NewModulus(&order)
+
+	var buf [2 * fr.Bytes]byte
+	scalar.FillBytes(buf[:])
+	var k [fr.Bytes]byte
+	ctmod.FillBytes(k[:], m.SetBytes(make([]uint64, m.Words()), buf[:]))
+
+	// table[i] = i*p1
+	var table [1 << c]PointExtended
+	table[0].setInfinity()
+	table[1].Set(p1)
+	for i := 2; i < len(table); i++ {
+		table[i].Add(&table[i-1], p1)
+	}
+
+	var res, q PointExtended
+	res.setInfinity()
+	for i := 0; i < fr.Bytes; i++ {
+		for _, w := range [2]uint8{k[i] >> c, k[i] & (1<<c - 1)} {
+			for j := 0; j < c; j++ {
+				res.Double(&res)
+			}
+			q.setInfinity()
+			for j := 1; j < len(table); j++ {
+				q.cmov(&table[j], uint64(subtle.ConstantTimeByteEq(uint8(j), w)))
+			}
+			res.Add(&res, &q)
+		}
+	}
+
+	return p.Set(&res)
+}
+
+// cmov sets p to q if b == 1, and leaves it unchanged if b == 0, in constant time
+func (p *PointExtended) cmov(q *PointExtended, b uint64) {
+	mask := -b
+	pc := [...]*fr.Element{&p.X, &p.Y, &p.Z, &p.T}
+	qc := [...]*fr.Element{&q.X, &q.Y, &q.Z, &q.T}
+	for i := range pc {
+		for j := 0; j < fr.Limbs; j++ {
+			pc[i][j] ^= mask & (pc[i][j] ^ qc[i][j])
+		}
+	}
+}
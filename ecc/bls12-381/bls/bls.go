@@ -0,0 +1,492 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bls provides BLS signatures over BLS12-381, following
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04
+//
+// Both the minimal-pubkey-size (public keys in G1, signatures in G2) and the
+// minimal-signature-size (public keys in G2, signatures in G1) variants are
+// supported, each with the basic, message augmentation and proof of possession
+// schemes.
+package bls
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/crypto/signature"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"golang.org/x/crypto/hkdf"
+)
+
+var (
+	errInvalidPublicKey    = errors.New("invalid public key")
+	errInvalidSignature    = errors.New("invalid signature")
+	errInvalidSecretKey    = errors.New("invalid secret key")
+	errShortSeed           = errors.New("key material must be at least 32 bytes")
+	errNoSignatures        = errors.New("at least one signature is required")
+	errNoPublicKeys        = errors.New("at least one public key is required")
+	errLengthMismatch      = errors.New("number of public keys and messages differ")
+	errSuiteMismatch       = errors.New("public keys belong to different ciphersuites")
+	errMessagesNotDistinct = errors.New("messages must be distinct in the basic scheme")
+	errNotPop              = errors.New("operation requires a proof of possession ciphersuite")
+)
+
+const (
+	sizeFr = fr.Bytes
+	sizeG1 = bls12381.SizeOfG1AffineCompressed
+	sizeG2 = bls12381.SizeOfG2AffineCompressed
+)
+
+// Suite identifies a BLS ciphersuite, that is a variant (where public keys and
+// signatures live) and a scheme (how rogue key attacks are prevented)
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04#section-4.2
+type Suite uint8
+
+const (
+	MinPkBasic  Suite = iota // public keys in G1, signatures in G2, basic scheme
+	MinPkAug                 // public keys in G1, signatures in G2, message augmentation scheme
+	MinPkPop                 // public keys in G1, signatures in G2, proof of possession scheme
+	MinSigBasic              // public keys in G2, signatures in G1, basic scheme
+	MinSigAug                // public keys in G2, signatures in G1, message augmentation scheme
+	MinSigPop                // public keys in G2, signatures in G1, proof of possession scheme
+)
+
+var suiteIDs = [...]string{
+	MinPkBasic:  "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_",
+	MinPkAug:    "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_AUG_",
+	MinPkPop:    "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_",
+	MinSigBasic: "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_",
+	MinSigAug:   "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_AUG_",
+	MinSigPop:   "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_",
+}
+
+var signatureSchemes = [...]signature.SignatureScheme{
+	MinPkBasic:  signature.BLS_BLS12_381_MINPK_BASIC,
+	MinPkAug:    signature.BLS_BLS12_381_MINPK_AUG,
+	MinPkPop:    signature.BLS_BLS12_381_MINPK_POP,
+	MinSigBasic: signature.BLS_BLS12_381_MINSIG_BASIC,
+	MinSigAug:   signature.BLS_BLS12_381_MINSIG_AUG,
+	MinSigPop:   signature.BLS_BLS12_381_MINSIG_POP,
+}
+
+// popDSTs domain separation tags of hash_pubkey_to_point in the proof of possession scheme
+var popDSTs = map[Suite]string{
+	MinPkPop:  "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_",
+	MinSigPop: "BLS_POP_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_",
+}
+
+// ID returns the ciphersuite ID, used as domain separation tag when hashing messages
+func (s Suite) ID() string {
+	return suiteIDs[s]
+}
+
+// minPk returns true if public keys are in G1 and signatures in G2
+func (s Suite) minPk() bool {
+	return s <= MinPkPop
+}
+
+// sizePublicKey returns the size of a serialized public key
+func (s Suite) sizePublicKey() int {
+	if s.minPk() {
+		return sizeG1
+	}
+	return sizeG2
+}
+
+// sizeSignature returns the size of a serialized signature
+func (s Suite) sizeSignature() int {
+	if s.minPk() {
+		return sizeG2
+	}
+	return sizeG1
+}
+
+func init() {
+	for s := range signatureSchemes {
+		suite := Suite(s)
+		signature.Register(signatureSchemes[s], func(r io.Reader) (signature.Signer, error) {
+			priv, err := GenerateKey(suite, r)
+			return &priv, err
+		})
+	}
+}
+
+// PublicKey bls public key. Only the point matching the variant of
+// the suite is used.
+type PublicKey struct {
+	Suite Suite
+	G1    bls12381.G1Affine // public key in the minimal-pubkey-size variant
+	G2    bls12381.G2Affine // public key in the minimal-signature-size variant
+}
+
+// PrivateKey private key of a bls instance
+type PrivateKey struct {
+	PublicKey PublicKey    // copy of the associated public key
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+}
+
+// GenerateKey reads 32 bytes of key material from r and derives a key pair with KeyGen.
+func GenerateKey(s Suite, r io.Reader) (PrivateKey, error) {
+	var ikm [32]byte
+	if _, err := io.ReadFull(r, ikm[:]); err != nil {
+		return PrivateKey{}, err
+	}
+	return KeyGen(s, ikm[:], nil)
+}
+
+// KeyGen deterministically derives a key pair from the secret key material ikm
+// (at least 32 bytes) and the optional keyInfo
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04#section-2.3
+func KeyGen(s Suite, ikm, keyInfo []byte) (PrivateKey, error) {
+	var priv PrivateKey
+	if len(ikm) < 32 {
+		return priv, errShortSeed
+	}
+
+	// L = ceil((3 * ceil(log2(r))) / 16)
+	const L = 48
+
+	// IKM || I2OSP(0, 1)
+	_ikm := make([]byte, len(ikm)+1)
+	copy(_ikm, ikm)
+
+	// key_info || I2OSP(L, 2)
+	info := make([]byte, len(keyInfo)+2)
+	copy(info, keyInfo)
+	info[len(keyInfo)+1] = L
+
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	okm := make([]byte, L)
+	var sk big.Int
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		prk := hkdf.Extract(sha256.New, _ikm, salt)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), okm); err != nil {
+			return priv, err
+		}
+		sk.SetBytes(okm).Mod(&sk, fr.Modulus())
+	}
+
+	sk.FillBytes(priv.scalar[:])
+	priv.PublicKey = skToPk(s, &sk)
+
+	return priv, nil
+}
+
+// skToPk returns the public key associated to the secret scalar sk
+func skToPk(s Suite, sk *big.Int) PublicKey {
+	pub := PublicKey{Suite: s}
+	_, _, g1Gen, g2Gen := bls12381.Generators()
+	if s.minPk() {
+		pub.G1.ScalarMultiplicationSecret(&g1Gen, sk)
+	} else {
+		pub.G2.ScalarMultiplicationSecret(&g2Gen, sk)
+	}
+	return pub
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(other signature.PublicKey) bool {
+	o, ok := other.(*PublicKey)
+	if !ok || o.Suite != pub.Suite {
+		return false
+	}
+	return subtle.ConstantTimeCompare(pub.Bytes(), o.Bytes()) == 1
+}
+
+// Public returns the public key associated to the private key.
+// From Signer interface defined in gnark/crypto/signature.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	pub := privKey.PublicKey
+	return &pub
+}
+
+// Sign signs a message with the ciphersuite of the key.
+// The message is hashed to the curve as specified by the ciphersuite,
+// so hFunc is ignored.
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04#section-3
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	s := privKey.PublicKey.Suite
+	if s == MinPkAug || s == MinSigAug {
+		message = augment(&privKey.PublicKey, message)
+	}
+	return privKey.coreSign(message, []byte(s.ID()))
+}
+
+// Verify verifies a bls signature of message with the ciphersuite of the key.
+// hFunc is ignored, see Sign.
+func (pub *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	s := pub.Suite
+	if s == MinPkAug || s == MinSigAug {
+		message = augment(pub, message)
+	}
+	return coreVerify(pub, message, sigBin, []byte(s.ID()))
+}
+
+// PopProve returns a proof of possession of the private key
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04#section-3.3.2
+func (privKey *PrivateKey) PopProve() ([]byte, error) {
+	dst, ok := popDSTs[privKey.PublicKey.Suite]
+	if !ok {
+		return nil, errNotPop
+	}
+	return privKey.coreSign(privKey.PublicKey.Bytes(), []byte(dst))
+}
+
+// PopVerify checks a proof of possession of the private key associated to pub
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04#section-3.3.3
+func (pub *PublicKey) PopVerify(proof []byte) (bool, error) {
+	dst, ok := popDSTs[pub.Suite]
+	if !ok {
+		return false, errNotPop
+	}
+	return coreVerify(pub, pub.Bytes(), proof, []byte(dst))
+}
+
+// Aggregate aggregates signatures of the suite s into a single signature
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04#section-2.8
+func Aggregate(s Suite, signatures [][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errNoSignatures
+	}
+	if s.minPk() {
+		var aggregate bls12381.G2Jac
+		for _, sigBin := range signatures {
+			var sig bls12381.G2Affine
+			if err := setSignatureG2(&sig, sigBin); err != nil {
+				return nil, err
+			}
+			aggregate.AddMixed(&sig)
+		}
+		var res bls12381.G2Affine
+		res.FromJacobian(&aggregate)
+		b := res.Bytes()
+		return b[:], nil
+	}
+	var aggregate bls12381.G1Jac
+	for _, sigBin := range signatures {
+		var sig bls12381.G1Affine
+		if err := setSignatureG1(&sig, sigBin); err != nil {
+			return nil, err
+		}
+		aggregate.AddMixed(&sig)
+	}
+	var res bls12381.G1Affine
+	res.FromJacobian(&aggregate)
+	b := res.Bytes()
+	return b[:], nil
+}
+
+// AggregateVerify verifies an aggregate signature of messages[i] under publicKeys[i].
+// All the public keys must belong to the same ciphersuite. In the basic scheme
+// the messages must be distinct.
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04#section-3.1.1
+func AggregateVerify(publicKeys []PublicKey, messages [][]byte, sigBin []byte) (bool, error) {
+	if len(publicKeys) == 0 {
+		return false, errNoPublicKeys
+	}
+	if len(publicKeys) != len(messages) {
+		return false, errLengthMismatch
+	}
+	s := publicKeys[0].Suite
+	for i := range publicKeys {
+		if publicKeys[i].Suite != s {
+			return false, errSuiteMismatch
+		}
+	}
+
+	switch s {
+	case MinPkBasic, MinSigBasic:
+		seen := make(map[string]struct{}, len(messages))
+		for _, m := range messages {
+			if _, ok := seen[string(m)]; ok {
+				return false, errMessagesNotDistinct
+			}
+			seen[string(m)] = struct{}{}
+		}
+	case MinPkAug, MinSigAug:
+		augmented := make([][]byte, len(messages))
+		for i := range messages {
+			augmented[i] = augment(&publicKeys[i], messages[i])
+		}
+		messages = augmented
+	}
+
+	return coreAggregateVerify(publicKeys, messages, sigBin, []byte(s.ID()))
+}
+
+// FastAggregateVerify verifies an aggregate signature of the same message under
+// publicKeys. It is only available in the proof of possession scheme, and the
+// proof of possession of each public key must have been checked with PopVerify.
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04#section-3.3.4
+func FastAggregateVerify(publicKeys []PublicKey, message, sigBin []byte) (bool, error) {
+	if len(publicKeys) == 0 {
+		return false, errNoPublicKeys
+	}
+	s := publicKeys[0].Suite
+	if _, ok := popDSTs[s]; !ok {
+		return false, errNotPop
+	}
+
+	aggregate := PublicKey{Suite: s}
+	if s.minPk() {
+		var acc bls12381.G1Jac
+		for i := range publicKeys {
+			if publicKeys[i].Suite != s {
+				return false, errSuiteMismatch
+			}
+			acc.AddMixed(&publicKeys[i].G1)
+		}
+		aggregate.G1.FromJacobian(&acc)
+	} else {
+		var acc bls12381.G2Jac
+		for i := range publicKeys {
+			if publicKeys[i].Suite != s {
+				return false, errSuiteMismatch
+			}
+			acc.AddMixed(&publicKeys[i].G2)
+		}
+		aggregate.G2.FromJacobian(&acc)
+	}
+
+	return coreVerify(&aggregate, message, sigBin, []byte(s.ID()))
+}
+
+// augment prepends the serialized public key to the message (message augmentation scheme)
+func augment(pub *PublicKey, message []byte) []byte {
+	pk := pub.Bytes()
+	res := make([]byte, 0, len(pk)+len(message))
+	res = append(res, pk...)
+	return append(res, message...)
+}
+
+// coreSign hashes message to the signature group with dst and multiplies it by the secret scalar
+func (privKey *PrivateKey) coreSign(message, dst []byte) ([]byte, error) {
+	var sk big.Int
+	sk.SetBytes(privKey.scalar[:])
+
+	if privKey.PublicKey.Suite.minPk() {
+		Q, err := bls12381.HashToCurveG2SSWU(message, dst)
+		if err != nil {
+			return nil, err
+		}
+		Q.ScalarMultiplicationSecret(&Q, &sk)
+		res := Q.Bytes()
+		return res[:], nil
+	}
+
+	Q, err := bls12381.HashToCurveG1SSWU(message, dst)
+	if err != nil {
+		return nil, err
+	}
+	Q.ScalarMultiplicationSecret(&Q, &sk)
+	res := Q.Bytes()
+	return res[:], nil
+}
+
+// coreVerify checks that sigBin is a signature of message under pub, hashed with dst
+func coreVerify(pub *PublicKey, message, sigBin, dst []byte) (bool, error) {
+	return coreAggregateVerify([]PublicKey{*pub}, [][]byte{message}, sigBin, dst)
+}
+
+// coreAggregateVerify checks that sigBin is an aggregate signature of messages[i] under publicKeys[i]
+func coreAggregateVerify(publicKeys []PublicKey, messages [][]byte, sigBin, dst []byte) (bool, error) {
+	for i := range publicKeys {
+		if !publicKeys[i].keyValidate() {
+			return false, errInvalidPublicKey
+		}
+	}
+
+	_, _, g1Gen, g2Gen := bls12381.Generators()
+	n := len(publicKeys)
+	P := make([]bls12381.G1Affine, n+1)
+	Q := make([]bls12381.G2Affine, n+1)
+
+	if publicKeys[0].Suite.minPk() {
+		// prod e(PK_i, H(m_i)) == e(P, sig)
+		if err := setSignatureG2(&Q[n], sigBin); err != nil {
+			return false, err
+		}
+		P[n].Neg(&g1Gen)
+		for i := 0; i < n; i++ {
+			h, err := bls12381.HashToCurveG2SSWU(messages[i], dst)
+			if err != nil {
+				return false, err
+			}
+			P[i].Set(&publicKeys[i].G1)
+			Q[i].Set(&h)
+		}
+	} else {
+		// prod e(H(m_i), PK_i) == e(sig, P)
+		if err := setSignatureG1(&P[n], sigBin); err != nil {
+			return false, err
+		}
+		P[n].Neg(&P[n])
+		Q[n].Set(&g2Gen)
+		for i := 0; i < n; i++ {
+			h, err := bls12381.HashToCurveG1SSWU(messages[i], dst)
+			if err != nil {
+				return false, err
+			}
+			P[i].Set(&h)
+			Q[i].Set(&publicKeys[i].G2)
+		}
+	}
+
+	return bls12381.PairingCheck(P, Q)
+}
+
+// keyValidate checks that pub is a valid, non-identity point in the prime order subgroup
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-04#section-2.5
+func (pub *PublicKey) keyValidate() bool {
+	if pub.Suite.minPk() {
+		return !pub.G1.IsInfinity() && pub.G1.IsInSubGroup()
+	}
+	return !pub.G2.IsInfinity() && pub.G2.IsInSubGroup()
+}
+
+// setSignatureG1 deserializes a signature in G1, checking it is in the prime order subgroup
+func setSignatureG1(sig *bls12381.G1Affine, sigBin []byte) error {
+	if len(sigBin) != sizeG1 {
+		return errInvalidSignature
+	}
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return err
+	}
+	return nil
+}
+
+// setSignatureG2 deserializes a signature in G2, checking it is in the prime order subgroup
+func setSignatureG2(sig *bls12381.G2Affine, sigBin []byte) error {
+	if len(sigBin) != sizeG2 {
+		return errInvalidSignature
+	}
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return err
+	}
+	return nil
+}
+
+// isZero returns true if b only contains zeros
+func isZero(b []byte) bool {
+	return bytes.Equal(b, make([]byte, len(b)))
+}
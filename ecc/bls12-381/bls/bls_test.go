@@ -0,0 +1,286 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/crypto/signature"
+)
+
+var suites = []Suite{MinPkBasic, MinPkAug, MinPkPop, MinSigBasic, MinSigAug, MinSigPop}
+
+func TestSkToPk(t *testing.T) {
+	// test vector from the Ethereum consensus specs (minimal-pubkey-size, proof of possession)
+	sk, _ := new(big.Int).SetString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3", 16)
+	pub := skToPk(MinPkPop, sk)
+	expected := "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a"
+	if hex.EncodeToString(pub.Bytes()) != expected {
+		t.Fatal("wrong public key")
+	}
+}
+
+func TestKeyGen(t *testing.T) {
+	ikm := make([]byte, 32)
+	priv1, err := KeyGen(MinPkBasic, ikm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv2, err := KeyGen(MinPkBasic, ikm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(priv1.Bytes(), priv2.Bytes()) {
+		t.Fatal("KeyGen should be deterministic")
+	}
+	priv3, err := KeyGen(MinPkBasic, ikm, []byte("info"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(priv1.Bytes(), priv3.Bytes()) {
+		t.Fatal("KeyGen should depend on key info")
+	}
+	if _, err := KeyGen(MinPkBasic, ikm[:31], nil); err == nil {
+		t.Fatal("KeyGen should reject short key material")
+	}
+}
+
+func TestSerialization(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src)
+
+	for _, s := range suites {
+		privKey1, err := signatureSchemes[s].New(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey1 := privKey1.Public()
+
+		privKey2 := &PrivateKey{PublicKey: PublicKey{Suite: s}}
+		if _, err := privKey2.SetBytes(privKey1.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(privKey1.Bytes(), privKey2.Bytes()) {
+			t.Fatal("Error serialize(deserialize(.))")
+		}
+
+		pubKey2 := &PublicKey{Suite: s}
+		if _, err := pubKey2.SetBytes(pubKey1.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if !pubKey1.Equal(pubKey2) {
+			t.Fatal("Error serialize(deserialize(.))")
+		}
+		if len(pubKey1.Bytes()) != s.sizePublicKey() {
+			t.Fatal("wrong public key size")
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src)
+
+	for _, s := range suites {
+		var signer signature.Signer
+		signer, err := signatureSchemes[s].New(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey := signer.Public()
+
+		msg := []byte("hello")
+		sig, err := signer.Sign(msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sig) != s.sizeSignature() {
+			t.Fatal("wrong signature size")
+		}
+
+		// verifies correct msg
+		res, err := pubKey.Verify(sig, msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res {
+			t.Fatal("Verify correct signature should return true")
+		}
+
+		// verifies wrong msg
+		res, err = pubKey.Verify(sig, []byte("hellp"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res {
+			t.Fatal("Verify wrong signature should be false")
+		}
+
+		// a signature from another suite of the same variant must not verify
+		other := (s + 1) % 3
+		if !s.minPk() {
+			other += MinSigBasic
+		}
+		otherKey := *pubKey.(*PublicKey)
+		otherKey.Suite = other
+		res, _ = otherKey.Verify(sig, msg, nil)
+		if res {
+			t.Fatal("Verify should be domain separated across suites")
+		}
+	}
+}
+
+func TestAggregateVerify(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src)
+
+	const n = 3
+	for _, s := range suites {
+		publicKeys := make([]PublicKey, n)
+		messages := make([][]byte, n)
+		signatures := make([][]byte, n)
+		for i := 0; i < n; i++ {
+			priv, err := GenerateKey(s, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicKeys[i] = priv.PublicKey
+			messages[i] = []byte{byte(i)}
+			if signatures[i], err = priv.Sign(messages[i], nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sig, err := Aggregate(s, signatures)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := AggregateVerify(publicKeys, messages, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res {
+			t.Fatal("AggregateVerify correct signature should return true")
+		}
+
+		messages[0], messages[1] = messages[1], messages[0]
+		res, err = AggregateVerify(publicKeys, messages, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res {
+			t.Fatal("AggregateVerify wrong signature should return false")
+		}
+
+		messages[1] = messages[0]
+		_, err = AggregateVerify(publicKeys, messages, sig)
+		if (s == MinPkBasic || s == MinSigBasic) && err != errMessagesNotDistinct {
+			t.Fatal("AggregateVerify should reject duplicate messages in the basic scheme")
+		}
+	}
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src)
+
+	const n = 3
+	msg := []byte("hello")
+	for _, s := range suites {
+		publicKeys := make([]PublicKey, n)
+		signatures := make([][]byte, n)
+		for i := 0; i < n; i++ {
+			priv, err := GenerateKey(s, r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicKeys[i] = priv.PublicKey
+
+			proof, err := priv.PopProve()
+			if s != MinPkPop && s != MinSigPop {
+				if err != errNotPop {
+					t.Fatal("PopProve should fail outside of the proof of possession scheme")
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ok, err := publicKeys[i].PopVerify(proof); err != nil || !ok {
+					t.Fatal("PopVerify correct proof should return true")
+				}
+				if ok, _ := publicKeys[i].PopVerify(signatures[0]); ok {
+					t.Fatal("PopVerify wrong proof should return false")
+				}
+			}
+
+			if signatures[i], err = priv.Sign(msg, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sig, err := Aggregate(s, signatures)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := FastAggregateVerify(publicKeys, msg, sig)
+		if s != MinPkPop && s != MinSigPop {
+			if err != errNotPop {
+				t.Fatal("FastAggregateVerify should fail outside of the proof of possession scheme")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res {
+			t.Fatal("FastAggregateVerify correct signature should return true")
+		}
+
+		res, err = FastAggregateVerify(publicKeys[1:], msg, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res {
+			t.Fatal("FastAggregateVerify wrong signature should return false")
+		}
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	priv, _ := GenerateKey(MinPkPop, rand.New(rand.NewSource(0)))
+	msg := []byte("hello")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = priv.Sign(msg, nil)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	priv, _ := GenerateKey(MinPkPop, rand.New(rand.NewSource(0)))
+	msg := []byte("hello")
+	sig, _ := priv.Sign(msg, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = priv.PublicKey.Verify(sig, msg, nil)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls
+
+import (
+	"crypto/subtle"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// Bytes returns the binary representation of pk, that is the compressed
+// point in G1 (minimal-pubkey-size) or G2 (minimal-signature-size),
+// as returned by G1Affine.Bytes and G2Affine.Bytes.
+func (pk *PublicKey) Bytes() []byte {
+	if pk.Suite.minPk() {
+		res := pk.G1.Bytes()
+		return res[:]
+	}
+	res := pk.G2.Bytes()
+	return res[:]
+}
+
+// SetBytes sets pk from binary representation in buf.
+// The variant (G1 or G2) is given by pk.Suite, which must be set beforehand.
+// The point is checked to be in the prime order subgroup.
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < pk.Suite.sizePublicKey() {
+		return 0, io.ErrShortBuffer
+	}
+	if pk.Suite.minPk() {
+		return pk.G1.SetBytes(buf[:sizeG1])
+	}
+	return pk.G2.SetBytes(buf[:sizeG2])
+}
+
+// Bytes returns the binary representation of privKey,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	pubkBin := privKey.PublicKey.Bytes()
+	res := make([]byte, len(pubkBin)+sizeFr)
+	subtle.ConstantTimeCopy(1, res[:len(pubkBin)], pubkBin)
+	subtle.ConstantTimeCopy(1, res[len(pubkBin):], privKey.scalar[:])
+	return res
+}
+
+// SetBytes sets privKey from buf, where buf is interpreted
+// as publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// The ciphersuite is given by privKey.PublicKey.Suite, which must be set beforehand.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	n, err := privKey.PublicKey.SetBytes(buf)
+	if err != nil {
+		return 0, err
+	}
+	if len(buf) < n+sizeFr {
+		return n, io.ErrShortBuffer
+	}
+
+	// 1 <= SK < r
+	var sk big.Int
+	sk.SetBytes(buf[n : n+sizeFr])
+	if isZero(buf[n:n+sizeFr]) || sk.Cmp(fr.Modulus()) != -1 {
+		return n, errInvalidSecretKey
+	}
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[n:n+sizeFr])
+	n += sizeFr
+
+	return n, nil
+}
@@ -0,0 +1,373 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eip2537 implements the BLS12-381 precompiled contracts of EIP-2537,
+// with their byte formats.
+//
+// A field element is encoded as a 64-byte big-endian integer, whose 16 top bytes must be zero,
+// and which must be smaller than the modulus. An element c0 + c1*u of Fp2 is encoded as c0 || c1.
+// A point is encoded as x || y, and the point at infinity as zeros. A scalar is a 32-byte
+// big-endian integer, which is not required to be smaller than the group order.
+//
+// Points must be on the curve, and, for the multiplications and the pairing, in the prime
+// order subgroup.
+package eip2537
+
+import (
+	"errors"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// SizeOfFp size in bytes of an encoded field element
+	SizeOfFp = 64
+	// SizeOfFp2 size in bytes of an encoded element of Fp2
+	SizeOfFp2 = 2 * SizeOfFp
+	// SizeOfG1 size in bytes of an encoded G1 point
+	SizeOfG1 = 2 * SizeOfFp
+	// SizeOfG2 size in bytes of an encoded G2 point
+	SizeOfG2 = 2 * SizeOfFp2
+	// SizeOfScalar size in bytes of an encoded scalar
+	SizeOfScalar = 32
+	// SizeOfPair size in bytes of a (G1, G2) pair of the pairing input
+	SizeOfPair = SizeOfG1 + SizeOfG2
+
+	// paddingSize number of top bytes of an encoded field element that must be zero
+	paddingSize = SizeOfFp - fp.Bytes
+)
+
+var (
+	ErrInvalidInputLength          = errors.New("invalid input length")
+	ErrInvalidFieldElementTopBytes = errors.New("invalid field element top bytes")
+	ErrFieldElementExceedsModulus  = errors.New("must be less than modulus")
+	ErrPointNotOnCurve             = errors.New("point is not on curve")
+	ErrG1PointNotInSubgroup        = errors.New("g1 point is not on correct subgroup")
+	ErrG2PointNotInSubgroup        = errors.New("g2 point is not on correct subgroup")
+)
+
+// G1Add implements the G1ADD precompile: the input is two encoded G1 points,
+// which are not required to be in the subgroup, and their sum is returned.
+func G1Add(input []byte) ([]byte, error) {
+	if len(input) != 2*SizeOfG1 {
+		return nil, ErrInvalidInputLength
+	}
+	var p, q bls12381.G1Affine
+	if err := UnmarshalG1(&p, input[:SizeOfG1]); err != nil {
+		return nil, err
+	}
+	if err := UnmarshalG1(&q, input[SizeOfG1:]); err != nil {
+		return nil, err
+	}
+
+	var res, _q bls12381.G1Jac
+	res.FromAffine(&p)
+	_q.FromAffine(&q)
+	res.AddAssign(&_q)
+	p.FromJacobian(&res)
+
+	return MarshalG1(&p), nil
+}
+
+// G1Mul implements the G1MUL precompile: the input is an encoded G1 point and
+// a scalar, and the point multiplied by the scalar is returned.
+func G1Mul(input []byte) ([]byte, error) {
+	if len(input) != SizeOfG1+SizeOfScalar {
+		return nil, ErrInvalidInputLength
+	}
+	var p bls12381.G1Affine
+	if err := unmarshalG1InSubgroup(&p, input[:SizeOfG1]); err != nil {
+		return nil, err
+	}
+
+	// the point is in the subgroup of order r
+	var s big.Int
+	s.SetBytes(input[SizeOfG1:]).Mod(&s, fr.Modulus())
+	p.ScalarMultiplication(&p, &s)
+
+	return MarshalG1(&p), nil
+}
+
+// G1MSM implements the G1MSM precompile: the input is a non-empty sequence of
+// encoded (G1 point, scalar) pairs, and the multi-scalar multiplication is returned.
+func G1MSM(input []byte) ([]byte, error) {
+	const pairSize = SizeOfG1 + SizeOfScalar
+	n := len(input) / pairSize
+	if n == 0 || len(input)%pairSize != 0 {
+		return nil, ErrInvalidInputLength
+	}
+
+	points := make([]bls12381.G1Affine, n)
+	scalars := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		pair := input[i*pairSize : (i+1)*pairSize]
+		if err := unmarshalG1InSubgroup(&points[i], pair[:SizeOfG1]); err != nil {
+			return nil, err
+		}
+		setScalar(&scalars[i], pair[SizeOfG1:])
+	}
+
+	var res bls12381.G1Affine
+	res.MultiExp(points, scalars)
+
+	return MarshalG1(&res), nil
+}
+
+// G2Add implements the G2ADD precompile: the input is two encoded G2 points,
+// which are not required to be in the subgroup, and their sum is returned.
+func G2Add(input []byte) ([]byte, error) {
+	if len(input) != 2*SizeOfG2 {
+		return nil, ErrInvalidInputLength
+	}
+	var p, q bls12381.G2Affine
+	if err := UnmarshalG2(&p, input[:SizeOfG2]); err != nil {
+		return nil, err
+	}
+	if err := UnmarshalG2(&q, input[SizeOfG2:]); err != nil {
+		return nil, err
+	}
+
+	var res, _q bls12381.G2Jac
+	res.FromAffine(&p)
+	_q.FromAffine(&q)
+	res.AddAssign(&_q)
+	p.FromJacobian(&res)
+
+	return MarshalG2(&p), nil
+}
+
+// G2Mul implements the G2MUL precompile: the input is an encoded G2 point and
+// a scalar, and the point multiplied by the scalar is returned.
+func G2Mul(input []byte) ([]byte, error) {
+	if len(input) != SizeOfG2+SizeOfScalar {
+		return nil, ErrInvalidInputLength
+	}
+	var p bls12381.G2Affine
+	if err := unmarshalG2InSubgroup(&p, input[:SizeOfG2]); err != nil {
+		return nil, err
+	}
+
+	// the point is in the subgroup of order r
+	var s big.Int
+	s.SetBytes(input[SizeOfG2:]).Mod(&s, fr.Modulus())
+	p.ScalarMultiplication(&p, &s)
+
+	return MarshalG2(&p), nil
+}
+
+// G2MSM implements the G2MSM precompile: the input is a non-empty sequence of
+// encoded (G2 point, scalar) pairs, and the multi-scalar multiplication is returned.
+func G2MSM(input []byte) ([]byte, error) {
+	const pairSize = SizeOfG2 + SizeOfScalar
+	n := len(input) / pairSize
+	if n == 0 || len(input)%pairSize != 0 {
+		return nil, ErrInvalidInputLength
+	}
+
+	points := make([]bls12381.G2Affine, n)
+	scalars := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		pair := input[i*pairSize : (i+1)*pairSize]
+		if err := unmarshalG2InSubgroup(&points[i], pair[:SizeOfG2]); err != nil {
+			return nil, err
+		}
+		setScalar(&scalars[i], pair[SizeOfG2:])
+	}
+
+	var res bls12381.G2Affine
+	res.MultiExp(points, scalars)
+
+	return MarshalG2(&res), nil
+}
+
+// Pairing implements the PAIRING precompile: the input is a non-empty sequence
+// of encoded (G1, G2) pairs, and the output is the 32-byte big-endian encoding of 1
+// if the product of their pairings is 1, and 0 otherwise.
+func Pairing(input []byte) ([]byte, error) {
+	n := len(input) / SizeOfPair
+	if n == 0 || len(input)%SizeOfPair != 0 {
+		return nil, ErrInvalidInputLength
+	}
+
+	P := make([]bls12381.G1Affine, n)
+	Q := make([]bls12381.G2Affine, n)
+	for i := 0; i < n; i++ {
+		pair := input[i*SizeOfPair : (i+1)*SizeOfPair]
+		if err := unmarshalG1InSubgroup(&P[i], pair[:SizeOfG1]); err != nil {
+			return nil, err
+		}
+		if err := unmarshalG2InSubgroup(&Q[i], pair[SizeOfG1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	ok, err := bls12381.PairingCheck(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, 32)
+	if ok {
+		res[31] = 1
+	}
+	return res, nil
+}
+
+// MapFpToG1 implements the MAP_FP_TO_G1 precompile: the input is an encoded field element,
+// which is mapped to G1 with the SSWU map of the BLS12381G1_XMD:SHA-256_SSWU_RO_ suite
+// (map_to_curve followed by clear_cofactor, see RFC 9380).
+func MapFpToG1(input []byte) ([]byte, error) {
+	if len(input) != SizeOfFp {
+		return nil, ErrInvalidInputLength
+	}
+	var u fp.Element
+	if err := setFp(&u, input); err != nil {
+		return nil, err
+	}
+	res := bls12381.MapToCurveG1SSWU(u)
+	return MarshalG1(&res), nil
+}
+
+// MapFp2ToG2 implements the MAP_FP2_TO_G2 precompile: the input is an encoded element of Fp2,
+// which is mapped to G2 with the SSWU map of the BLS12381G2_XMD:SHA-256_SSWU_RO_ suite
+// (map_to_curve followed by clear_cofactor, see RFC 9380).
+func MapFp2ToG2(input []byte) ([]byte, error) {
+	if len(input) != SizeOfFp2 {
+		return nil, ErrInvalidInputLength
+	}
+	var u bls12381.E2
+	if err := setFp(&u.A0, input[:SizeOfFp]); err != nil {
+		return nil, err
+	}
+	if err := setFp(&u.A1, input[SizeOfFp:]); err != nil {
+		return nil, err
+	}
+	res := bls12381.MapToCurveG2SSWU(u)
+	return MarshalG2(&res), nil
+}
+
+// MarshalG1 returns the encoding of p, x || y
+func MarshalG1(p *bls12381.G1Affine) []byte {
+	res := make([]byte, SizeOfG1)
+	if p.IsInfinity() {
+		return res
+	}
+	putFp(res[:SizeOfFp], &p.X)
+	putFp(res[SizeOfFp:], &p.Y)
+	return res
+}
+
+// UnmarshalG1 sets p from the encoding in buf (see MarshalG1), and checks
+// that it is on the curve. Zeros decode to the point at infinity.
+func UnmarshalG1(p *bls12381.G1Affine, buf []byte) error {
+	if len(buf) != SizeOfG1 {
+		return ErrInvalidInputLength
+	}
+	if err := setFp(&p.X, buf[:SizeOfFp]); err != nil {
+		return err
+	}
+	if err := setFp(&p.Y, buf[SizeOfFp:]); err != nil {
+		return err
+	}
+	if !p.IsInfinity() && !p.IsOnCurve() {
+		return ErrPointNotOnCurve
+	}
+	return nil
+}
+
+// MarshalG2 returns the encoding of p, x.A0 || x.A1 || y.A0 || y.A1
+func MarshalG2(p *bls12381.G2Affine) []byte {
+	res := make([]byte, SizeOfG2)
+	if p.IsInfinity() {
+		return res
+	}
+	toPut := []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1}
+	for i, e := range toPut {
+		putFp(res[i*SizeOfFp:(i+1)*SizeOfFp], e)
+	}
+	return res
+}
+
+// UnmarshalG2 sets p from the encoding in buf (see MarshalG2), and checks
+// that it is on the curve. Zeros decode to the point at infinity.
+func UnmarshalG2(p *bls12381.G2Affine, buf []byte) error {
+	if len(buf) != SizeOfG2 {
+		return ErrInvalidInputLength
+	}
+	toSet := []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1}
+	for i, e := range toSet {
+		if err := setFp(e, buf[i*SizeOfFp:(i+1)*SizeOfFp]); err != nil {
+			return err
+		}
+	}
+	if !p.IsInfinity() && !p.IsOnCurve() {
+		return ErrPointNotOnCurve
+	}
+	return nil
+}
+
+// unmarshalG1InSubgroup is UnmarshalG1, followed by a subgroup check
+func unmarshalG1InSubgroup(p *bls12381.G1Affine, buf []byte) error {
+	if err := UnmarshalG1(p, buf); err != nil {
+		return err
+	}
+	if !p.IsInfinity() && !p.IsInSubGroup() {
+		return ErrG1PointNotInSubgroup
+	}
+	return nil
+}
+
+// unmarshalG2InSubgroup is UnmarshalG2, followed by a subgroup check
+func unmarshalG2InSubgroup(p *bls12381.G2Affine, buf []byte) error {
+	if err := UnmarshalG2(p, buf); err != nil {
+		return err
+	}
+	if !p.IsInfinity() && !p.IsInSubGroup() {
+		return ErrG2PointNotInSubgroup
+	}
+	return nil
+}
+
+// putFp writes the 64-byte big-endian encoding of e in buf
+func putFp(buf []byte, e *fp.Element) {
+	b := e.Bytes()
+	copy(buf[paddingSize:], b[:])
+}
+
+// setFp sets e from the 64-byte big-endian integer in buf, whose top bytes
+// must be zero and which must be smaller than the modulus
+func setFp(e *fp.Element, buf []byte) error {
+	for i := 0; i < paddingSize; i++ {
+		if buf[i] != 0 {
+			return ErrInvalidFieldElementTopBytes
+		}
+	}
+	var v big.Int
+	v.SetBytes(buf[paddingSize:SizeOfFp])
+	if v.Cmp(fp.Modulus()) != -1 {
+		return ErrFieldElementExceedsModulus
+	}
+	e.SetBigInt(&v)
+	return nil
+}
+
+// setScalar sets s from the 32-byte big-endian integer in buf, reduced modulo r,
+// in regular form (as expected by MultiExp)
+func setScalar(s *fr.Element, buf []byte) {
+	var v big.Int
+	v.SetBytes(buf).Mod(&v, fr.Modulus())
+	s.SetBigInt(&v).FromMont()
+}
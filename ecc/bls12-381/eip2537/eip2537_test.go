@@ -0,0 +1,180 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip2537
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+// precompileTest is a test vector, in the format of go-ethereum's core/vm/testdata/precompiles.
+// The map vectors are the BLS12381G1_XMD:SHA-256_SSWU_NU_ and BLS12381G2_XMD:SHA-256_SSWU_NU_
+// vectors of RFC 9380 (u -> P); the other vectors are built from multiples of the generators.
+// The official EIP-2537 files use the same names and format, and can replace them as is.
+type precompileTest struct {
+	Input, Expected string
+	Name            string
+}
+
+// precompileFailureTest is a failing test vector, in the format of the fail-*.json files of
+// go-ethereum's core/vm/testdata/precompiles
+type precompileFailureTest struct {
+	Input         string
+	ExpectedError string
+	Name          string
+}
+
+var precompiles = map[string]func([]byte) ([]byte, error){
+	"blsG1Add":      G1Add,
+	"blsG1Mul":      G1Mul,
+	"blsG1MultiExp": G1MSM,
+	"blsG2Add":      G2Add,
+	"blsG2Mul":      G2Mul,
+	"blsG2MultiExp": G2MSM,
+	"blsPairing":    Pairing,
+	"blsMapG1":      MapFpToG1,
+	"blsMapG2":      MapFp2ToG2,
+}
+
+func loadJSON(t *testing.T, name string, v interface{}) {
+	data, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPrecompiles(t *testing.T) {
+	for name, f := range precompiles {
+		var tests []precompileTest
+		loadJSON(t, name, &tests)
+		for _, test := range tests {
+			input, _ := hex.DecodeString(test.Input)
+			res, err := f(input)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", name, test.Name, err)
+			}
+			if hex.EncodeToString(res) != test.Expected {
+				t.Fatalf("%s/%s: wrong output", name, test.Name)
+			}
+		}
+	}
+}
+
+func TestPrecompilesErrors(t *testing.T) {
+	for name, f := range precompiles {
+		var tests []precompileFailureTest
+		loadJSON(t, "fail-"+name, &tests)
+		if len(tests) == 0 {
+			t.Fatalf("%s: no failing test vectors", name)
+		}
+		for _, test := range tests {
+			input, _ := hex.DecodeString(test.Input)
+			_, err := f(input)
+			if err == nil || err.Error() != test.ExpectedError {
+				t.Fatalf("%s/%s: expected %q, got %v", name, test.Name, test.ExpectedError, err)
+			}
+		}
+	}
+}
+
+func TestAddNoSubgroupCheck(t *testing.T) {
+	// points of the curves which are not in the prime order subgroups
+	// (the curves are y**2 = x**3 + 4 and y**2 = x**3 + 4*(1+u))
+	var p bls12381.G1Affine
+	var one, b fp.Element
+	one.SetOne()
+	b.SetUint64(4)
+	for {
+		var rhs fp.Element
+		p.X.Add(&p.X, &one)
+		rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, &b)
+		if rhs.Legendre() == 1 {
+			p.Y.Sqrt(&rhs)
+			break
+		}
+	}
+	if !p.IsOnCurve() || p.IsInSubGroup() {
+		t.Fatal("invalid test point")
+	}
+
+	var q bls12381.G2Affine
+	var bTwist bls12381.E2
+	bTwist.A0.SetUint64(4)
+	bTwist.A1.SetUint64(4)
+	for {
+		var rhs bls12381.E2
+		q.X.A0.Add(&q.X.A0, &one)
+		rhs.Square(&q.X).Mul(&rhs, &q.X).Add(&rhs, &bTwist)
+		if rhs.Legendre() == 1 {
+			q.Y.Sqrt(&rhs)
+			break
+		}
+	}
+	if !q.IsOnCurve() || q.IsInSubGroup() {
+		t.Fatal("invalid test point")
+	}
+
+	// G1ADD and G2ADD don't check the subgroup
+	_, _, g1, g2 := bls12381.Generators()
+	if _, err := G1Add(append(MarshalG1(&p), MarshalG1(&g1)...)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := G2Add(append(MarshalG2(&q), MarshalG2(&g2)...)); err != nil {
+		t.Fatal(err)
+	}
+	// but G1MUL and G2MUL do
+	scalar := make([]byte, SizeOfScalar)
+	if _, err := G1Mul(append(MarshalG1(&p), scalar...)); err != ErrG1PointNotInSubgroup {
+		t.Fatalf("expected %v, got %v", ErrG1PointNotInSubgroup, err)
+	}
+	if _, err := G2Mul(append(MarshalG2(&q), scalar...)); err != ErrG2PointNotInSubgroup {
+		t.Fatalf("expected %v, got %v", ErrG2PointNotInSubgroup, err)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	_, _, p, q := bls12381.Generators()
+
+	var p2 bls12381.G1Affine
+	if err := UnmarshalG1(&p2, MarshalG1(&p)); err != nil || !p2.Equal(&p) {
+		t.Fatal("unmarshal(marshal(G1)) failed")
+	}
+	var q2 bls12381.G2Affine
+	if err := UnmarshalG2(&q2, MarshalG2(&q)); err != nil || !q2.Equal(&q) {
+		t.Fatal("unmarshal(marshal(G2)) failed")
+	}
+
+	// the point at infinity is encoded as zeros
+	var inf bls12381.G2Affine
+	if !bytes.Equal(MarshalG2(&inf), make([]byte, SizeOfG2)) {
+		t.Fatal("infinity should be encoded as zeros")
+	}
+	if err := UnmarshalG2(&q2, make([]byte, SizeOfG2)); err != nil || !q2.IsInfinity() {
+		t.Fatal("zeros should decode to infinity")
+	}
+	if err := UnmarshalG1(&p2, MarshalG1(&p)[:SizeOfG1-1]); err != ErrInvalidInputLength {
+		t.Fatal("expected ErrInvalidInputLength")
+	}
+}
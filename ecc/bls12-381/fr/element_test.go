@@ -57,6 +57,18 @@ func BenchmarkElementInverse(b *testing.B) {
 
 }
 
+func BenchmarkElementBatchInvert(b *testing.B) {
+	const n = 1024
+	a := make([]Element, n)
+	for i := 0; i < n; i++ {
+		a[i].SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchInvert(a)
+	}
+}
+
 func BenchmarkElementExp(b *testing.B) {
 	var x Element
 	x.SetRandom()
@@ -385,6 +397,41 @@ func TestElementInverseExp(t *testing.T) {
 	}
 }
 
+func TestElementBatchInvert(t *testing.T) {
+	const n = 33
+	a := make([]Element, n)
+	for i := 0; i < n; i++ {
+		if i%7 != 0 {
+			a[i].SetRandom()
+		}
+	}
+
+	check := func(res []Element) {
+		if len(res) != n {
+			t.Fatal("wrong size")
+		}
+		for i := 0; i < n; i++ {
+			var expected Element
+			expected.Inverse(&a[i])
+			if a[i].IsZero() {
+				expected.SetZero()
+			}
+			if !res[i].Equal(&expected) {
+				t.Fatal("BatchInvert doesn't match Inverse")
+			}
+		}
+	}
+
+	check(BatchInvert(a))
+	for _, nbTasks := range []int{0, 1, 4, n, 2 * n} {
+		check(BatchInvertParallel(a, nbTasks))
+	}
+
+	if len(BatchInvert(nil)) != 0 {
+		t.Fatal("BatchInvert of an empty slice should be empty")
+	}
+}
+
 func TestElementMulByConstants(t *testing.T) {
 
 	parameters := gopter.DefaultTestParameters()
@@ -0,0 +1,242 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pedersen
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// testKey re-used accross tests of the Pedersen commitment
+var testKey *Key
+
+func init() {
+	const keySize = 16
+	testKey, _ = NewKey(keySize, []byte("pedersen test"))
+}
+
+func randomValues(size int) []fr.Element {
+	values := make([]fr.Element, size)
+	for i := 0; i < size; i++ {
+		values[i].SetRandom()
+	}
+	return values
+}
+
+func TestNewKey(t *testing.T) {
+
+	if _, err := NewKey(0, []byte("pedersen test")); err != ErrInvalidKeySize {
+		t.Fatal("NewKey should fail on an empty key")
+	}
+
+	// the generators are deterministic and distinct
+	key, err := NewKey(len(testKey.G), []byte("pedersen test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(key, testKey) {
+		t.Fatal("NewKey is not deterministic")
+	}
+	for i := 0; i < len(key.G); i++ {
+		if !key.G[i].IsInSubGroup() || key.G[i].Equal(&key.H) {
+			t.Fatal("invalid generator")
+		}
+		for j := 0; j < i; j++ {
+			if key.G[i].Equal(&key.G[j]) {
+				t.Fatal("generators should be distinct")
+			}
+		}
+	}
+
+	// the generators depend on the domain separation tag
+	other, err := NewKey(1, []byte("other pedersen test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.G[0].Equal(&key.G[0]) || other.H.Equal(&key.H) {
+		t.Fatal("generators should depend on the domain separation tag")
+	}
+}
+
+func TestCommit(t *testing.T) {
+
+	values := randomValues(len(testKey.G) - 3)
+	var randomness fr.Element
+	randomness.SetRandom()
+
+	commitment, err := testKey.Commit(values, randomness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// check the commitment against Σ values[i]*G_i + randomness*H
+	var expected, tmp bls12381.G1Jac
+	var bs big.Int
+	for i := 0; i < len(values); i++ {
+		tmp.FromAffine(&testKey.G[i])
+		tmp.ScalarMultiplication(&tmp, values[i].ToBigIntRegular(&bs))
+		expected.AddAssign(&tmp)
+	}
+	tmp.FromAffine(&testKey.H)
+	tmp.ScalarMultiplication(&tmp, randomness.ToBigIntRegular(&bs))
+	expected.AddAssign(&tmp)
+	var _expected bls12381.G1Affine
+	_expected.FromJacobian(&expected)
+	if !_expected.Equal((*bls12381.G1Affine)(&commitment)) {
+		t.Fatal("commitment doesn't match Σ values[i]*G_i + randomness*H")
+	}
+
+	// valid opening
+	if err := testKey.Verify(&commitment, values, randomness); err != nil {
+		t.Fatal(err)
+	}
+
+	// wrong value
+	wrongValues := make([]fr.Element, len(values))
+	copy(wrongValues, values)
+	wrongValues[1].Double(&wrongValues[1])
+	if err := testKey.Verify(&commitment, wrongValues, randomness); err != ErrVerifyOpening {
+		t.Fatal("verifying a wrong value should fail")
+	}
+
+	// wrong randomness
+	var wrongRandomness fr.Element
+	wrongRandomness.Double(&randomness)
+	if err := testKey.Verify(&commitment, values, wrongRandomness); err != ErrVerifyOpening {
+		t.Fatal("verifying a wrong randomness should fail")
+	}
+
+	// too many values
+	if _, err := testKey.Commit(randomValues(len(testKey.G)+1), randomness); err != ErrInvalidNbValues {
+		t.Fatal("committing to more values than the key size should fail")
+	}
+}
+
+func TestHomomorphism(t *testing.T) {
+
+	a, b := randomValues(len(testKey.G)), randomValues(len(testKey.G)-1)
+	var ra, rb, s fr.Element
+	ra.SetRandom()
+	rb.SetRandom()
+	s.SetRandom()
+
+	ca, err := testKey.Commit(a, ra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb, err := testKey.Commit(b, rb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Commit(a) + Commit(b) opens to a + b
+	sum := make([]fr.Element, len(a))
+	copy(sum, a)
+	for i := 0; i < len(b); i++ {
+		sum[i].Add(&sum[i], &b[i])
+	}
+	var rSum fr.Element
+	rSum.Add(&ra, &rb)
+	var cSum Commitment
+	cSum.Add(&ca, &cb)
+	if err := testKey.Verify(&cSum, sum, rSum); err != nil {
+		t.Fatal(err)
+	}
+
+	// s*Commit(a) opens to s*a
+	scaled := make([]fr.Element, len(a))
+	for i := 0; i < len(a); i++ {
+		scaled[i].Mul(&a[i], &s)
+	}
+	var rScaled fr.Element
+	rScaled.Mul(&ra, &s)
+	var cScaled Commitment
+	cScaled.ScalarMultiplication(&ca, &s)
+	if err := testKey.Verify(&cScaled, scaled, rScaled); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSerialization(t *testing.T) {
+
+	// serialize the key...
+	var buf bytes.Buffer
+	if _, err := testKey.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// ... and reconstruct it
+	var key Key
+	if _, err := key.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(testKey, &key) {
+		t.Fatal("key serialization failed")
+	}
+
+	var randomness fr.Element
+	randomness.SetRandom()
+	commitment, err := testKey.Commit(randomValues(len(testKey.G)), randomness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// io.WriterTo and io.ReaderFrom
+	buf.Reset()
+	if _, err := commitment.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _commitment Commitment
+	if _, err := _commitment.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !_commitment.Equal(&commitment) {
+		t.Fatal("commitment serialization failed")
+	}
+
+	// Bytes and SetBytes
+	_commitment = Commitment{}
+	if _, err := _commitment.SetBytes(commitment.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !_commitment.Equal(&commitment) {
+		t.Fatal("commitment serialization failed")
+	}
+}
+
+// benchmarks
+
+func BenchmarkCommit(b *testing.B) {
+	const keySize = 1 << 10
+	key, err := NewKey(keySize, []byte("pedersen benchmark"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	values := randomValues(keySize)
+	var randomness fr.Element
+	randomness.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key.Commit(values, randomness)
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var (
+	ErrLengthMismatch  = errors.New("xs and ys must have the same length")
+	ErrDuplicatePoints = errors.New("interpolation points must be distinct")
+)
+
+const (
+	// below this number of points, InterpolateAt and EvaluateAt use the O(n²) algorithms
+	subproductTreeThreshold = 64
+
+	// below this length, polynomials are multiplied and divided with the schoolbook methods
+	fftMulThreshold = 64
+)
+
+// InterpolateAt returns the polynomial p of degree < len(xs) such that p(xs[i]) = ys[i].
+// The points xs must be distinct.
+//
+// Large inputs are interpolated in O(n log²(n)) with a subproduct tree and FFT multiplications,
+// small inputs in O(n²) with the barycentric form of the Lagrange polynomial.
+func InterpolateAt(xs, ys []fr.Element) (Polynomial, error) {
+	if len(xs) != len(ys) {
+		return nil, ErrLengthMismatch
+	}
+	if len(xs) == 0 {
+		return Polynomial{}, nil
+	}
+	if len(xs) <= subproductTreeThreshold {
+		return interpolateBarycentric(xs, ys)
+	}
+
+	tree := newSubproductTree(xs)
+
+	// p = Σ ys[i] / m'(xs[i]) * m / (X - xs[i]), where m = Π (X - xs[i])
+	var dm Polynomial
+	dm.Derivative(tree.root())
+	weights := tree.evaluate(dm)
+	for i := 0; i < len(weights); i++ {
+		if weights[i].IsZero() {
+			return nil, ErrDuplicatePoints
+		}
+	}
+	weights = fr.BatchInvert(weights)
+
+	// bottom-up, each node is the sum of the terms of its leaves,
+	// with the product of the other leaves of the node
+	nodes := make([]Polynomial, len(xs))
+	for i := 0; i < len(xs); i++ {
+		nodes[i] = Polynomial{*weights[i].Mul(&weights[i], &ys[i])}
+	}
+	for k := 0; k < len(tree)-1; k++ {
+		level := tree[k]
+		next := make([]Polynomial, len(tree[k+1]))
+		for i := 0; i < len(next); i++ {
+			if 2*i+1 == len(level) {
+				next[i] = nodes[2*i]
+				continue
+			}
+			left := mul(nodes[2*i], level[2*i+1])
+			right := mul(nodes[2*i+1], level[2*i])
+			next[i].Add(left, right)
+		}
+		nodes = next
+	}
+
+	return nodes[0], nil
+}
+
+// EvaluateAt returns the evaluations of p at xs.
+//
+// Many evaluations of a large polynomial are computed in O(n log²(n)) with a subproduct tree
+// of xs and fast polynomial divisions, others with Horner's method.
+func EvaluateAt(p Polynomial, xs []fr.Element) []fr.Element {
+	if len(xs) <= subproductTreeThreshold || len(p) <= subproductTreeThreshold {
+		res := make([]fr.Element, len(xs))
+		for i := 0; i < len(xs); i++ {
+			res[i] = p.Evaluate(xs[i])
+		}
+		return res
+	}
+	return newSubproductTree(xs).evaluate(p)
+}
+
+// interpolateBarycentric is InterpolateAt, in O(n²)
+func interpolateBarycentric(xs, ys []fr.Element) (Polynomial, error) {
+	n := len(xs)
+
+	// m = Π (X - xs[i])
+	m := make(Polynomial, 1, n+1)
+	m[0].SetOne()
+	var tmp fr.Element
+	for i := 0; i < n; i++ {
+		m = append(m, fr.Element{})
+		for j := len(m) - 1; j > 0; j-- {
+			tmp.Mul(&m[j], &xs[i])
+			m[j].Sub(&m[j-1], &tmp)
+		}
+		m[0].Mul(&m[0], &xs[i]).Neg(&m[0])
+	}
+
+	// barycentric weights w[i] = 1 / Π_{j≠i} (xs[i] - xs[j])
+	weights := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		weights[i].SetOne()
+		for j := 0; j < n; j++ {
+			if j != i {
+				tmp.Sub(&xs[i], &xs[j])
+				weights[i].Mul(&weights[i], &tmp)
+			}
+		}
+		if weights[i].IsZero() {
+			return nil, ErrDuplicatePoints
+		}
+	}
+	weights = fr.BatchInvert(weights)
+
+	// p = Σ ys[i] * w[i] * m / (X - xs[i])
+	res := make(Polynomial, n)
+	var l Polynomial
+	for i := 0; i < n; i++ {
+		l.DivideByLinear(m, xs[i])
+		tmp.Mul(&weights[i], &ys[i])
+		for j := 0; j < n; j++ {
+			var t fr.Element
+			t.Mul(&l[j], &tmp)
+			res[j].Add(&res[j], &t)
+		}
+	}
+
+	return res, nil
+}
+
+// subproductTree stores the products of the linear polynomials (X - xs[i]):
+// tree[0][i] = X - xs[i], and tree[k+1][i] = tree[k][2i] * tree[k][2i+1]
+// (or tree[k][2i] if it is the last node of an odd level). The last level is the root.
+type subproductTree [][]Polynomial
+
+func newSubproductTree(xs []fr.Element) subproductTree {
+	level := make([]Polynomial, len(xs))
+	for i := 0; i < len(xs); i++ {
+		level[i] = make(Polynomial, 2)
+		level[i][0].Neg(&xs[i])
+		level[i][1].SetOne()
+	}
+	tree := subproductTree{level}
+
+	for len(level) > 1 {
+		next := make([]Polynomial, (len(level)+1)/2)
+		for i := 0; i < len(next); i++ {
+			if 2*i+1 == len(level) {
+				next[i] = level[2*i]
+			} else {
+				next[i] = mul(level[2*i], level[2*i+1])
+			}
+		}
+		tree = append(tree, next)
+		level = next
+	}
+
+	return tree
+}
+
+// root returns Π (X - xs[i])
+func (tree subproductTree) root() Polynomial {
+	return tree[len(tree)-1][0]
+}
+
+// evaluate returns the evaluations of p at the points of the tree, by reducing p
+// modulo the nodes of the tree, from the root to the leaves
+func (tree subproductTree) evaluate(p Polynomial) []fr.Element {
+	rems := []Polynomial{p}
+	if len(p) >= len(tree.root()) {
+		_, r := quoRem(p, tree.root())
+		rems[0] = r
+	}
+
+	for k := len(tree) - 2; k > 0; k-- {
+		level := tree[k]
+		next := make([]Polynomial, len(level))
+		for i := 0; i < len(level); i++ {
+			if len(rems[i/2]) < len(level[i]) {
+				next[i] = rems[i/2]
+			} else {
+				_, next[i] = quoRem(rems[i/2], level[i])
+			}
+		}
+		rems = next
+	}
+
+	// the remainder modulo X - xs[i] is the evaluation at xs[i]
+	res := make([]fr.Element, len(tree[0]))
+	for i := 0; i < len(res); i++ {
+		var x fr.Element
+		x.Neg(&tree[0][i][0])
+		res[i] = rems[i/2].Evaluate(x)
+	}
+	return res
+}
+
+// mul returns p1 * p2, with MulFFT or Mul depending on their lengths
+func mul(p1, p2 Polynomial) Polynomial {
+	var res Polynomial
+	if len(p1) < fftMulThreshold || len(p2) < fftMulThreshold {
+		res.Mul(p1, p2)
+	} else {
+		res.MulFFT(p1, p2, nil)
+	}
+	return res
+}
+
+// quoRem returns the quotient and the remainder of the division of a by b, whose leading
+// coefficient must be non zero. Large divisions are computed with a power series inversion
+// of the reversed divisor, in O(M(n)).
+func quoRem(a, b Polynomial) (q, r Polynomial) {
+	m := len(a) - len(b) + 1
+	if m < fftMulThreshold || len(b) < fftMulThreshold {
+		q.QuoRem(a, b, &r)
+		return
+	}
+
+	// rev(q) = rev(a) / rev(b) mod X^m
+	revA := make(Polynomial, m)
+	for i := 0; i < m; i++ {
+		revA[i] = a[len(a)-1-i]
+	}
+	revB := make(Polynomial, len(b))
+	for i := 0; i < len(b); i++ {
+		revB[i] = b[len(b)-1-i]
+	}
+	revQ := mul(revA, invertSeries(revB, m))[:m]
+	q = make(Polynomial, m)
+	for i := 0; i < m; i++ {
+		q[i] = revQ[m-1-i]
+	}
+
+	// r = a - q*b, of degree < deg(b)
+	qb := mul(q, b)
+	r = make(Polynomial, len(b)-1)
+	for i := 0; i < len(r); i++ {
+		r[i].Sub(&a[i], &qb[i])
+	}
+	return
+}
+
+// invertSeries returns g such that f*g = 1 mod X^n, using Newton's iteration
+// g <- g*(2 - f*g). f[0] must be non zero.
+func invertSeries(f Polynomial, n int) Polynomial {
+	g := make(Polynomial, 1, n)
+	g[0].Inverse(&f[0])
+	var two fr.Element
+	two.SetUint64(2)
+
+	for k := 1; k < n; {
+		k *= 2
+		if k > n {
+			k = n
+		}
+		fk := f
+		if len(fk) > k {
+			fk = fk[:k]
+		}
+		e := mul(fk, g)
+		if len(e) > k {
+			e = e[:k]
+		}
+		for i := 0; i < len(e); i++ {
+			e[i].Neg(&e[i])
+		}
+		e[0].Add(&e[0], &two)
+		g = mul(g, e)
+		if len(g) > k {
+			g = g[:k]
+		}
+	}
+
+	return g
+}
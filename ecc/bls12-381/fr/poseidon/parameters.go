@@ -0,0 +1,227 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+var (
+	ErrInvalidWidth        = errors.New("invalid width: t must be at least 2")
+	ErrInvalidNbRounds     = errors.New("invalid number of rounds: the number of full rounds must be even and positive")
+	ErrNoDefaultParameters = errors.New("no default parameters for this width")
+)
+
+// defaultNbFullRounds number of full rounds of the default parameters
+const defaultNbFullRounds = 8
+
+// defaultNbPartialRounds[t-2] number of partial rounds of the default parameters of width t.
+// They are given by the round numbers script of the reference implementation for 128 bits
+// of security (security margin included), rounded up to a multiple of t as done by circomlib.
+var defaultNbPartialRounds = [...]int{
+	56, 57, 56, 60, 60, 63, 64, 63, 60, 66, 60, 65, 70, 60, 64, 68,
+}
+
+// Parameters of a Poseidon permutation
+type Parameters struct {
+	T               int            // width of the state
+	NbFullRounds    int            // number of full rounds, half of them are done before the partial rounds
+	NbPartialRounds int            // number of partial rounds
+	RoundConstants  [][]fr.Element // RoundConstants[r] is added to the state at the beginning of round r
+	MDS             [][]fr.Element // T x T matrix of the linear layer
+}
+
+// DefaultParameters returns the parameters of width t (2 <= t <= 17) with 8 full rounds
+// and the recommended number of partial rounds.
+func DefaultParameters(t int) (*Parameters, error) {
+	if t < 2 || t-2 >= len(defaultNbPartialRounds) {
+		return nil, ErrNoDefaultParameters
+	}
+	return NewParameters(t, defaultNbFullRounds, defaultNbPartialRounds[t-2])
+}
+
+// NewParameters generates the round constants and the MDS matrix of a Poseidon
+// permutation of width t with the Grain LFSR, as done by the reference implementation
+// (cf https://eprint.iacr.org/2019/458.pdf, appendix F).
+//
+// The MDS matrix is the first Cauchy matrix obtained from the LFSR; unlike the reference
+// script, it is not checked against infinitely long subspace trails.
+func NewParameters(t, nbFullRounds, nbPartialRounds int) (*Parameters, error) {
+	if t < 2 {
+		return nil, ErrInvalidWidth
+	}
+	if nbFullRounds <= 0 || nbFullRounds%2 != 0 || nbPartialRounds < 0 {
+		return nil, ErrInvalidNbRounds
+	}
+
+	params := &Parameters{
+		T:               t,
+		NbFullRounds:    nbFullRounds,
+		NbPartialRounds: nbPartialRounds,
+	}
+
+	g := newGrainLFSR(t, nbFullRounds, nbPartialRounds)
+
+	params.RoundConstants = make([][]fr.Element, nbFullRounds+nbPartialRounds)
+	for i := range params.RoundConstants {
+		params.RoundConstants[i] = make([]fr.Element, t)
+		for j := range params.RoundConstants[i] {
+			g.nextElement(&params.RoundConstants[i][j])
+		}
+	}
+
+	params.MDS = g.cauchyMatrix(t)
+
+	return params, nil
+}
+
+// grainLFSR self-shrinking 80 bits Grain LFSR used to generate the parameters
+type grainLFSR struct {
+	state [80]uint8
+	head  int
+}
+
+// newGrainLFSR initializes the LFSR with the description of the permutation
+// (prime field, x**alpha s-box, field size, width and number of rounds)
+// and discards its first 160 bits
+func newGrainLFSR(t, nbFullRounds, nbPartialRounds int) *grainLFSR {
+	g := new(grainLFSR)
+
+	i := 0
+	write := func(v, nbBits int) {
+		for j := nbBits - 1; j >= 0; j-- {
+			g.state[i] = uint8((v >> j) & 1)
+			i++
+		}
+	}
+	write(1, 2) // prime field
+	write(0, 4) // s-box x**alpha
+	write(fr.Bits, 12)
+	write(t, 12)
+	write(nbFullRounds, 10)
+	write(nbPartialRounds, 10)
+	for i < len(g.state) {
+		g.state[i] = 1
+		i++
+	}
+
+	for j := 0; j < 160; j++ {
+		g.clock()
+	}
+
+	return g
+}
+
+// clock updates the LFSR state and returns the new bit
+func (g *grainLFSR) clock() uint8 {
+	s := func(k int) uint8 {
+		return g.state[(g.head+k)%len(g.state)]
+	}
+	b := s(62) ^ s(51) ^ s(38) ^ s(23) ^ s(13) ^ s(0)
+	g.state[g.head] = b
+	g.head = (g.head + 1) % len(g.state)
+	return b
+}
+
+// nextBit returns the next output bit: bits are produced in pairs,
+// and the second one is kept only if the first one is 1
+func (g *grainLFSR) nextBit() uint8 {
+	for {
+		keep := g.clock()
+		b := g.clock()
+		if keep == 1 {
+			return b
+		}
+	}
+}
+
+// nextBits sets res to the integer made of the next fr.Bits output bits, most significant first
+func (g *grainLFSR) nextBits(res *big.Int) {
+	res.SetUint64(0)
+	for i := 0; i < fr.Bits; i++ {
+		res.Lsh(res, 1)
+		if g.nextBit() == 1 {
+			res.SetBit(res, 0, 1)
+		}
+	}
+}
+
+// nextElement sets e to the next uniformly sampled field element (rejection sampling)
+func (g *grainLFSR) nextElement(e *fr.Element) {
+	var v big.Int
+	modulus := fr.Modulus()
+	for {
+		g.nextBits(&v)
+		if v.Cmp(modulus) == -1 {
+			e.SetBigInt(&v)
+			return
+		}
+	}
+}
+
+// cauchyMatrix returns the t x t matrix M[i][j] = 1 / (x[i] + y[j]), where
+// the x[i], y[j] are 2t distinct elements sampled from the LFSR (reduced mod r)
+func (g *grainLFSR) cauchyMatrix(t int) [][]fr.Element {
+	var v big.Int
+	xy := make([]fr.Element, 2*t)
+
+	for {
+		// sample 2t distinct elements
+		for {
+			for i := range xy {
+				g.nextBits(&v)
+				xy[i].SetBigInt(&v)
+			}
+			if distinct(xy) {
+				break
+			}
+		}
+		x, y := xy[:t], xy[t:]
+
+		res := make([][]fr.Element, t)
+		ok := true
+		for i := 0; i < t && ok; i++ {
+			res[i] = make([]fr.Element, t)
+			for j := 0; j < t; j++ {
+				res[i][j].Add(&x[i], &y[j])
+				if res[i][j].IsZero() {
+					ok = false
+					break
+				}
+				res[i][j].Inverse(&res[i][j])
+			}
+		}
+		if ok {
+			return res
+		}
+	}
+}
+
+// distinct returns true if all the elements of v are distinct
+func distinct(v []fr.Element) bool {
+	for i := range v {
+		for j := i + 1; j < len(v); j++ {
+			if v[i].Equal(&v[j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestPermutation(t *testing.T) {
+	params, err := DefaultParameters(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := params.Permutation(make([]fr.Element, 2)); err != ErrInvalidStateSize {
+		t.Fatal("expected ErrInvalidStateSize")
+	}
+
+	// Hash on t-1 inputs is the first element of the permutation of [0, inputs...]
+	state := make([]fr.Element, 3)
+	state[1].SetUint64(1)
+	state[2].SetUint64(2)
+	h := params.Hash(state[1], state[2])
+	if err := params.Permutation(state); err != nil {
+		t.Fatal(err)
+	}
+	if !state[0].Equal(&h) {
+		t.Fatal("Hash and Permutation don't match")
+	}
+}
+
+func TestParameters(t *testing.T) {
+	if _, err := NewParameters(1, 8, 57); err != ErrInvalidWidth {
+		t.Fatal("expected ErrInvalidWidth")
+	}
+	if _, err := NewParameters(3, 7, 57); err != ErrInvalidNbRounds {
+		t.Fatal("expected ErrInvalidNbRounds")
+	}
+	if _, err := DefaultParameters(18); err != ErrNoDefaultParameters {
+		t.Fatal("expected ErrNoDefaultParameters")
+	}
+
+	// generation is deterministic
+	p1, err := NewParameters(4, 8, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, _ := NewParameters(4, 8, 20)
+	if len(p1.RoundConstants) != 28 || len(p1.MDS) != 4 {
+		t.Fatal("wrong parameters size")
+	}
+	for i := range p1.RoundConstants {
+		for j := range p1.RoundConstants[i] {
+			if !p1.RoundConstants[i][j].Equal(&p2.RoundConstants[i][j]) {
+				t.Fatal("round constants generation is not deterministic")
+			}
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	params, err := DefaultParameters(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the bytes interface hashes the elements written, followed by the length of the data
+	var a, b, length fr.Element
+	a.SetUint64(42)
+	b.SetRandom()
+	length.SetUint64(2 * BlockSize)
+	h := params.Hash(a, b, length)
+
+	hFunc := NewPoseidon(params)
+	ab, bb := a.Bytes(), b.Bytes()
+	hFunc.Write(ab[:])
+	hFunc.Write(bb[:])
+	res := hFunc.Sum(nil)
+	expected := h.Bytes()
+	if !bytes.Equal(res, expected[:]) {
+		t.Fatal("hash.Hash and Hash don't match")
+	}
+
+	// Sum doesn't change the state
+	if !bytes.Equal(hFunc.Sum(nil), res) {
+		t.Fatal("Sum should not change the state")
+	}
+
+	// a short chunk is left-padded with zeros
+	hFunc.Reset()
+	hFunc.Write([]byte{42})
+	length.SetOne()
+	h = params.Hash(a, length)
+	expected = h.Bytes()
+	if !bytes.Equal(hFunc.Sum(nil), expected[:]) {
+		t.Fatal("the last chunk should be left-padded")
+	}
+}
+
+func TestHashDomainSeparation(t *testing.T) {
+	params, err := DefaultParameters(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the zero padding doesn't make inputs of different lengths collide
+	var zero, a fr.Element
+	a.SetRandom()
+	collisions := [][2][]fr.Element{
+		{nil, {zero}},
+		{{a}, {a, zero}},
+		{{a, zero}, {a, zero, zero}},
+		{nil, {zero, zero}},
+	}
+	for _, c := range collisions {
+		h0, h1 := params.Hash(c[0]...), params.Hash(c[1]...)
+		if h0.Equal(&h1) {
+			t.Fatalf("Hash of %d and %d inputs should differ", len(c[0]), len(c[1]))
+		}
+	}
+
+	// nor does the left padding of the last chunk of the bytes interface
+	hFunc := NewPoseidon(params)
+	hFunc.Write([]byte("x"))
+	h0 := hFunc.Sum(nil)
+	hFunc.Reset()
+	hFunc.Write([]byte("\x00x"))
+	if bytes.Equal(h0, hFunc.Sum(nil)) {
+		t.Fatal("writing \"x\" and \"\\x00x\" should give different digests")
+	}
+}
+
+func BenchmarkPermutation(b *testing.B) {
+	params, _ := DefaultParameters(3)
+	state := make([]fr.Element, 3)
+	for i := range state {
+		state[i].SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = params.Permutation(state)
+	}
+}
@@ -18,8 +18,10 @@ package bls12381
 
 import (
 	"math/big"
+	"runtime"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/internal/fptower"
 	"github.com/consensys/gnark-crypto/internal/parallel"
@@ -63,6 +65,18 @@ func (p *G2Affine) ScalarMultiplication(a *G2Affine, s *big.Int) *G2Affine {
 	return p
 }
 
+// ScalarMultiplicationSecret computes and returns p = a*s, in constant time with respect to s.
+// It must be used instead of ScalarMultiplication when s is secret (for example, a private key),
+// as the running time of ScalarMultiplication depends on the bits of s.
+// a must be in the prime order subgroup.
+func (p *G2Affine) ScalarMultiplicationSecret(a *G2Affine, s *big.Int) *G2Affine {
+	var _p G2Jac
+	_p.FromAffine(a)
+	_p.mulLadder(&_p, s)
+	p.fromJacobianSecret(&_p)
+	return p
+}
+
 // Equal tests if two points (in Affine coordinates) are equal
 func (p *G2Affine) Equal(a *G2Affine) bool {
 	return p.X.Equal(&a.X) && p.Y.Equal(&a.Y)
@@ -346,23 +360,97 @@ func (p *G2Jac) IsOnCurve() bool {
 }
 
 // IsInSubGroup returns true if p is on the r-torsion, false otherwise.
-// Z[r,0]+Z[-lambdaG2Affine, 1] is the kernel
-// of (u,v)->u+lambdaG2Affinev mod r. Expressing r, lambdaG2Affine as
-// polynomials in x, a short vector of this Zmodule is
-// 1, x**2. So we check that p+x**2*phi(p)
-// is the infinity.
+// On BLS12 curves, p is in G2 if and only if psi(p) = [x]p, where x is the seed of the curve,
+// see https://eprint.iacr.org/2021/1130.pdf (Scott), which costs a single multiplication by x.
 func (p *G2Jac) IsInSubGroup() bool {
 
-	var res G2Jac
-	res.phi(p).
-		ScalarMultiplication(&res, &xGen).
-		ScalarMultiplication(&res, &xGen).
-		AddAssign(p)
+	var res, psip G2Jac
+	psip.psi(p)
+	res.mulBySeed(p)
+	// the seed is negative, and xGen = |x|
+	res.AddAssign(&psip)
 
 	return res.IsOnCurve() && res.Z.IsZero()
 
 }
 
+// mulBySeed sets p = [xGen]a, where xGen is the seed of the curve (in absolute value),
+// with a double-and-add over its bits (this is faster than ScalarMultiplication, as xGen
+// is a small constant).
+func (p *G2Jac) mulBySeed(a *G2Jac) *G2Jac {
+	var res G2Jac
+	res.Set(a)
+	for i := xGen.BitLen() - 2; i >= 0; i-- {
+		res.DoubleAssign()
+		if xGen.Bit(i) == 1 {
+			res.AddAssign(a)
+		}
+	}
+	p.Set(&res)
+	return p
+}
+
+// ScalarMultiplicationSecret computes and returns p = a*s, in constant time with respect to s.
+// It must be used instead of ScalarMultiplication when s is secret (for example, a private key),
+// as the running time of ScalarMultiplication depends on the bits of s.
+// a must be in the prime order subgroup.
+func (p *G2Jac) ScalarMultiplicationSecret(a *G2Jac, s *big.Int) *G2Jac {
+	return p.mulLadder(a, s)
+}
+
+// mulLadder computes p = a*s with a Montgomery ladder, over the fixed number of bits
+// of fixedLengthScalar(s), with constant time conditional swaps.
+// As a is in the prime order subgroup and r0 - r1 = a, the additions
+// never hit the exceptional cases (r0 == r1 or r0, r1 at infinity) for non trivial scalars.
+func (p *G2Jac) mulLadder(a *G2Jac, s *big.Int) *G2Jac {
+	k := fixedLengthScalar(s)
+
+	// the bit fr.Bits of k is set
+	var r0, r1 G2Jac
+	r0.Set(a)
+	r1.Double(a)
+
+	// invariant: r1 = r0 + a
+	for i := fr.Bits - 1; i >= 0; i-- {
+		b := (k[i/64] >> (uint(i) % 64)) & 1
+		r0.cswap(&r1, b)
+		r1.AddAssign(&r0)
+		r0.DoubleAssign()
+		r0.cswap(&r1, b)
+	}
+	p.Set(&r0)
+
+	return p
+}
+
+// cswap swaps p and q if b == 1, and leaves them unchanged if b == 0, in constant time
+func (p *G2Jac) cswap(q *G2Jac, b uint64) {
+	mask := -b
+	pc := [...]*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1, &p.Z.A0, &p.Z.A1}
+	qc := [...]*fp.Element{&q.X.A0, &q.X.A1, &q.Y.A0, &q.Y.A1, &q.Z.A0, &q.Z.A1}
+	for i := range pc {
+		for j := 0; j < fp.Limbs; j++ {
+			t := mask & (pc[i][j] ^ qc[i][j])
+			pc[i][j] ^= t
+			qc[i][j] ^= t
+		}
+	}
+}
+
+// fromJacobianSecret is FromJacobian, with a constant time inversion of p1.Z
+// (by Fermat's little theorem)
+func (p *G2Affine) fromJacobianSecret(p1 *G2Jac) *G2Affine {
+	var e big.Int
+	e.Mul(fp.Modulus(), fp.Modulus()).Sub(&e, big.NewInt(2))
+
+	var a, b fptower.E2
+	a.Exp(p1.Z, &e)
+	b.Square(&a)
+	p.X.Mul(&p1.X, &b)
+	p.Y.Mul(&p1.Y, &b).Mul(&p.Y, &a)
+	return p
+}
+
 // mulWindowed 2-bits windowed exponentiation
 func (p *G2Jac) mulWindowed(a *G2Jac, s *big.Int) *G2Jac {
 
@@ -854,6 +942,55 @@ func (p *g2Proj) FromAffine(Q *G2Affine) *g2Proj {
 	return p
 }
 
+// BatchJacobianToAffineG2Affine converts points in Jacobian coordinates to Affine coordinates
+// performing a single field inversion (Montgomery batch inversion trick)
+// result must be allocated with len(result) == len(points)
+func BatchJacobianToAffineG2Affine(points []G2Jac, result []G2Affine) {
+	zeroes := make([]bool, len(points))
+	var accumulator fptower.E2
+	accumulator.SetOne()
+
+	// batch invert all points[].Z coordinates with Montgomery batch inversion trick
+	// (stores points[].Z^-1 in result[i].X to avoid allocating a slice of fptower.E2)
+	for i := 0; i < len(points); i++ {
+		if points[i].Z.IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		result[i].X = accumulator
+		accumulator.Mul(&accumulator, &points[i].Z)
+	}
+
+	var accInverse fptower.E2
+	accInverse.Inverse(&accumulator)
+
+	for i := len(points) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			// do nothing, X and Y are zeroes in affine.
+			continue
+		}
+		result[i].X.Mul(&result[i].X, &accInverse)
+		accInverse.Mul(&accInverse, &points[i].Z)
+	}
+
+	// batch convert to affine.
+	parallel.Execute(len(points), func(start, end int) {
+		for i := start; i < end; i++ {
+			if zeroes[i] {
+				// do nothing, X and Y are zeroes in affine.
+				continue
+			}
+			var a, b fptower.E2
+			a = result[i].X
+			b.Square(&a)
+			result[i].X.Mul(&points[i].X, &b)
+			result[i].Y.Mul(&points[i].Y, &b).
+				Mul(&result[i].Y, &a)
+		}
+	})
+
+}
+
 // BatchScalarMultiplicationG2 multiplies the same base (generator) by all scalars
 // and return resulting points in affine coordinates
 // uses a simple windowed-NAF like exponentiation algorithm
@@ -896,7 +1033,7 @@ func BatchScalarMultiplicationG2(base *G2Affine, scalars []fr.Element) []G2Affin
 		baseTable[i].AddMixed(base)
 	}
 
-	pScalars := partitionScalars(scalars, c)
+	pScalars := partitionScalars(scalars, c, runtime.NumCPU())
 
 	// compute offset and word selector / shift to select the right bits of our windows
 	selectors := make([]selector, nbChunks)
@@ -62,21 +62,31 @@ func TestMapToCurveG2(t *testing.T) {
 	genFuzz1 := GenE2()
 
 	properties.Property("[G2] Svsw mapping should output point on the curve", prop.ForAll(
-		func(a fptower.E2) bool {
-			g := MapToCurveG2Svdw(a)
+		func(a *fptower.E2) bool {
+			g := MapToCurveG2Svdw(*a)
 			return g.IsOnCurve()
 		},
 		genFuzz1,
 	))
 
 	properties.Property("[G2] Svsw mapping should be deterministic", prop.ForAll(
-		func(a fptower.E2) bool {
-			g1 := MapToCurveG2Svdw(a)
-			g2 := MapToCurveG2Svdw(a)
+		func(a *fptower.E2) bool {
+			g1 := MapToCurveG2Svdw(*a)
+			g2 := MapToCurveG2Svdw(*a)
 			return g1.Equal(&g2)
 		},
 		genFuzz1,
 	))
+
+	properties.Property("[G2] Svsw mapping should output point in the subgroup", prop.ForAll(
+		func(a *fptower.E2) bool {
+			g := MapToCurveG2Svdw(*a)
+			return g.IsInSubGroup()
+		},
+		genFuzz1,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
 func TestG2AffineIsOnCurve(t *testing.T) {
@@ -337,6 +347,48 @@ func TestG2AffineOps(t *testing.T) {
 		genScalar,
 	))
 
+	properties.Property("[BLS12-381] constant time scalar multiplication (ladder) and double and add should output the same result", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var scalar, blindedScalar big.Int
+			var op1, op2, op3 G2Jac
+			s.ToBigIntRegular(&scalar)
+			blindedScalar.Add(&scalar, fr.Modulus())
+			op1.mulWindowed(&g2Gen, &scalar)
+			op2.ScalarMultiplicationSecret(&g2Gen, &scalar)
+			op3.ScalarMultiplicationSecret(&g2Gen, &blindedScalar)
+
+			var op1Aff, op2Aff G2Affine
+			op1Aff.FromJacobian(&op1)
+			op2Aff.ScalarMultiplicationSecret(&g2GenAff, &scalar)
+
+			return op1.Equal(&op2) && op1.Equal(&op3) && op1Aff.Equal(&op2Aff)
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS12-381] constant time scalar multiplication (ladder) of small scalars and multiples of r should be correct", prop.ForAll(
+		func() bool {
+			r := fr.Modulus()
+			var op G2Jac
+			var opAff G2Affine
+			var g2, gneg G2Jac
+			g2.Double(&g2Gen)
+			gneg.Neg(&g2Gen)
+
+			var rminusone big.Int
+			rminusone.SetUint64(1).Sub(r, &rminusone)
+
+			ok := op.ScalarMultiplicationSecret(&g2Gen, big.NewInt(0)).Z.IsZero()
+			ok = ok && op.ScalarMultiplicationSecret(&g2Gen, r).Z.IsZero()
+			ok = ok && op.ScalarMultiplicationSecret(&g2Gen, big.NewInt(1)).Equal(&g2Gen)
+			ok = ok && op.ScalarMultiplicationSecret(&g2Gen, big.NewInt(2)).Equal(&g2)
+			ok = ok && op.ScalarMultiplicationSecret(&g2Gen, &rminusone).Equal(&gneg)
+			ok = ok && opAff.ScalarMultiplicationSecret(&g2GenAff, r).IsInfinity()
+			return ok
+		},
+	))
+
 	properties.Property("[BLS12-381] psi should map points from E' to itself", prop.ForAll(
 		func() bool {
 			var a G2Jac
@@ -417,6 +469,92 @@ func TestG2AffineCofactorCleaning(t *testing.T) {
 
 }
 
+// isInSubGroupNaiveG2Jac returns true if [r]p is the infinity
+func isInSubGroupNaiveG2Jac(p *G2Jac) bool {
+	var res G2Jac
+	res.mulWindowed(p, fr.Modulus())
+	return p.IsOnCurve() && res.Z.IsZero()
+}
+
+// randomPointOnCurveG2 returns a random point of the curve, which is
+// (with overwhelming probability) not in the subgroup if the cofactor is not 1
+func randomPointOnCurveG2() G2Jac {
+	var a, x, b fptower.E2
+	for {
+		a.SetRandom()
+		x.Square(&a).Mul(&x, &a).Add(&x, &bTwistCurveCoeff)
+		if x.Legendre() == 1 {
+			break
+		}
+	}
+	b.Sqrt(&x)
+	var point G2Jac
+	point.X.Set(&a)
+	point.Y.Set(&b)
+	point.Z.SetOne()
+	return point
+}
+
+func TestG2AffineIsInSubGroup(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	properties.Property("[BLS12-381] IsInSubGroup should match the [r]p check on random points of the curve", prop.ForAll(
+		func() bool {
+			p := randomPointOnCurveG2()
+			var q G2Jac
+			q.Set(&p).AddAssign(&g2Gen)
+			return p.IsInSubGroup() == isInSubGroupNaiveG2Jac(&p) &&
+				q.IsInSubGroup() == isInSubGroupNaiveG2Jac(&q)
+		},
+	))
+
+	properties.Property("[BLS12-381] IsInSubGroup should match the [r]p check on points of the subgroup", prop.ForAll(
+		func(s fr.Element) bool {
+			var p G2Jac
+			var scalar big.Int
+			s.ToBigIntRegular(&scalar)
+			p.mulWindowed(&g2Gen, &scalar)
+			var q G2Jac
+			random := randomPointOnCurveG2()
+			q.ClearCofactor(&random)
+			return p.IsInSubGroup() && isInSubGroupNaiveG2Jac(&p) &&
+				q.IsInSubGroup() && isInSubGroupNaiveG2Jac(&q)
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS12-381] IsInSubGroup should accept the infinity", prop.ForAll(
+		func() bool {
+			var inf G2Affine
+			return g2Infinity.IsInSubGroup() && inf.IsInSubGroup()
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func BenchmarkG2AffineIsInSubGroup(b *testing.B) {
+	var p G2Jac
+	p.Double(&g2Gen)
+
+	b.Run("endomorphism", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.IsInSubGroup()
+		}
+	})
+	b.Run("[r]p", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			isInSubGroupNaiveG2Jac(&p)
+		}
+	})
+}
+
 func TestG2AffineBatchScalarMultiplication(t *testing.T) {
 
 	parameters := gopter.DefaultTestParameters()
@@ -519,6 +657,14 @@ func BenchmarkG2JacScalarMul(b *testing.B) {
 		}
 	})
 
+	var ladder G2Jac
+	b.Run("constant time ladder", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			ladder.ScalarMultiplicationSecret(&g2Gen, &scalar)
+		}
+	})
+
 }
 
 func BenchmarkG2AffineCofactorClearing(b *testing.B) {
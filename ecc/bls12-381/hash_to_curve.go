@@ -278,3 +278,436 @@ func HashToCurveG2Svdw(msg, dst []byte) (G2Affine, error) {
 	res.FromJacobian(&_res)
 	return res, nil
 }
+
+// ----------------------------------------------------------------------------------------
+// Simplified Shallue-van de Woestijne-Ulas method with isogeny
+// https://datatracker.ietf.org/doc/html/rfc9380#section-6.6.3
+//
+// The points are first mapped on a curve E' isogenous to E (with AB != 0)
+// then sent to E with the isogeny.
+// These maps are the ones used by the suites BLS12381G1_XMD:SHA-256_SSWU_RO_,
+// BLS12381G1_XMD:SHA-256_SSWU_NU_, BLS12381G2_XMD:SHA-256_SSWU_RO_ and
+// BLS12381G2_XMD:SHA-256_SSWU_NU_.
+
+// E1': y**2 = x**3 + A*x + B, 11-isogenous to E1 (https://datatracker.ietf.org/doc/html/rfc9380#section-8.8.1)
+var g1IsoCurveCoeffA, g1IsoCurveCoeffB, g1SSWUZ fp.Element
+
+// coefficients of the rational maps of the 11-isogeny E1' -> E1
+// https://datatracker.ietf.org/doc/html/rfc9380#appendix-E.2
+var g1IsogenyConstants struct {
+	xNum [12]fp.Element
+	xDen [11]fp.Element
+	yNum [16]fp.Element
+	yDen [16]fp.Element
+}
+
+// E2': y**2 = x**3 + A*x + B, 3-isogenous to E2 (https://datatracker.ietf.org/doc/html/rfc9380#section-8.8.2)
+var g2IsoCurveCoeffA, g2IsoCurveCoeffB, g2SSWUZ fptower.E2
+
+// coefficients of the rational maps of the 3-isogeny E2' -> E2
+// https://datatracker.ietf.org/doc/html/rfc9380#appendix-E.3
+var g2IsogenyConstants struct {
+	xNum [4]fptower.E2
+	xDen [3]fptower.E2
+	yNum [4]fptower.E2
+	yDen [4]fptower.E2
+}
+
+func init() {
+	g1IsoCurveCoeffA.SetString("12190336318893619529228877361869031420615612348429846051986726275283378313155663745811710833465465981901188123677")
+	g1IsoCurveCoeffB.SetString("2906670324641927570491258158026293881577086121416628140204402091718288198173574630967936031029026176254968826637280")
+	g1SSWUZ.SetUint64(11)
+
+	g1IsogenyConstants.xNum[0].SetString("2712959285290305970661081772124144179193819192423276218370281158706191519995889425075952244140278856085036081760695")
+	g1IsogenyConstants.xNum[1].SetString("3564859427549639835253027846704205725951033235539816243131874237388832081954622352624080767121604606753339903542203")
+	g1IsogenyConstants.xNum[2].SetString("2051387046688339481714726479723076305756384619135044672831882917686431912682625619320120082313093891743187631791280")
+	g1IsogenyConstants.xNum[3].SetString("3612713941521031012780325893181011392520079402153354595775735142359240110423346445050803899623018402874731133626465")
+	g1IsogenyConstants.xNum[4].SetString("2247053637822768981792833880270996398470828564809439728372634811976089874056583714987807553397615562273407692740057")
+	g1IsogenyConstants.xNum[5].SetString("3415427104483187489859740871640064348492611444552862448295571438270821994900526625562705192993481400731539293415811")
+	g1IsogenyConstants.xNum[6].SetString("2067521456483432583860405634125513059912765526223015704616050604591207046392807563217109432457129564962571408764292")
+	g1IsogenyConstants.xNum[7].SetString("3650721292069012982822225637849018828271936405382082649291891245623305084633066170122780668657208923883092359301262")
+	g1IsogenyConstants.xNum[8].SetString("1239271775787030039269460763652455868148971086016832054354147730155061349388626624328773377658494412538595239256855")
+	g1IsogenyConstants.xNum[9].SetString("3479374185711034293956731583912244564891370843071137483962415222733470401948838363051960066766720884717833231600798")
+	g1IsogenyConstants.xNum[10].SetString("2492756312273161536685660027440158956721981129429869601638362407515627529461742974364729223659746272460004902959995")
+	g1IsogenyConstants.xNum[11].SetString("1058488477413994682556770863004536636444795456512795473806825292198091015005841418695586811009326456605062948114985")
+	g1IsogenyConstants.xDen[0].SetString("1353092447850172218905095041059784486169131709710991428415161466575141675351394082965234118340787683181925558786844")
+	g1IsogenyConstants.xDen[1].SetString("2822220997908397120956501031591772354860004534930174057793539372552395729721474912921980407622851861692773516917759")
+	g1IsogenyConstants.xDen[2].SetString("1717937747208385987946072944131378949849282930538642983149296304709633281382731764122371874602115081850953846504985")
+	g1IsogenyConstants.xDen[3].SetString("501624051089734157816582944025690868317536915684467868346388760435016044027032505306995281054569109955275640941784")
+	g1IsogenyConstants.xDen[4].SetString("3025903087998593826923738290305187197829899948335370692927241015584233559365859980023579293766193297662657497834014")
+	g1IsogenyConstants.xDen[5].SetString("2224140216975189437834161136818943039444741035168992629437640302964164227138031844090123490881551522278632040105125")
+	g1IsogenyConstants.xDen[6].SetString("1146414465848284837484508420047674663876992808692209238763293935905506532411661921697047880549716175045414621825594")
+	g1IsogenyConstants.xDen[7].SetString("3179090966864399634396993677377903383656908036827452986467581478509513058347781039562481806409014718357094150199902")
+	g1IsogenyConstants.xDen[8].SetString("1549317016540628014674302140786462938410429359529923207442151939696344988707002602944342203885692366490121021806145")
+	g1IsogenyConstants.xDen[9].SetString("1442797143427491432630626390066422021593505165588630398337491100088557278058060064930663878153124164818522816175370")
+	g1IsogenyConstants.yNum[0].SetString("1393399195776646641963150658816615410692049723305861307490980409834842911816308830479576739332720113414154429643571")
+	g1IsogenyConstants.yNum[1].SetString("2968610969752762946134106091152102846225411740689724909058016729455736597929366401532929068084731548131227395540630")
+	g1IsogenyConstants.yNum[2].SetString("122933100683284845219599644396874530871261396084070222155796123161881094323788483360414289333111221370374027338230")
+	g1IsogenyConstants.yNum[3].SetString("303251954782077855462083823228569901064301365507057490567314302006681283228886645653148231378803311079384246777035")
+	g1IsogenyConstants.yNum[4].SetString("1353972356724735644398279028378555627591260676383150667237975415318226973994509601413730187583692624416197017403099")
+	g1IsogenyConstants.yNum[5].SetString("3443977503653895028417260979421240655844034880950251104724609885224259484262346958661845148165419691583810082940400")
+	g1IsogenyConstants.yNum[6].SetString("718493410301850496156792713845282235942975872282052335612908458061560958159410402177452633054233549648465863759602")
+	g1IsogenyConstants.yNum[7].SetString("1466864076415884313141727877156167508644960317046160398342634861648153052436926062434809922037623519108138661903145")
+	g1IsogenyConstants.yNum[8].SetString("1536886493137106337339531461344158973554574987550750910027365237255347020572858445054025958480906372033954157667719")
+	g1IsogenyConstants.yNum[9].SetString("2171468288973248519912068884667133903101171670397991979582205855298465414047741472281361964966463442016062407908400")
+	g1IsogenyConstants.yNum[10].SetString("3915937073730221072189646057898966011292434045388986394373682715266664498392389619761133407846638689998746172899634")
+	g1IsogenyConstants.yNum[11].SetString("3802409194827407598156407709510350851173404795262202653149767739163117554648574333789388883640862266596657730112910")
+	g1IsogenyConstants.yNum[12].SetString("1707589313757812493102695021134258021969283151093981498394095062397393499601961942449581422761005023512037430861560")
+	g1IsogenyConstants.yNum[13].SetString("349697005987545415860583335313370109325490073856352967581197273584891698473628451945217286148025358795756956811571")
+	g1IsogenyConstants.yNum[14].SetString("885704436476567581377743161796735879083481447641210566405057346859953524538988296201011389016649354976986251207243")
+	g1IsogenyConstants.yNum[15].SetString("3370924952219000111210625390420697640496067348723987858345031683392215988129398381698161406651860675722373763741188")
+	g1IsogenyConstants.yDen[0].SetString("3396434800020507717552209507749485772788165484415495716688989613875369612529138640646200921379825018840894888371137")
+	g1IsogenyConstants.yDen[1].SetString("3907278185868397906991868466757978732688957419873771881240086730384895060595583602347317992689443299391009456758845")
+	g1IsogenyConstants.yDen[2].SetString("854914566454823955479427412036002165304466268547334760894270240966182605542146252771872707010378658178126128834546")
+	g1IsogenyConstants.yDen[3].SetString("3496628876382137961119423566187258795236027183112131017519536056628828830323846696121917502443333849318934945158166")
+	g1IsogenyConstants.yDen[4].SetString("1828256966233331991927609917644344011503610008134915752990581590799656305331275863706710232159635159092657073225757")
+	g1IsogenyConstants.yDen[5].SetString("1362317127649143894542621413133849052553333099883364300946623208643344298804722863920546222860227051989127113848748")
+	g1IsogenyConstants.yDen[6].SetString("3443845896188810583748698342858554856823966611538932245284665132724280883115455093457486044009395063504744802318172")
+	g1IsogenyConstants.yDen[7].SetString("3484671274283470572728732863557945897902920439975203610275006103818288159899345245633896492713412187296754791689945")
+	g1IsogenyConstants.yDen[8].SetString("3755735109429418587065437067067640634211015783636675372165599470771975919172394156249639331555277748466603540045130")
+	g1IsogenyConstants.yDen[9].SetString("3459661102222301807083870307127272890283709299202626530836335779816726101522661683404130556379097384249447658110805")
+	g1IsogenyConstants.yDen[10].SetString("742483168411032072323733249644347333168432665415341249073150659015707795549260947228694495111018381111866512337576")
+	g1IsogenyConstants.yDen[11].SetString("1662231279858095762833829698537304807741442669992646287950513237989158777254081548205552083108208170765474149568658")
+	g1IsogenyConstants.yDen[12].SetString("1668238650112823419388205992952852912407572045257706138925379268508860023191233729074751042562151098884528280913356")
+	g1IsogenyConstants.yDen[13].SetString("369162719928976119195087327055926326601627748362769544198813069133429557026740823593067700396825489145575282378487")
+	g1IsogenyConstants.yDen[14].SetString("2164195715141237148945939585099633032390257748382945597506236650132835917087090097395995817229686247227784224263055")
+	g1IsogenyConstants.xDen[10].SetOne()
+	g1IsogenyConstants.yDen[15].SetOne()
+
+	g2IsoCurveCoeffA.SetString("0", "240")
+	g2IsoCurveCoeffB.SetString("1012", "1012")
+	g2SSWUZ.SetString("4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559785",
+		"4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559786") // -(2+i)
+
+	g2IsogenyConstants.xNum[0].SetString("889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542", "889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235542")
+	g2IsogenyConstants.xNum[1].SetString("0", "2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706522")
+	g2IsogenyConstants.xNum[2].SetString("2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706526", "1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853261")
+	g2IsogenyConstants.xNum[3].SetString("3557697382419259905260257622876359250272784728834673675850718343221361467102966990615722337003569479144794908942033", "0")
+	g2IsogenyConstants.xDen[0].SetString("0", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559715")
+	g2IsogenyConstants.xDen[1].SetString("12", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559775")
+	g2IsogenyConstants.yNum[0].SetString("3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558", "3261222600550988246488569487636662646083386001431784202863158481286248011511053074731078808919938689216061999863558")
+	g2IsogenyConstants.yNum[1].SetString("0", "889424345604814976315064405719089812568196182208668418962679585805340366775741747653930584250892369786198727235518")
+	g2IsogenyConstants.yNum[2].SetString("2668273036814444928945193217157269437704588546626005256888038757416021100327225242961791752752677109358596181706524", "1334136518407222464472596608578634718852294273313002628444019378708010550163612621480895876376338554679298090853263")
+	g2IsogenyConstants.yNum[3].SetString("2816510427748580758331037284777117739799287910327449993381818688383577828123182200904113516794492504322962636245776", "0")
+	g2IsogenyConstants.yDen[0].SetString("4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559355", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559355")
+	g2IsogenyConstants.yDen[1].SetString("0", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559571")
+	g2IsogenyConstants.yDen[2].SetString("18", "4002409555221667393417789825735904156556882819939007885332058136124031650490837864442687629129015664037894272559769")
+	g2IsogenyConstants.xDen[2].SetOne()
+	g2IsogenyConstants.yDen[3].SetOne()
+}
+
+// sgn0 returns the "sign" of u
+// https://datatracker.ietf.org/doc/html/rfc9380#section-4.1
+func sgn0(u *fp.Element) bool {
+	_u := *u
+	_u.FromMont()
+	return _u[0]&1 == 1
+}
+
+// sgn0E2 returns the "sign" of u, for u in Fp2
+// https://datatracker.ietf.org/doc/html/rfc9380#section-4.1
+func sgn0E2(u *fptower.E2) bool {
+	return sgn0(&u.A0) || (u.A0.IsZero() && sgn0(&u.A1))
+}
+
+// sswuMapG1 maps u to a point on the curve E1' isogenous to E1
+// https://datatracker.ietf.org/doc/html/rfc9380#section-6.6.2
+func sswuMapG1(u *fp.Element) (x, y fp.Element) {
+
+	var tv1, tv2, x1, gx1, one fp.Element
+	one.SetOne()
+
+	// tv1 = Z * u**2
+	tv1.Square(u).Mul(&tv1, &g1SSWUZ)
+
+	// tv2 = Z**2 * u**4 + Z * u**2
+	tv2.Square(&tv1).Add(&tv2, &tv1)
+
+	if tv2.IsZero() {
+		// exceptional case, x1 = B / (Z * A)
+		x1.Mul(&g1SSWUZ, &g1IsoCurveCoeffA).
+			Inverse(&x1).
+			Mul(&x1, &g1IsoCurveCoeffB)
+	} else {
+		// x1 = (-B / A) * (1 + 1 / tv2)
+		var minusBOverA fp.Element
+		minusBOverA.Inverse(&g1IsoCurveCoeffA).
+			Mul(&minusBOverA, &g1IsoCurveCoeffB).
+			Neg(&minusBOverA)
+		x1.Inverse(&tv2).
+			Add(&x1, &one).
+			Mul(&x1, &minusBOverA)
+	}
+
+	// gx1 = x1**3 + A * x1 + B
+	gx1.Square(&x1).
+		Add(&gx1, &g1IsoCurveCoeffA).
+		Mul(&gx1, &x1).
+		Add(&gx1, &g1IsoCurveCoeffB)
+
+	if gx1.Legendre() != -1 {
+		x.Set(&x1)
+		y.Sqrt(&gx1)
+	} else {
+		// x2 = Z * u**2 * x1 and gx2 = (Z * u**2)**3 * gx1 is a square
+		var gx2 fp.Element
+		x.Mul(&tv1, &x1)
+		gx2.Square(&tv1).
+			Mul(&gx2, &tv1).
+			Mul(&gx2, &gx1)
+		y.Sqrt(&gx2)
+	}
+
+	if sgn0(u) != sgn0(&y) {
+		y.Neg(&y)
+	}
+
+	return
+}
+
+// g1Isogeny maps (x, y) on E1' to E1 with the 11-isogeny
+// https://datatracker.ietf.org/doc/html/rfc9380#appendix-E.2
+func g1Isogeny(x, y *fp.Element) G1Affine {
+
+	var res G1Affine
+	var xNum, xDen, yNum, yDen fp.Element
+
+	xNum = hornerG1(g1IsogenyConstants.xNum[:], x)
+	xDen = hornerG1(g1IsogenyConstants.xDen[:], x)
+	yNum = hornerG1(g1IsogenyConstants.yNum[:], x)
+	yDen = hornerG1(g1IsogenyConstants.yDen[:], x)
+
+	// the kernel of the isogeny is sent to infinity
+	if xDen.IsZero() || yDen.IsZero() {
+		return res
+	}
+
+	res.X.Inverse(&xDen).Mul(&res.X, &xNum)
+	res.Y.Inverse(&yDen).Mul(&res.Y, &yNum).Mul(&res.Y, y)
+
+	return res
+}
+
+// hornerG1 evaluates the polynomial of coefficients c (in increasing degree order) at x
+func hornerG1(c []fp.Element, x *fp.Element) fp.Element {
+	res := c[len(c)-1]
+	for i := len(c) - 2; i >= 0; i-- {
+		res.Mul(&res, x).Add(&res, &c[i])
+	}
+	return res
+}
+
+// mapToCurveG1SSWU maps u to E1, without clearing the cofactor
+func mapToCurveG1SSWU(u *fp.Element) G1Affine {
+	x, y := sswuMapG1(u)
+	return g1Isogeny(&x, &y)
+}
+
+// MapToCurveG1SSWU maps an fp.Element to a point on the curve using the Simplified Shallue and van de Woestijne Ulas map with isogeny
+// https://datatracker.ietf.org/doc/html/rfc9380#section-6.6.3
+func MapToCurveG1SSWU(u fp.Element) G1Affine {
+	res := mapToCurveG1SSWU(&u)
+	res.ClearCofactor(&res)
+	return res
+}
+
+// EncodeToCurveG1SSWU hashes msg to a point on the curve using the Simplified Shallue and van de Woestijne Ulas map with isogeny.
+// The output is not uniformly distributed (suite BLS12381G1_XMD:SHA-256_SSWU_NU_)
+// https://datatracker.ietf.org/doc/html/rfc9380#section-3
+func EncodeToCurveG1SSWU(msg, dst []byte) (G1Affine, error) {
+	var res G1Affine
+	u, err := hashToFp(msg, dst, 1)
+	if err != nil {
+		return res, err
+	}
+	res = MapToCurveG1SSWU(u[0])
+	return res, nil
+}
+
+// HashToCurveG1SSWU hashes msg to a point on the curve using the Simplified Shallue and van de Woestijne Ulas map with isogeny.
+// The output is uniformly distributed (suite BLS12381G1_XMD:SHA-256_SSWU_RO_)
+// https://datatracker.ietf.org/doc/html/rfc9380#section-3
+func HashToCurveG1SSWU(msg, dst []byte) (G1Affine, error) {
+	var res G1Affine
+	u, err := hashToFp(msg, dst, 2)
+	if err != nil {
+		return res, err
+	}
+	Q0 := mapToCurveG1SSWU(&u[0])
+	Q1 := mapToCurveG1SSWU(&u[1])
+	var _Q0, _Q1 G1Jac
+	_Q0.FromAffine(&Q0)
+	_Q1.FromAffine(&Q1).AddAssign(&_Q0)
+	_Q1.ClearCofactor(&_Q1)
+	res.FromJacobian(&_Q1)
+	return res, nil
+}
+
+// sswuMapG2 maps u to a point on the curve E2' isogenous to E2
+// https://datatracker.ietf.org/doc/html/rfc9380#section-6.6.2
+func sswuMapG2(u *fptower.E2) (x, y fptower.E2) {
+
+	var tv1, tv2, x1, gx1, one fptower.E2
+	one.SetOne()
+
+	// tv1 = Z * u**2
+	tv1.Square(u).Mul(&tv1, &g2SSWUZ)
+
+	// tv2 = Z**2 * u**4 + Z * u**2
+	tv2.Square(&tv1).Add(&tv2, &tv1)
+
+	if tv2.IsZero() {
+		// exceptional case, x1 = B / (Z * A)
+		x1.Mul(&g2SSWUZ, &g2IsoCurveCoeffA).
+			Inverse(&x1).
+			Mul(&x1, &g2IsoCurveCoeffB)
+	} else {
+		// x1 = (-B / A) * (1 + 1 / tv2)
+		var minusBOverA fptower.E2
+		minusBOverA.Inverse(&g2IsoCurveCoeffA).
+			Mul(&minusBOverA, &g2IsoCurveCoeffB).
+			Neg(&minusBOverA)
+		x1.Inverse(&tv2).
+			Add(&x1, &one).
+			Mul(&x1, &minusBOverA)
+	}
+
+	// gx1 = x1**3 + A * x1 + B
+	gx1.Square(&x1).
+		Add(&gx1, &g2IsoCurveCoeffA).
+		Mul(&gx1, &x1).
+		Add(&gx1, &g2IsoCurveCoeffB)
+
+	if gx1.Legendre() != -1 {
+		x.Set(&x1)
+		y.Sqrt(&gx1)
+	} else {
+		// x2 = Z * u**2 * x1 and gx2 = (Z * u**2)**3 * gx1 is a square
+		var gx2 fptower.E2
+		x.Mul(&tv1, &x1)
+		gx2.Square(&tv1).
+			Mul(&gx2, &tv1).
+			Mul(&gx2, &gx1)
+		y.Sqrt(&gx2)
+	}
+
+	if sgn0E2(u) != sgn0E2(&y) {
+		y.Neg(&y)
+	}
+
+	return
+}
+
+// g2Isogeny maps (x, y) on E2' to E2 with the 3-isogeny
+// https://datatracker.ietf.org/doc/html/rfc9380#appendix-E.3
+func g2Isogeny(x, y *fptower.E2) G2Affine {
+
+	var res G2Affine
+	var xNum, xDen, yNum, yDen fptower.E2
+
+	xNum = hornerG2(g2IsogenyConstants.xNum[:], x)
+	xDen = hornerG2(g2IsogenyConstants.xDen[:], x)
+	yNum = hornerG2(g2IsogenyConstants.yNum[:], x)
+	yDen = hornerG2(g2IsogenyConstants.yDen[:], x)
+
+	// the kernel of the isogeny is sent to infinity
+	if xDen.IsZero() || yDen.IsZero() {
+		return res
+	}
+
+	res.X.Inverse(&xDen).Mul(&res.X, &xNum)
+	res.Y.Inverse(&yDen).Mul(&res.Y, &yNum).Mul(&res.Y, y)
+
+	return res
+}
+
+// hornerG2 evaluates the polynomial of coefficients c (in increasing degree order) at x
+func hornerG2(c []fptower.E2, x *fptower.E2) fptower.E2 {
+	res := c[len(c)-1]
+	for i := len(c) - 2; i >= 0; i-- {
+		res.Mul(&res, x).Add(&res, &c[i])
+	}
+	return res
+}
+
+// mapToCurveG2SSWU maps u to E2, without clearing the cofactor
+func mapToCurveG2SSWU(u *fptower.E2) G2Affine {
+	x, y := sswuMapG2(u)
+	return g2Isogeny(&x, &y)
+}
+
+// MapToCurveG2SSWU maps an fptower.E2 to a point on the curve using the Simplified Shallue and van de Woestijne Ulas map with isogeny
+// https://datatracker.ietf.org/doc/html/rfc9380#section-6.6.3
+func MapToCurveG2SSWU(u fptower.E2) G2Affine {
+	var res G2Affine
+	var _res G2Jac
+	Q := mapToCurveG2SSWU(&u)
+	_res.FromAffine(&Q)
+	_res.clearCofactorSSWU(&_res)
+	res.FromJacobian(&_res)
+	return res
+}
+
+// EncodeToCurveG2SSWU hashes msg to a point on the curve using the Simplified Shallue and van de Woestijne Ulas map with isogeny.
+// The output is not uniformly distributed (suite BLS12381G2_XMD:SHA-256_SSWU_NU_)
+// https://datatracker.ietf.org/doc/html/rfc9380#section-3
+func EncodeToCurveG2SSWU(msg, dst []byte) (G2Affine, error) {
+	var res G2Affine
+	_u, err := hashToFp(msg, dst, 2)
+	if err != nil {
+		return res, err
+	}
+	var u fptower.E2
+	u.A0.Set(&_u[0])
+	u.A1.Set(&_u[1])
+	res = MapToCurveG2SSWU(u)
+	return res, nil
+}
+
+// HashToCurveG2SSWU hashes msg to a point on the curve using the Simplified Shallue and van de Woestijne Ulas map with isogeny.
+// The output is uniformly distributed (suite BLS12381G2_XMD:SHA-256_SSWU_RO_)
+// https://datatracker.ietf.org/doc/html/rfc9380#section-3
+func HashToCurveG2SSWU(msg, dst []byte) (G2Affine, error) {
+	var res G2Affine
+	u, err := hashToFp(msg, dst, 4)
+	if err != nil {
+		return res, err
+	}
+	var u0, u1 fptower.E2
+	u0.A0.Set(&u[0])
+	u0.A1.Set(&u[1])
+	u1.A0.Set(&u[2])
+	u1.A1.Set(&u[3])
+	Q0 := mapToCurveG2SSWU(&u0)
+	Q1 := mapToCurveG2SSWU(&u1)
+	var _Q0, _Q1 G2Jac
+	_Q0.FromAffine(&Q0)
+	_Q1.FromAffine(&Q1).AddAssign(&_Q0)
+	_Q1.clearCofactorSSWU(&_Q1)
+	res.FromJacobian(&_Q1)
+	return res, nil
+}
+
+// clearCofactorSSWU maps a point of E2 to G2 by multiplying it by the
+// effective cofactor h_eff of the hash-to-curve suites, using the
+// endomorphism psi (Budroni-Pintore method)
+// https://datatracker.ietf.org/doc/html/rfc9380#appendix-G.3
+func (p *G2Jac) clearCofactorSSWU(a *G2Jac) *G2Jac {
+	// c1 = -xGen
+	var t1, t2, t3 G2Jac
+
+	// t1 = c1 * P
+	t1.ScalarMultiplication(a, &xGen).Neg(&t1)
+	// t2 = psi(P)
+	t2.psi(a)
+	// t3 = psi2(2 * P) - psi(P)
+	t3.Double(a).psi(&t3).psi(&t3).SubAssign(&t2)
+	// t2 = c1 * (t1 + t2)
+	t2.AddAssign(&t1)
+	t2.ScalarMultiplication(&t2, &xGen).Neg(&t2)
+	// Q = t3 + t2 - t1 - P
+	t3.AddAssign(&t2).SubAssign(&t1).SubAssign(a)
+
+	p.Set(&t3)
+	return p
+}
@@ -314,6 +314,26 @@ func TestE12Ops(t *testing.T) {
 
 }
 
+func TestE12BatchInvert(t *testing.T) {
+	const n = 10
+	a := make([]E12, n)
+	for i := 1; i < n; i++ {
+		a[i].SetRandom()
+	}
+
+	res := BatchInvertE12(a)
+	if !res[0].IsZero() {
+		t.Fatal("inverse of 0 should be 0")
+	}
+	for i := 1; i < n; i++ {
+		var expected E12
+		expected.Inverse(&a[i])
+		if !res[i].Equal(&expected) {
+			t.Fatal("BatchInvertE12 doesn't match Inverse")
+		}
+	}
+}
+
 // ------------------------------------------------------------
 // benches
 
@@ -49,6 +49,11 @@ func (z *E6) SetOne() *E6 {
 	return z
 }
 
+// IsZero returns true if z is zero, false otherwise
+func (z *E6) IsZero() bool {
+	return z.B0.IsZero() && z.B1.IsZero() && z.B2.IsZero()
+}
+
 // SetRandom set z to a random elmt
 func (z *E6) SetRandom() (*E6, error) {
 	if _, err := z.B0.SetRandom(); err != nil {
@@ -198,3 +203,39 @@ func (z *E6) Inverse(x *E6) *E6 {
 
 	return z
 }
+
+// BatchInvertE6 returns a new slice with every element inverted.
+// Uses Montgomery batch inversion trick: a single E6 inversion is performed.
+// Zero elements are left unchanged (their inverse is set to 0).
+func BatchInvertE6(a []E6) []E6 {
+	res := make([]E6, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E6
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i] = accumulator
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	var accInverse E6
+	accInverse.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accInverse)
+		accInverse.Mul(&accInverse, &a[i])
+	}
+
+	return res
+}
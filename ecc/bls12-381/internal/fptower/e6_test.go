@@ -214,6 +214,26 @@ func TestE6Ops(t *testing.T) {
 
 }
 
+func TestE6BatchInvert(t *testing.T) {
+	const n = 10
+	a := make([]E6, n)
+	for i := 1; i < n; i++ {
+		a[i].SetRandom()
+	}
+
+	res := BatchInvertE6(a)
+	if !res[0].IsZero() {
+		t.Fatal("inverse of 0 should be 0")
+	}
+	for i := 1; i < n; i++ {
+		var expected E6
+		expected.Inverse(&a[i])
+		if !res[i].Equal(&expected) {
+			t.Fatal("BatchInvertE6 doesn't match Inverse")
+		}
+	}
+}
+
 // ------------------------------------------------------------
 // benches
 
@@ -68,7 +68,7 @@ func NewDecoder(r io.Reader) *Decoder {
 }
 
 // Decode reads the binary encoding of v from the stream
-// type must be *uint64, *fr.Element, *fp.Element, *G1Affine, *G2Affine, *[]G1Affine or *[]G2Affine
+// type must be *uint64, *fr.Element, *fp.Element, *GT, *G1Affine, *G2Affine, *[]G1Affine or *[]G2Affine
 func (dec *Decoder) Decode(v interface{}) (err error) {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() || !rv.Elem().CanSet() {
@@ -106,6 +106,15 @@ func (dec *Decoder) Decode(v interface{}) (err error) {
 		}
 		t.SetBytes(buf[:fp.Limbs*8])
 		return
+	case *GT:
+		var bufGT [SizeOfGT]byte
+		read, err = io.ReadFull(dec.r, bufGT[:])
+		dec.n += int64(read)
+		if err != nil {
+			return
+		}
+		err = t.SetBytes(bufGT[:])
+		return
 	case *G1Affine:
 		// we start by reading compressed point size, if metadata tells us it is uncompressed, we read more.
 		read, err = io.ReadFull(dec.r, buf[:SizeOfG1AffineCompressed])
@@ -305,7 +314,7 @@ func NewEncoder(w io.Writer, options ...func(*Encoder)) *Encoder {
 }
 
 // Encode writes the binary encoding of v to the stream
-// type must be uint64, *fr.Element, *fp.Element, *G1Affine, *G2Affine, []G1Affine or []G2Affine
+// type must be uint64, *fr.Element, *fp.Element, *GT, *G1Affine, *G2Affine, []G1Affine or []G2Affine
 func (enc *Encoder) Encode(v interface{}) (err error) {
 	if enc.raw {
 		return enc.encodeRaw(v)
@@ -347,6 +356,11 @@ func (enc *Encoder) encode(v interface{}) (err error) {
 		written, err = enc.w.Write(buf[:])
 		enc.n += int64(written)
 		return
+	case *GT:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:])
+		enc.n += int64(written)
+		return
 	case *G1Affine:
 		buf := t.Bytes()
 		written, err = enc.w.Write(buf[:])
@@ -421,6 +435,11 @@ func (enc *Encoder) encodeRaw(v interface{}) (err error) {
 		written, err = enc.w.Write(buf[:])
 		enc.n += int64(written)
 		return
+	case *GT:
+		buf := t.Bytes()
+		written, err = enc.w.Write(buf[:])
+		enc.n += int64(written)
+		return
 	case *G1Affine:
 		buf := t.RawBytes()
 		written, err = enc.w.Write(buf[:])
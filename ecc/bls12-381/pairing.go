@@ -16,6 +16,7 @@ package bls12381
 
 import (
 	"errors"
+	"io"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/internal/fptower"
 )
@@ -170,6 +171,154 @@ func MillerLoop(P []G1Affine, Q []G2Affine) (GT, error) {
 	return result, nil
 }
 
+// G2Prepared stores the lines of the Miller loop of a fixed G2 point, which don't
+// depend on the G1 point, so that they are computed once (see MillerLoopFixedQ)
+type G2Prepared struct {
+	q     G2Affine
+	lines []lineEvaluation
+}
+
+// NewG2Prepared computes the lines of the Miller loop of Q
+func NewG2Prepared(Q *G2Affine) *G2Prepared {
+	res := &G2Prepared{q: *Q}
+	if Q.IsInfinity() {
+		return res
+	}
+	res.lines = make([]lineEvaluation, 0, nbPreparedLines())
+
+	var qProj g2Proj
+	qProj.FromAffine(Q)
+
+	var l lineEvaluation
+
+	// i == 62
+	qProj.DoubleStep(&l)
+	res.lines = append(res.lines, l)
+	qProj.AddMixedStep(&l, Q)
+	res.lines = append(res.lines, l)
+
+	for i := 61; i >= 0; i-- {
+		qProj.DoubleStep(&l)
+		res.lines = append(res.lines, l)
+
+		if loopCounter[i] == 0 {
+			continue
+		}
+
+		qProj.AddMixedStep(&l, Q)
+		res.lines = append(res.lines, l)
+	}
+
+	return res
+}
+
+// nbPreparedLines returns the number of lines stored in a G2Prepared
+func nbPreparedLines() int {
+	n := 2
+	for i := 61; i >= 0; i-- {
+		n++
+		if loopCounter[i] != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// mulByLine multiplies result by the line l evaluated at p
+func mulByLine(result *GT, l *lineEvaluation, p *G1Affine) {
+	var r1, r2 fptower.E2
+	r1.MulByElement(&l.r1, &p.X)
+	r2.MulByElement(&l.r2, &p.Y)
+	result.MulBy014(&l.r0, &r1, &r2)
+}
+
+// MillerLoopFixedQ Miller loop, where the G2 points are given with their precomputed lines
+func MillerLoopFixedQ(P []G1Affine, Q []G2Prepared) (GT, error) {
+	n := len(P)
+	if n == 0 || n != len(Q) {
+		return GT{}, errors.New("invalid inputs sizes")
+	}
+
+	// filter infinity points
+	p := make([]G1Affine, 0, n)
+	q := make([]*G2Prepared, 0, n)
+
+	for k := 0; k < n; k++ {
+		if P[k].IsInfinity() || Q[k].q.IsInfinity() {
+			continue
+		}
+		if len(Q[k].lines) != nbPreparedLines() {
+			return GT{}, errors.New("invalid prepared G2 point")
+		}
+		p = append(p, P[k])
+		q = append(q, &Q[k])
+	}
+	n = len(p)
+
+	var result GT
+	result.SetOne()
+
+	// i == 62
+	for k := 0; k < n; k++ {
+		mulByLine(&result, &q[k].lines[0], &p[k])
+		mulByLine(&result, &q[k].lines[1], &p[k])
+	}
+
+	j := 2
+	for i := 61; i >= 0; i-- {
+		result.Square(&result)
+
+		for k := 0; k < n; k++ {
+			mulByLine(&result, &q[k].lines[j], &p[k])
+		}
+		j++
+
+		if loopCounter[i] == 0 {
+			continue
+		}
+
+		for k := 0; k < n; k++ {
+			mulByLine(&result, &q[k].lines[j], &p[k])
+		}
+		j++
+	}
+
+	result.Conjugate(&result)
+
+	return result, nil
+}
+
+// PairingCheckFixedQ calculates the reduced pairing for a set of points, where the G2 points
+// are given with their precomputed lines, and returns True if the result is One
+func PairingCheckFixedQ(P []G1Affine, Q []G2Prepared) (bool, error) {
+	f, err := MillerLoopFixedQ(P, Q)
+	if err != nil {
+		return false, err
+	}
+	f = FinalExponentiation(&f)
+	var one GT
+	one.SetOne()
+	return f.Equal(&one), nil
+}
+
+// WriteTo writes the binary encoding of the prepared point: the point only,
+// as the lines are computed again from it by ReadFrom
+func (q *G2Prepared) WriteTo(w io.Writer) (int64, error) {
+	enc := NewEncoder(w)
+	err := enc.Encode(&q.q)
+	return enc.BytesWritten(), err
+}
+
+// ReadFrom decodes a prepared point written with WriteTo, and computes its lines
+func (q *G2Prepared) ReadFrom(r io.Reader) (int64, error) {
+	dec := NewDecoder(r)
+	if err := dec.Decode(&q.q); err != nil {
+		return dec.BytesRead(), err
+	}
+	*q = *NewG2Prepared(&q.q)
+	return dec.BytesRead(), nil
+}
+
 // DoubleStep doubles a point in Homogenous projective coordinates, and evaluates the line in Miller loop
 // https://eprint.iacr.org/2013/722.pdf (Section 4.3)
 func (p *g2Proj) DoubleStep(l *lineEvaluation) {
@@ -0,0 +1,207 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+func TestElligator2Map(t *testing.T) {
+
+	if elligator2Z.Legendre() != -1 {
+		t.Fatal("Z should not be a square")
+	}
+
+	// the denominator 1 + Z*u^2 of x1 never vanishes, as -1/Z is not a square (-1 is a square in fr)
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne)
+	if minusOne.Legendre() != 1 {
+		t.Fatal("-1 should be a square")
+	}
+
+	// u = 0 is mapped with x1 = -A
+	var zero fr.Element
+	inputs := []fr.Element{zero}
+	for i := 0; i < 20; i++ {
+		var u fr.Element
+		u.SetRandom()
+		inputs = append(inputs, u)
+	}
+
+	for _, u := range inputs {
+		p := elligator2Map(u)
+		if !p.IsOnCurve() {
+			t.Fatalf("elligator2Map(%s) is not on the curve", u.String())
+		}
+
+		// u and -u are mapped to the same point, as only u^2 is used to select x
+		var v fr.Element
+		v.Neg(&u)
+		if q := elligator2Map(v); !q.Equal(&p) {
+			t.Fatal("elligator2Map(u) and elligator2Map(-u) should be equal")
+		}
+	}
+}
+
+func TestHashToCurve(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+	for i := 0; i < 10; i++ {
+		msg := []byte(fmt.Sprintf("msg %d", i))
+
+		for _, hashToCurve := range []func(msg, dst []byte) (PointAffine, error){HashToCurve, EncodeToCurve} {
+			p, err := hashToCurve(msg, dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !p.IsOnCurve() {
+				t.Fatal("hash to curve output should be on the curve")
+			}
+
+			// the output is in the prime order subgroup
+			var pProj, q PointProj
+			pProj.FromAffine(&p)
+			if scalarMulNaive(&q, &pProj, &ed.Order); !q.IsZero() || pProj.IsZero() {
+				t.Fatal("hash to curve output should be in the prime order subgroup")
+			}
+
+			// the output is deterministic, and depends on the message and on the domain separation tag
+			p2, _ := hashToCurve(msg, dst)
+			p3, _ := hashToCurve(append(msg, 0), dst)
+			p4, _ := hashToCurve(msg, []byte("another DST"))
+			if !p.Equal(&p2) || p.Equal(&p3) || p.Equal(&p4) {
+				t.Fatal("hash to curve output should depend only on the message and on the domain separation tag")
+			}
+		}
+	}
+
+	// EncodeToCurve(msg) = MapToCurve(u), with u = hash_to_field(msg)
+	u, err := hashToFr([]byte("abc"), dst, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MapToCurve(u[0])
+	p, _ := EncodeToCurve([]byte("abc"), dst)
+	if !p.Equal(&expected) {
+		t.Fatal("EncodeToCurve and MapToCurve mismatch")
+	}
+
+	// HashToCurve(msg) = MapToCurve(u0) + MapToCurve(u1), with u0, u1 = hash_to_field(msg)
+	if u, err = hashToFr([]byte("abc"), dst, 2); err != nil {
+		t.Fatal(err)
+	}
+	q0, q1 := MapToCurve(u[0]), MapToCurve(u[1])
+	expected.Add(&q0, &q1)
+	p, _ = HashToCurve([]byte("abc"), dst)
+	if !p.Equal(&expected) {
+		t.Fatal("HashToCurve and MapToCurve mismatch")
+	}
+}
+
+// TestHashToCurveVectors checks hash_to_field, the Elligator 2 map and HashToCurve against known answers,
+// computed with an independent implementation of the generic steps of RFC 9380 (expand_message_xmd
+// with SHA-256, hash_to_field, map_to_curve_elligator2 on the Montgomery curve, the rational map of
+// appendix D and clear_cofactor). q0 and q1 are the outputs of the map before the cofactor is cleared.
+func TestHashToCurveVectors(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+	vectors := []struct {
+		msg       string
+		u0, u1    string
+		q0, q1, p [2]string
+	}{
+		{
+			msg: "",
+			u0:  "28278357814166947568488584426491389479439390112357611701312376267537105050041",
+			u1:  "49308309320967913505919568061414385396948656328546067415362443647623667680601",
+			q0:  [2]string{"14582618134704681524632895658496248399920796556709811837714150884977562951873", "38580273634006755989372971551282208261537710679475790242920409777971515008617"},
+			q1:  [2]string{"40587232743358579909067738748155486190441774387822466477369907107219744777671", "4058927410184930395363306686112391490573819997459375240684111367396578987398"},
+			p:   [2]string{"22417928199527407920627046477270441130799082221772292267170352251676890968895", "38500145946948732375398388876571549259665469440087888766370751952052502597471"},
+		},
+		{
+			msg: "abc",
+			u0:  "8806368777326611730071432931469475601663107643044540149455546132280892900883",
+			u1:  "330432291611276014638773701200383811214971228698308470022688781867633840072",
+			q0:  [2]string{"39892293173524440400680294438116733435689126316081253945494569105436854099635", "48652869358753778125909656227177749784693695812078540146679000358504374552336"},
+			q1:  [2]string{"300807711066976343086995358495933403652402119317510877269758054288795271507", "31064805062932209788750914884018731827404555200085950494884465889657754093354"},
+			p:   [2]string{"38946581924815451446154477254900198163017954802278141534307171662306974940960", "25882159404170930227942922479293422066714875800742240258498688698883440357204"},
+		},
+		{
+			msg: "abcdef0123456789",
+			u0:  "38772386909081436743329575645801769926301001313287835297014683976293361251597",
+			u1:  "5931881820503667569547080286518883897309992521973793238071429988488969071916",
+			q0:  [2]string{"39716707512709834768314332957655606503764288171022216031535800700980390965446", "19075987162088756790749286558307692160332498720810330981718737204367528520565"},
+			q1:  [2]string{"22204581012315123270503266591402604526559400823988484669822131438678173162646", "334228561112272903703432021367373122490642200705808320955687439058296069846"},
+			p:   [2]string{"27619960734386572893114755218318999840796863831403933954395675578106738950871", "17615038566165481511398958817067269061468838064039351414519155868443732154071"},
+		},
+	}
+
+	for _, v := range vectors {
+		u, err := hashToFr([]byte(v.msg), dst, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var u0, u1 fr.Element
+		u0.SetString(v.u0)
+		u1.SetString(v.u1)
+		if !u[0].Equal(&u0) || !u[1].Equal(&u1) {
+			t.Fatalf("hashToFr(%q) mismatch", v.msg)
+		}
+
+		var q0, q1, expected PointAffine
+		q0.X.SetString(v.q0[0])
+		q0.Y.SetString(v.q0[1])
+		q1.X.SetString(v.q1[0])
+		q1.Y.SetString(v.q1[1])
+		if p := elligator2Map(u0); !p.Equal(&q0) {
+			t.Fatalf("elligator2Map(u0) mismatch for msg %q", v.msg)
+		}
+		if p := elligator2Map(u1); !p.Equal(&q1) {
+			t.Fatalf("elligator2Map(u1) mismatch for msg %q", v.msg)
+		}
+
+		p, err := HashToCurve([]byte(v.msg), dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected.X.SetString(v.p[0])
+		expected.Y.SetString(v.p[1])
+		if !p.Equal(&expected) {
+			t.Fatalf("HashToCurve(%q) mismatch", v.msg)
+		}
+	}
+}
+
+func BenchmarkHashToCurve(b *testing.B) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	msg := []byte("abc")
+	b.Run("EncodeToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncodeToCurve(msg, dst)
+		}
+	})
+	b.Run("HashToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			HashToCurve(msg, dst)
+		}
+	})
+}
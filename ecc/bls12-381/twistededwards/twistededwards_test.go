@@ -163,3 +163,36 @@ func TestScalarMul(t *testing.T) {
 	}
 
 }
+
+func TestScalarMulSecret(t *testing.T) {
+
+	// set curve parameters
+	ed := GetEdwardsCurve()
+
+	var cofactor, order big.Int
+	ed.Cofactor.ToBigInt(&cofactor)
+	order.Mul(&ed.Order, &cofactor)
+
+	var s fr.Element
+	s.SetRandom()
+	var large big.Int
+	large.Lsh(&order, 8*fr.Bytes).Add(&large, big.NewInt(5))
+
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(23902374),
+		new(big.Int).Sub(&order, big.NewInt(1)),
+		s.ToBigIntRegular(new(big.Int)),
+		&large, // reduced modulo the order of the curve
+	}
+
+	for _, scalar := range scalars {
+		var expected, p PointAffine
+		expected.ScalarMul(&ed.Base, scalar)
+		p.ScalarMulSecret(&ed.Base, scalar)
+		if !p.Equal(&expected) {
+			t.Fatalf("ScalarMulSecret and ScalarMul mismatch for scalar %s", scalar.String())
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eip196 implements the Ethereum precompiled contracts on bn254, with the
+// byte formats of EIP-196 (ecAdd, ecMul) and EIP-197 (ecPairing).
+//
+// Field elements are encoded as 32-byte big-endian integers, which must be smaller than the modulus.
+// A G1 point is encoded as x || y, a G2 point as x.A1 || x.A0 || y.A1 || y.A0
+// (imaginary part first), and the point at infinity as zeros.
+//
+// The functions accept and reject the same inputs as the go-ethereum implementation,
+// with the same error messages.
+package eip196
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+const (
+	// SizeOfFp size in bytes of an encoded coordinate
+	SizeOfFp = 32
+	// SizeOfG1 size in bytes of an encoded G1 point
+	SizeOfG1 = 2 * SizeOfFp
+	// SizeOfG2 size in bytes of an encoded G2 point
+	SizeOfG2 = 4 * SizeOfFp
+	// SizeOfScalar size in bytes of an ecMul scalar
+	SizeOfScalar = 32
+	// SizeOfPair size in bytes of a (G1, G2) pair of the ecPairing input
+	SizeOfPair = SizeOfG1 + SizeOfG2
+)
+
+var (
+	ErrNotEnoughData            = errors.New("bn256: not enough data")
+	ErrCoordinateExceedsModulus = errors.New("bn256: coordinate exceeds modulus")
+	ErrMalformedPoint           = errors.New("bn256: malformed point")
+	ErrBadPairingInput          = errors.New("bad elliptic curve pairing size")
+)
+
+// Add implements the ecAdd precompile (address 0x06): the input is
+// right-padded with zeros (or truncated) to two encoded G1 points, and their sum is returned.
+func Add(input []byte) ([]byte, error) {
+	input = getData(input, 2*SizeOfG1)
+
+	var p, q bn254.G1Affine
+	if err := UnmarshalG1(&p, input[:SizeOfG1]); err != nil {
+		return nil, err
+	}
+	if err := UnmarshalG1(&q, input[SizeOfG1:]); err != nil {
+		return nil, err
+	}
+
+	var res, _q bn254.G1Jac
+	res.FromAffine(&p)
+	_q.FromAffine(&q)
+	res.AddAssign(&_q)
+	p.FromJacobian(&res)
+
+	return MarshalG1(&p), nil
+}
+
+// ScalarMul implements the ecMul precompile (address 0x07): the input is
+// right-padded with zeros (or truncated) to an encoded G1 point followed by a 32-byte
+// big-endian scalar, and the point multiplied by the scalar is returned.
+func ScalarMul(input []byte) ([]byte, error) {
+	input = getData(input, SizeOfG1+SizeOfScalar)
+
+	var p bn254.G1Affine
+	if err := UnmarshalG1(&p, input[:SizeOfG1]); err != nil {
+		return nil, err
+	}
+
+	// G1 has prime order r
+	var s big.Int
+	s.SetBytes(input[SizeOfG1:]).Mod(&s, fr.Modulus())
+	p.ScalarMultiplication(&p, &s)
+
+	return MarshalG1(&p), nil
+}
+
+// Pairing implements the ecPairing precompile (address 0x08): the input is a
+// sequence of encoded (G1, G2) pairs, and the output is the 32-byte big-endian
+// encoding of 1 if the product of their pairings is 1, and 0 otherwise.
+// An empty input returns 1.
+func Pairing(input []byte) ([]byte, error) {
+	if len(input)%SizeOfPair != 0 {
+		return nil, ErrBadPairingInput
+	}
+
+	n := len(input) / SizeOfPair
+	P := make([]bn254.G1Affine, n)
+	Q := make([]bn254.G2Affine, n)
+	for i := 0; i < n; i++ {
+		pair := input[i*SizeOfPair : (i+1)*SizeOfPair]
+		if err := UnmarshalG1(&P[i], pair[:SizeOfG1]); err != nil {
+			return nil, err
+		}
+		if err := UnmarshalG2(&Q[i], pair[SizeOfG1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	res := make([]byte, 32)
+	if n == 0 {
+		res[31] = 1
+		return res, nil
+	}
+	ok, err := bn254.PairingCheck(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		res[31] = 1
+	}
+	return res, nil
+}
+
+// MarshalG1 returns the encoding of p, x || y
+func MarshalG1(p *bn254.G1Affine) []byte {
+	res := make([]byte, SizeOfG1)
+	if p.IsInfinity() {
+		return res
+	}
+	putFp(res[:SizeOfFp], &p.X)
+	putFp(res[SizeOfFp:], &p.Y)
+	return res
+}
+
+// UnmarshalG1 sets p from the encoding in buf (see MarshalG1), and checks
+// that it is on the curve. Zeros decode to the point at infinity.
+func UnmarshalG1(p *bn254.G1Affine, buf []byte) error {
+	if len(buf) < SizeOfG1 {
+		return ErrNotEnoughData
+	}
+	if err := setFp(&p.X, buf[:SizeOfFp]); err != nil {
+		return err
+	}
+	if err := setFp(&p.Y, buf[SizeOfFp:SizeOfG1]); err != nil {
+		return err
+	}
+	if !p.IsInfinity() && !p.IsOnCurve() {
+		return ErrMalformedPoint
+	}
+	return nil
+}
+
+// MarshalG2 returns the encoding of p, x.A1 || x.A0 || y.A1 || y.A0
+func MarshalG2(p *bn254.G2Affine) []byte {
+	res := make([]byte, SizeOfG2)
+	if p.IsInfinity() {
+		return res
+	}
+	putFp(res[:SizeOfFp], &p.X.A1)
+	putFp(res[SizeOfFp:2*SizeOfFp], &p.X.A0)
+	putFp(res[2*SizeOfFp:3*SizeOfFp], &p.Y.A1)
+	putFp(res[3*SizeOfFp:], &p.Y.A0)
+	return res
+}
+
+// UnmarshalG2 sets p from the encoding in buf (see MarshalG2), and checks
+// that it is on the curve and in the prime order subgroup. Zeros decode to the point at infinity.
+func UnmarshalG2(p *bn254.G2Affine, buf []byte) error {
+	if len(buf) < SizeOfG2 {
+		return ErrNotEnoughData
+	}
+	toSet := []*fp.Element{&p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0}
+	for i, e := range toSet {
+		if err := setFp(e, buf[i*SizeOfFp:(i+1)*SizeOfFp]); err != nil {
+			return err
+		}
+	}
+	if !p.IsInfinity() && !p.IsInSubGroup() {
+		return ErrMalformedPoint
+	}
+	return nil
+}
+
+// putFp writes the 32-byte big-endian encoding of e in buf
+func putFp(buf []byte, e *fp.Element) {
+	b := e.Bytes()
+	copy(buf, b[:])
+}
+
+// setFp sets e from the 32-byte big-endian integer in buf, which must be smaller than the modulus
+func setFp(e *fp.Element, buf []byte) error {
+	var v big.Int
+	v.SetBytes(buf)
+	if v.Cmp(fp.Modulus()) != -1 {
+		return ErrCoordinateExceedsModulus
+	}
+	e.SetBigInt(&v)
+	return nil
+}
+
+// getData returns input right-padded with zeros, or truncated, to size bytes
+func getData(input []byte, size int) []byte {
+	res := make([]byte, size)
+	copy(res, input)
+	return res
+}
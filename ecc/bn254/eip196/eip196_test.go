@@ -0,0 +1,117 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip196
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// precompileTest is a test vector, in the format of go-ethereum's core/vm/testdata/precompiles.
+// Only the chfast and jeff vectors come from there, the others are built from the generators.
+// The official bn256Add, bn256ScalarMul and bn256Pairing files use the same names and format,
+// and can replace them as is.
+type precompileTest struct {
+	Input, Expected string
+	Name            string
+}
+
+// precompileFailureTest is a failing test vector, in the format of the fail-*.json files of
+// go-ethereum's core/vm/testdata/precompiles
+type precompileFailureTest struct {
+	Input         string
+	ExpectedError string
+	Name          string
+}
+
+var precompiles = map[string]func([]byte) ([]byte, error){
+	"bn256Add":       Add,
+	"bn256ScalarMul": ScalarMul,
+	"bn256Pairing":   Pairing,
+}
+
+func loadJSON(t *testing.T, name string, v interface{}) {
+	data, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPrecompiles(t *testing.T) {
+	for name, f := range precompiles {
+		var tests []precompileTest
+		loadJSON(t, name, &tests)
+		for _, test := range tests {
+			input, _ := hex.DecodeString(test.Input)
+			res, err := f(input)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", name, test.Name, err)
+			}
+			if hex.EncodeToString(res) != test.Expected {
+				t.Fatalf("%s/%s: wrong output", name, test.Name)
+			}
+		}
+	}
+}
+
+func TestPrecompilesErrors(t *testing.T) {
+	for name, f := range precompiles {
+		var tests []precompileFailureTest
+		loadJSON(t, "fail-"+name, &tests)
+		if len(tests) == 0 {
+			t.Fatalf("%s: no failing test vectors", name)
+		}
+		for _, test := range tests {
+			input, _ := hex.DecodeString(test.Input)
+			_, err := f(input)
+			if err == nil || err.Error() != test.ExpectedError {
+				t.Fatalf("%s/%s: expected %q, got %v", name, test.Name, test.ExpectedError, err)
+			}
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	var p bn254.G1Affine
+	var q bn254.G2Affine
+	_, _, p, q = bn254.Generators()
+
+	var p2 bn254.G1Affine
+	if err := UnmarshalG1(&p2, MarshalG1(&p)); err != nil || !p2.Equal(&p) {
+		t.Fatal("unmarshal(marshal(G1)) failed")
+	}
+	var q2 bn254.G2Affine
+	if err := UnmarshalG2(&q2, MarshalG2(&q)); err != nil || !q2.Equal(&q) {
+		t.Fatal("unmarshal(marshal(G2)) failed")
+	}
+
+	// the point at infinity is encoded as zeros
+	var inf bn254.G2Affine
+	if !bytes.Equal(MarshalG2(&inf), make([]byte, SizeOfG2)) {
+		t.Fatal("infinity should be encoded as zeros")
+	}
+	if err := UnmarshalG1(&p2, MarshalG1(&p)[:SizeOfG1-1]); err != ErrNotEnoughData {
+		t.Fatal("expected ErrNotEnoughData")
+	}
+}
@@ -945,3 +945,73 @@ func (z *Element) Inverse(x *Element) *Element {
 	}
 
 }
+
+// BatchInvert returns a new slice with every element inverted.
+// Uses Montgomery batch inversion trick: a single field inversion is performed.
+// Zero elements are left unchanged (their inverse is set to 0).
+func BatchInvert(a []Element) []Element {
+	res := make([]Element, len(a))
+	batchInvert(a, res)
+	return res
+}
+
+// BatchInvertParallel is BatchInvert, splitting a in nbTasks chunks
+// inverted concurrently (one field inversion per chunk).
+func BatchInvertParallel(a []Element, nbTasks int) []Element {
+	res := make([]Element, len(a))
+	if nbTasks > len(a) {
+		nbTasks = len(a)
+	}
+	if nbTasks <= 1 {
+		batchInvert(a, res)
+		return res
+	}
+
+	chunkSize := len(a) / nbTasks
+	remainder := len(a) % nbTasks
+
+	var wg sync.WaitGroup
+	wg.Add(nbTasks)
+	start := 0
+	for i := 0; i < nbTasks; i++ {
+		end := start + chunkSize
+		if i < remainder {
+			end++
+		}
+		go func(start, end int) {
+			batchInvert(a[start:end], res[start:end])
+			wg.Done()
+		}(start, end)
+		start = end
+	}
+	wg.Wait()
+
+	return res
+}
+
+// batchInvert sets res[i] = a[i]^-1 (0 if a[i] == 0), res and a must not overlap
+func batchInvert(a, res []Element) {
+	zeroes := make([]bool, len(a))
+	var accumulator Element
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i] = accumulator
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	var accInverse Element
+	accInverse.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accInverse)
+		accInverse.Mul(&accInverse, &a[i])
+	}
+}
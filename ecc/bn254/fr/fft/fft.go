@@ -17,9 +17,11 @@
 package fft
 
 import (
+	"context"
 	"math/bits"
 	"runtime"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/internal/parallel"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
@@ -47,8 +49,18 @@ const butterflyThreshold = 16
 // domain := NewDomain(m, 2) -->  contains precomputed data for Z/mZ, and Z/4mZ
 // FFT(pol, DIT, 1) --> evaluates pol on the coset 1 in (Z/4mZ)/(Z/mZ)
 func (domain *Domain) FFT(a []fr.Element, decimation Decimation, coset uint64) {
+	_ = domain.FFTContext(context.Background(), a, decimation, coset, ecc.FFTConfig{})
+}
 
-	numCPU := uint64(runtime.NumCPU())
+// FFTContext computes the discrete Fourier transform of a, as FFT does, with at most config.NbTasks go routines.
+//
+// If ctx is done before the end of the computation, the go routines stop early and
+// FFTContext returns ctx.Err(), leaving a in an undefined state.
+func (domain *Domain) FFTContext(ctx context.Context, a []fr.Element, decimation Decimation, coset uint64, config ecc.FFTConfig) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s := newFFTScheduler(ctx, config)
 
 	if coset != 0 {
 		if decimation == DIT {
@@ -58,28 +70,23 @@ func (domain *Domain) FFT(a []fr.Element, decimation Decimation, coset uint64) {
 			for i := start; i < end; i++ {
 				a[i].Mul(&a[i], &domain.CosetTable[coset-1][i])
 			}
-		})
+		}, s.nbTasks)
 		// put it back as we found it
 		if decimation == DIT {
 			BitReverse(domain.CosetTable[coset-1])
 		}
 	}
 
-	// find the stage where we should stop spawning go routines in our recursive calls
-	// (ie when we have as many go routines running as we have available CPUs)
-	maxSplits := bits.TrailingZeros64(nextPowerOfTwo(numCPU))
-	if numCPU <= 1 {
-		maxSplits = -1
-	}
-
 	switch decimation {
 	case DIF:
-		difFFT(a, domain.Twiddles, 0, maxSplits, nil)
+		difFFT(a, domain.Twiddles, 0, s, nil)
 	case DIT:
-		ditFFT(a, domain.Twiddles, 0, maxSplits, nil)
+		ditFFT(a, domain.Twiddles, 0, s, nil)
 	default:
 		panic("not implemented")
 	}
+
+	return ctx.Err()
 }
 
 // FFTInverse computes (recursively) the inverse discrete Fourier transform of a and stores the result in a
@@ -88,23 +95,31 @@ func (domain *Domain) FFT(a []fr.Element, decimation Decimation, coset uint64) {
 // coset sets the shift of the fft (0 = no shift, standard fft)
 // len(a) must be a power of 2, and w must be a len(a)th root of unity in field F.
 func (domain *Domain) FFTInverse(a []fr.Element, decimation Decimation, coset uint64) {
+	_ = domain.FFTInverseContext(context.Background(), a, decimation, coset, ecc.FFTConfig{})
+}
 
-	numCPU := uint64(runtime.NumCPU())
-
-	// find the stage where we should stop spawning go routines in our recursive calls
-	// (ie when we have as many go routines running as we have available CPUs)
-	maxSplits := bits.TrailingZeros64(nextPowerOfTwo(numCPU))
-	if numCPU <= 1 {
-		maxSplits = -1
+// FFTInverseContext computes the inverse discrete Fourier transform of a, as FFTInverse does,
+// with at most config.NbTasks go routines.
+//
+// If ctx is done before the end of the computation, the go routines stop early and
+// FFTInverseContext returns ctx.Err(), leaving a in an undefined state.
+func (domain *Domain) FFTInverseContext(ctx context.Context, a []fr.Element, decimation Decimation, coset uint64, config ecc.FFTConfig) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
+	s := newFFTScheduler(ctx, config)
+
 	switch decimation {
 	case DIF:
-		difFFT(a, domain.TwiddlesInv, 0, maxSplits, nil)
+		difFFT(a, domain.TwiddlesInv, 0, s, nil)
 	case DIT:
-		ditFFT(a, domain.TwiddlesInv, 0, maxSplits, nil)
+		ditFFT(a, domain.TwiddlesInv, 0, s, nil)
 	default:
 		panic("not implemented")
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// scale by CardinalityInv (+ cosetTableInv is coset!=0)
 	if coset != 0 {
@@ -116,7 +131,7 @@ func (domain *Domain) FFTInverse(a []fr.Element, decimation Decimation, coset ui
 				a[i].Mul(&a[i], &domain.CosetTableInv[coset-1][i]).
 					MulAssign(&domain.CardinalityInv)
 			}
-		})
+		}, s.nbTasks)
 		// put it back as we found it
 		if decimation == DIF {
 			BitReverse(domain.CosetTableInv[coset-1])
@@ -126,11 +141,48 @@ func (domain *Domain) FFTInverse(a []fr.Element, decimation Decimation, coset ui
 			for i := start; i < end; i++ {
 				a[i].MulAssign(&domain.CardinalityInv)
 			}
-		})
+		}, s.nbTasks)
+	}
+
+	return nil
+}
+
+// fftScheduler is the scheduling state shared by the recursive calls of a FFT
+type fftScheduler struct {
+	nbTasks   int             // number of go routines
+	maxSplits int             // stage where the recursive calls stop spawning go routines
+	done      <-chan struct{} // closed when the FFT is cancelled (nil if it can't be)
+}
+
+func newFFTScheduler(ctx context.Context, config ecc.FFTConfig) *fftScheduler {
+	s := &fftScheduler{
+		nbTasks: config.NbTasks,
+		done:    ctx.Done(),
+	}
+	if s.nbTasks <= 0 {
+		s.nbTasks = runtime.NumCPU()
+	}
+
+	// find the stage where we should stop spawning go routines in our recursive calls
+	// (ie when we have as many go routines running as we have available CPUs)
+	s.maxSplits = bits.TrailingZeros64(nextPowerOfTwo(uint64(s.nbTasks)))
+	if s.nbTasks <= 1 {
+		s.maxSplits = -1
 	}
+	return s
 }
 
-func difFFT(a []fr.Element, twiddles [][]fr.Element, stage, maxSplits int, chDone chan struct{}) {
+// cancelled returns true if the FFT is cancelled, without blocking
+func (s *fftScheduler) cancelled() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func difFFT(a []fr.Element, twiddles [][]fr.Element, stage int, s *fftScheduler, chDone chan struct{}) {
 	if chDone != nil {
 		defer func() {
 			chDone <- struct{}{}
@@ -140,13 +192,16 @@ func difFFT(a []fr.Element, twiddles [][]fr.Element, stage, maxSplits int, chDon
 	if n == 1 {
 		return
 	}
+	if n > butterflyThreshold && s.cancelled() {
+		return
+	}
 	m := n >> 1
 
 	// if stage < maxSplits, we parallelize this butterfly
 	// but we have only numCPU / stage cpus available
-	if (m > butterflyThreshold) && (stage < maxSplits) {
+	if (m > butterflyThreshold) && (stage < s.maxSplits) {
 		// 1 << stage == estimated used CPUs
-		numCPU := runtime.NumCPU() / (1 << (stage))
+		numCPU := s.nbTasks / (1 << (stage))
 		parallel.Execute(m, func(start, end int) {
 			var t fr.Element
 			for i := start; i < end; i++ {
@@ -181,18 +236,18 @@ func difFFT(a []fr.Element, twiddles [][]fr.Element, stage, maxSplits int, chDon
 	}
 
 	nextStage := stage + 1
-	if stage < maxSplits {
+	if stage < s.maxSplits {
 		chDone := make(chan struct{}, 1)
-		go difFFT(a[m:n], twiddles, nextStage, maxSplits, chDone)
-		difFFT(a[0:m], twiddles, nextStage, maxSplits, nil)
+		go difFFT(a[m:n], twiddles, nextStage, s, chDone)
+		difFFT(a[0:m], twiddles, nextStage, s, nil)
 		<-chDone
 	} else {
-		difFFT(a[0:m], twiddles, nextStage, maxSplits, nil)
-		difFFT(a[m:n], twiddles, nextStage, maxSplits, nil)
+		difFFT(a[0:m], twiddles, nextStage, s, nil)
+		difFFT(a[m:n], twiddles, nextStage, s, nil)
 	}
 }
 
-func ditFFT(a []fr.Element, twiddles [][]fr.Element, stage, maxSplits int, chDone chan struct{}) {
+func ditFFT(a []fr.Element, twiddles [][]fr.Element, stage int, s *fftScheduler, chDone chan struct{}) {
 	if chDone != nil {
 		defer func() {
 			chDone <- struct{}{}
@@ -202,27 +257,30 @@ func ditFFT(a []fr.Element, twiddles [][]fr.Element, stage, maxSplits int, chDon
 	if n == 1 {
 		return
 	}
+	if n > butterflyThreshold && s.cancelled() {
+		return
+	}
 	m := n >> 1
 
 	nextStage := stage + 1
 
-	if stage < maxSplits {
+	if stage < s.maxSplits {
 		// that's the only time we fire go routines
 		chDone := make(chan struct{}, 1)
-		go ditFFT(a[m:], twiddles, nextStage, maxSplits, chDone)
-		ditFFT(a[0:m], twiddles, nextStage, maxSplits, nil)
+		go ditFFT(a[m:], twiddles, nextStage, s, chDone)
+		ditFFT(a[0:m], twiddles, nextStage, s, nil)
 		<-chDone
 	} else {
-		ditFFT(a[0:m], twiddles, nextStage, maxSplits, nil)
-		ditFFT(a[m:n], twiddles, nextStage, maxSplits, nil)
+		ditFFT(a[0:m], twiddles, nextStage, s, nil)
+		ditFFT(a[m:n], twiddles, nextStage, s, nil)
 
 	}
 
 	// if stage < maxSplits, we parallelize this butterfly
 	// but we have only numCPU / stage cpus available
-	if (m > butterflyThreshold) && (stage < maxSplits) {
+	if (m > butterflyThreshold) && (stage < s.maxSplits) {
 		// 1 << stage == estimated used CPUs
-		numCPU := runtime.NumCPU() / (1 << (stage))
+		numCPU := s.nbTasks / (1 << (stage))
 		parallel.Execute(m, func(start, end int) {
 			var t, tm fr.Element
 			for k := start; k < end; k++ {
@@ -0,0 +1,498 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package kzg provides a KZG commitment scheme.
+package kzg
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/polynomial"
+)
+
+var (
+	ErrInvalidNbDigests              = errors.New("number of digests is not the same as the number of polynomials")
+	ErrInvalidPolynomialSize         = errors.New("invalid polynomial size (larger than SRS or == 0)")
+	ErrVerifyOpeningProof            = errors.New("can't verify opening proof")
+	ErrVerifyBatchOpeningSinglePoint = errors.New("can't verify batch opening proof at single point")
+	ErrMinSRSSize                    = errors.New("minimum srs size is 2")
+)
+
+// Digest commitment of a polynomial.
+type Digest = bn254.G1Affine
+
+// SRS stores the result of the MPC
+type SRS struct {
+	G1 []bn254.G1Affine  // [gen [alpha]gen , [alpha**2]gen, ... ]
+	G2 [2]bn254.G2Affine // [gen, [alpha]gen ]
+}
+
+// NewSRS returns a new SRS using alpha as randomness source
+//
+// In production, a SRS generated through MPC should be used.
+//
+// implements io.ReaderFrom and io.WriterTo
+func NewSRS(size uint64, bAlpha *big.Int) (*SRS, error) {
+	if size < 2 {
+		return nil, ErrMinSRSSize
+	}
+	var srs SRS
+	srs.G1 = make([]bn254.G1Affine, size)
+
+	var alpha fr.Element
+	alpha.SetBigInt(bAlpha)
+
+	_, _, gen1Aff, gen2Aff := bn254.Generators()
+	srs.G1[0] = gen1Aff
+	srs.G2[0] = gen2Aff
+	srs.G2[1].ScalarMultiplication(&gen2Aff, bAlpha)
+
+	alphas := make([]fr.Element, size-1)
+	alphas[0] = alpha
+	for i := 1; i < len(alphas); i++ {
+		alphas[i].Mul(&alphas[i-1], &alpha)
+	}
+	for i := 0; i < len(alphas); i++ {
+		alphas[i].FromMont()
+	}
+	g1s := bn254.BatchScalarMultiplicationG1(&gen1Aff, alphas)
+	copy(srs.G1[1:], g1s)
+
+	return &srs, nil
+}
+
+// OpeningProof KZG proof for opening at a single point.
+//
+// implements io.ReaderFrom and io.WriterTo
+type OpeningProof struct {
+	// H quotient polynomial (f - f(z))/(x-z)
+	H bn254.G1Affine
+
+	// Point at which the polynomial is evaluated
+	Point fr.Element
+
+	// ClaimedValue purported value
+	ClaimedValue fr.Element
+}
+
+// BatchOpeningProof opening proof for many polynomials at the same point
+//
+// implements io.ReaderFrom and io.WriterTo
+type BatchOpeningProof struct {
+	// H quotient polynomial Sum_i gamma**i*(f - f(z))/(x-z)
+	H bn254.G1Affine
+
+	// Point at which the polynomials are evaluated
+	Point fr.Element
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+}
+
+// Commit commits to a polynomial using a multi exponentiation with the SRS.
+// It is assumed that the polynomial is in canonical form, in Montgomery form.
+func Commit(p polynomial.Polynomial, srs *SRS, opts ...*bn254.CPUSemaphore) (Digest, error) {
+
+	if len(p) == 0 || len(p) > len(srs.G1) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+
+	var res bn254.G1Affine
+	res.MultiExp(srs.G1[:len(p)], toRegular(p), opts...)
+
+	return res, nil
+}
+
+// Open computes an opening proof of polynomial p at given point.
+func Open(p polynomial.Polynomial, point *fr.Element, srs *SRS) (OpeningProof, error) {
+
+	if len(p) == 0 || len(p) > len(srs.G1) {
+		return OpeningProof{}, ErrInvalidPolynomialSize
+	}
+
+	// build the proof
+	res := OpeningProof{
+		Point:        *point,
+		ClaimedValue: *p.Eval(point).(*fr.Element),
+	}
+
+	// compute H
+	_p := make(polynomial.Polynomial, len(p))
+	copy(_p, p)
+	h := dividePolyByXminusA(_p, res.ClaimedValue, res.Point)
+
+	// commit to H
+	var err error
+	res.H, err = commitQuotient(h, srs)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	return res, nil
+}
+
+// Verify verifies a KZG opening proof at a single point
+func Verify(commitment *Digest, proof *OpeningProof, srs *SRS) error {
+
+	// comm(f(a))
+	var claimedValueG1Aff bn254.G1Affine
+	var claimedValueBigInt big.Int
+	proof.ClaimedValue.ToBigIntRegular(&claimedValueBigInt)
+	claimedValueG1Aff.ScalarMultiplication(&srs.G1[0], &claimedValueBigInt)
+
+	// [f(alpha) - f(a)]G1Jac
+	var fminusfaG1Jac, tmpG1Jac bn254.G1Jac
+	fminusfaG1Jac.FromAffine(commitment)
+	tmpG1Jac.FromAffine(&claimedValueG1Aff)
+	fminusfaG1Jac.SubAssign(&tmpG1Jac)
+
+	// [-H(alpha)]G1Aff
+	var negH bn254.G1Affine
+	negH.Neg(&proof.H)
+
+	// [alpha-a]G2Jac
+	var alphaMinusaG2Jac, genG2Jac, alphaG2Jac bn254.G2Jac
+	var pointBigInt big.Int
+	proof.Point.ToBigIntRegular(&pointBigInt)
+	genG2Jac.FromAffine(&srs.G2[0])
+	alphaG2Jac.FromAffine(&srs.G2[1])
+	alphaMinusaG2Jac.ScalarMultiplication(&genG2Jac, &pointBigInt).
+		Neg(&alphaMinusaG2Jac).
+		AddAssign(&alphaG2Jac)
+
+	// [alpha-a]G2Aff
+	var xminusaG2Aff bn254.G2Affine
+	xminusaG2Aff.FromJacobian(&alphaMinusaG2Jac)
+
+	// [f(alpha) - f(a)]G1Aff
+	var fminusfaG1Aff bn254.G1Affine
+	fminusfaG1Aff.FromJacobian(&fminusfaG1Jac)
+
+	// e([f(alpha) - f(a)]G1Aff, G2gen).e([-H(alpha)]G1Aff, [alpha-a]G2Aff) ==? 1
+	check, err := bn254.PairingCheck(
+		[]bn254.G1Affine{fminusfaG1Aff, negH},
+		[]bn254.G2Affine{srs.G2[0], xminusaG2Aff},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// BatchOpenSinglePoint creates a batch opening proof at point of a list of polynomials.
+// It's an interactive protocol, made non interactive using Fiat Shamir.
+// point is the point at which the polynomials are opened.
+// digests is the list of committed polynomials to open, need to derive the challenge using Fiat Shamir.
+// polynomials is the list of polynomials to open.
+func BatchOpenSinglePoint(polynomials []polynomial.Polynomial, digests []Digest, point *fr.Element, hf hash.Hash, srs *SRS) (BatchOpeningProof, error) {
+
+	// check for invalid sizes
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(polynomials) {
+		return BatchOpeningProof{}, ErrInvalidNbDigests
+	}
+	largestPoly := -1
+	for _, p := range polynomials {
+		if len(p) == 0 || len(p) > len(srs.G1) {
+			return BatchOpeningProof{}, ErrInvalidPolynomialSize
+		}
+		if len(p) > largestPoly {
+			largestPoly = len(p)
+		}
+	}
+
+	var res BatchOpeningProof
+
+	// compute the purported values
+	res.ClaimedValues = make([]fr.Element, len(polynomials))
+	for i := 0; i < len(polynomials); i++ {
+		res.ClaimedValues[i] = *polynomials[i].Eval(point).(*fr.Element)
+	}
+
+	// set the point at which the evaluation is done
+	res.Point = *point
+
+	// derive the challenge gamma, binded to the point and the commitments
+	gamma, err := deriveGamma(point, digests, res.ClaimedValues, hf)
+	if err != nil {
+		return BatchOpeningProof{}, err
+	}
+
+	// compute sum_i gamma**i*f(a)
+	var sumGammaiTimesEval fr.Element
+	gammai := make([]fr.Element, nbDigests)
+	gammai[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+	for i := 0; i < nbDigests; i++ {
+		var tmp fr.Element
+		tmp.Mul(&res.ClaimedValues[i], &gammai[i])
+		sumGammaiTimesEval.Add(&sumGammaiTimesEval, &tmp)
+	}
+
+	// compute sum_i gamma**i*f
+	sumGammaiTimesPol := make(polynomial.Polynomial, largestPoly)
+	for i := 0; i < nbDigests; i++ {
+		for j := 0; j < len(polynomials[i]); j++ {
+			var tmp fr.Element
+			tmp.Mul(&polynomials[i][j], &gammai[i])
+			sumGammaiTimesPol[j].Add(&sumGammaiTimesPol[j], &tmp)
+		}
+	}
+
+	// compute H
+	h := dividePolyByXminusA(sumGammaiTimesPol, sumGammaiTimesEval, res.Point)
+	res.H, err = commitQuotient(h, srs)
+	if err != nil {
+		return BatchOpeningProof{}, err
+	}
+
+	return res, nil
+}
+
+// BatchVerifySinglePoint verifies a batched opening proof at a single point of a list of polynomials.
+//
+// digests list of digests on which batchOpeningProof is based
+// batchOpeningProof opening proof of digests
+func BatchVerifySinglePoint(digests []Digest, batchOpeningProof *BatchOpeningProof, hf hash.Hash, srs *SRS) error {
+
+	// check consistancy between numbers of claims vs number of digests
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+
+	// derive the challenge gamma, binded to the point and the commitments
+	gamma, err := deriveGamma(&batchOpeningProof.Point, digests, batchOpeningProof.ClaimedValues, hf)
+	if err != nil {
+		return err
+	}
+
+	// fold the claimed values and digests
+	gammai := make([]fr.Element, nbDigests)
+	gammai[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+	var foldedEval fr.Element
+	for i := 0; i < nbDigests; i++ {
+		var tmp fr.Element
+		tmp.Mul(&batchOpeningProof.ClaimedValues[i], &gammai[i])
+		foldedEval.Add(&foldedEval, &tmp)
+	}
+
+	var foldedDigest bn254.G1Affine
+	foldedDigest.MultiExp(digests, toRegular(gammai))
+
+	// create the folded opening proof
+	var foldedProof OpeningProof
+	foldedProof.H.Set(&batchOpeningProof.H)
+	foldedProof.Point.Set(&batchOpeningProof.Point)
+	foldedProof.ClaimedValue.Set(&foldedEval)
+
+	// verify the folded proof
+	if err := Verify(&foldedDigest, &foldedProof, srs); err != nil {
+		return ErrVerifyBatchOpeningSinglePoint
+	}
+
+	return nil
+}
+
+// BatchVerifyMultiPoints batch verifies a list of opening proofs at different points.
+// The purpose of the batching is to have only one pairing for verifying several proofs.
+//
+// digests list of committed polynomials which are opened
+// proofs list of opening proofs of the digest
+func BatchVerifyMultiPoints(digests []Digest, proofs []OpeningProof, srs *SRS) error {
+
+	// check consistancy nb proofs vs nb digests
+	if len(digests) == 0 || len(digests) != len(proofs) {
+		return ErrInvalidNbDigests
+	}
+
+	// if only one digest, call Verify
+	if len(digests) == 1 {
+		return Verify(&digests[0], &proofs[0], srs)
+	}
+
+	// sample random numbers for sampling
+	randomNumbers := make([]fr.Element, len(digests))
+	randomNumbers[0].SetOne()
+	for i := 1; i < len(randomNumbers); i++ {
+		if _, err := randomNumbers[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+
+	// combine random_i*quotient_i
+	var foldedQuotients bn254.G1Affine
+	quotients := make([]bn254.G1Affine, len(proofs))
+	for i := 0; i < len(randomNumbers); i++ {
+		quotients[i].Set(&proofs[i].H)
+	}
+	foldedQuotients.MultiExp(quotients, toRegular(randomNumbers))
+
+	// fold digests and evals
+	evals := make([]fr.Element, len(digests))
+	for i := 0; i < len(randomNumbers); i++ {
+		evals[i].Set(&proofs[i].ClaimedValue)
+	}
+	foldedDigests, foldedEvals, err := fold(digests, evals, randomNumbers)
+	if err != nil {
+		return err
+	}
+
+	// compute commitment to folded Eval
+	var foldedEvalsCommit bn254.G1Jac
+	var foldedEvalsBigInt big.Int
+	foldedEvals.ToBigIntRegular(&foldedEvalsBigInt)
+	foldedEvalsCommit.FromAffine(&srs.G1[0])
+	foldedEvalsCommit.ScalarMultiplication(&foldedEvalsCommit, &foldedEvalsBigInt)
+
+	// compute F = foldedDigests - foldedEvalsCommit
+	var lhs bn254.G1Jac
+	lhs.FromAffine(&foldedDigests)
+	lhs.SubAssign(&foldedEvalsCommit)
+
+	// combine random_i*(point_i*quotient_i)
+	var foldedPointsQuotients bn254.G1Jac
+	for i := 0; i < len(randomNumbers); i++ {
+		randomNumbers[i].Mul(&randomNumbers[i], &proofs[i].Point)
+	}
+	foldedPointsQuotients.MultiExp(quotients, toRegular(randomNumbers))
+
+	// lhs first pairing
+	lhs.AddAssign(&foldedPointsQuotients)
+	var lhsAff bn254.G1Affine
+	lhsAff.FromJacobian(&lhs)
+
+	// lhs second pairing
+	foldedQuotients.Neg(&foldedQuotients)
+
+	// pairing check
+	check, err := bn254.PairingCheck(
+		[]bn254.G1Affine{lhsAff, foldedQuotients},
+		[]bn254.G2Affine{srs.G2[0], srs.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// fold folds digests and evaluations using the list of factors as random numbers.
+//
+// * digests list of digests to fold
+// * evaluations list of evaluations to fold
+// * factors list of multiplicative factors used for the folding (in Montgomery form)
+func fold(digests []Digest, evaluations []fr.Element, factors []fr.Element) (Digest, fr.Element, error) {
+
+	// length inconsistancy between digests and evaluations should have been done before calling this function
+	nbDigests := len(digests)
+
+	// fold the claimed values
+	var foldedEvaluations, tmp fr.Element
+	for i := 0; i < nbDigests; i++ {
+		tmp.Mul(&evaluations[i], &factors[i])
+		foldedEvaluations.Add(&foldedEvaluations, &tmp)
+	}
+
+	// fold the digests
+	var foldedDigests Digest
+	foldedDigests.MultiExp(digests, toRegular(factors))
+
+	// folding done
+	return foldedDigests, foldedEvaluations, nil
+}
+
+// deriveGamma derives a challenge using Fiat Shamir to fold polynomials.
+func deriveGamma(point *fr.Element, digests []Digest, claimedValues []fr.Element, hf hash.Hash) (fr.Element, error) {
+
+	hf.Reset()
+
+	bPoint := point.Bytes()
+	if _, err := hf.Write(bPoint[:]); err != nil {
+		return fr.Element{}, err
+	}
+	for i := 0; i < len(digests); i++ {
+		bDigest := digests[i].Bytes()
+		if _, err := hf.Write(bDigest[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := 0; i < len(claimedValues); i++ {
+		bValue := claimedValues[i].Bytes()
+		if _, err := hf.Write(bValue[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	var gamma fr.Element
+	gamma.SetBytes(hf.Sum(nil))
+	hf.Reset()
+
+	return gamma, nil
+}
+
+// commitQuotient commits to the quotient h. If the opened polynomials are constant,
+// h is empty and its commitment is the point at infinity.
+func commitQuotient(h polynomial.Polynomial, srs *SRS) (Digest, error) {
+	if len(h) == 0 {
+		return Digest{}, nil
+	}
+	return Commit(h, srs)
+}
+
+// toRegular returns a copy of s, converted from Montgomery form to regular form,
+// as expected by MultiExp
+func toRegular(s []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(s))
+	copy(res, s)
+	for i := 0; i < len(res); i++ {
+		res[i].FromMont()
+	}
+	return res
+}
+
+// dividePolyByXminusA computes (f-f(a))/(x-a), in canonical basis, in regular form
+// f memory is re-used for the result
+func dividePolyByXminusA(f polynomial.Polynomial, fa, a fr.Element) polynomial.Polynomial {
+
+	// first we compute f-f(a)
+	f[0].Sub(&f[0], &fa)
+
+	// now we use syntetic division to divide by x-a
+	var t fr.Element
+	for i := len(f) - 2; i >= 0; i-- {
+		t.Mul(&f[i+1], &a)
+
+		f[i].Add(&f[i], &t)
+	}
+
+	// the result is of degree deg(f)-1
+	return f[1:]
+}
@@ -0,0 +1,181 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package poseidon provides the Poseidon hash function and permutation, cf https://eprint.iacr.org/2019/458.pdf
+package poseidon
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// ErrInvalidStateSize the state given to the permutation doesn't match the width of the parameters
+var ErrInvalidStateSize = errors.New("invalid state size: must be equal to the width t")
+
+// BlockSize size that poseidon consumes
+const BlockSize = fr.Bytes
+
+// Permutation applies the Poseidon permutation to state, in place.
+// len(state) must be p.T.
+func (p *Parameters) Permutation(state []fr.Element) error {
+	if len(state) != p.T {
+		return ErrInvalidStateSize
+	}
+	p.permutation(state, make([]fr.Element, p.T))
+	return nil
+}
+
+// permutation applies the Poseidon permutation to state, tmp is a buffer of size p.T
+func (p *Parameters) permutation(state, tmp []fr.Element) {
+	rf := p.NbFullRounds / 2
+	for r := 0; r < p.NbFullRounds+p.NbPartialRounds; r++ {
+
+		// add round constants
+		for i := range state {
+			state[i].Add(&state[i], &p.RoundConstants[r][i])
+		}
+
+		// s-box layer, on the whole state in full rounds and on the first element in partial rounds
+		if r < rf || r >= rf+p.NbPartialRounds {
+			for i := range state {
+				sBox(&state[i])
+			}
+		} else {
+			sBox(&state[0])
+		}
+
+		// linear layer
+		for i := range tmp {
+			var t fr.Element
+			tmp[i].SetZero()
+			for j := range state {
+				t.Mul(&p.MDS[i][j], &state[j])
+				tmp[i].Add(&tmp[i], &t)
+			}
+		}
+		copy(state, tmp)
+	}
+}
+
+// sBox sets x to x**5
+func sBox(x *fr.Element) {
+	var x4 fr.Element
+	x4.Square(x).Square(&x4)
+	x.Mul(x, &x4)
+}
+
+// Hash hashes inputs using a sponge of rate T-1 and capacity 1, the capacity
+// being the first element of the state, and returns the first element of the final state.
+// The last chunk of inputs is padded with zeros.
+//
+// When len(inputs) = T-1, this is the Poseidon hash of circomlib, with a zero capacity.
+// Otherwise, the capacity is set to the length tag 2^64 + len(inputs), for the domain separation
+// of variable length inputs (cf https://eprint.iacr.org/2019/458.pdf, section 4.2), such that
+// inputs of different lengths are not mixed up by the zero padding.
+func (p *Parameters) Hash(inputs ...fr.Element) fr.Element {
+	state := make([]fr.Element, p.T)
+	tmp := make([]fr.Element, p.T)
+	rate := p.T - 1
+
+	if len(inputs) != rate {
+		var tag fr.Element
+		tag.SetUint64(uint64(len(inputs)))
+		state[0].SetUint64(1<<63).Double(&state[0]).Add(&state[0], &tag)
+	}
+
+	for i := 0; i < len(inputs) || i == 0; i += rate {
+		for j := 0; j < rate && i+j < len(inputs); j++ {
+			state[j+1].Add(&state[j+1], &inputs[i+j])
+		}
+		p.permutation(state, tmp)
+	}
+
+	return state[0]
+}
+
+// digest represents the data to hash along with the parameters of the permutation
+type digest struct {
+	params *Parameters
+	data   []byte // data to hash
+}
+
+// NewPoseidon returns a Poseidon hash function with the given parameters.
+// The data written is split in big endian chunks of BlockSize bytes (the last one
+// being left-padded with zeros), each being interpreted as a field element, which
+// are then hashed with Parameters.Hash, followed by the length in bytes of the data.
+func NewPoseidon(params *Parameters) hash.Hash {
+	d := new(digest)
+	d.params = params
+	d.Reset()
+	return d
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = nil
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	h := d.params.Hash(d.elements()...)
+	hash := h.Bytes()
+	b = append(b, hash[:]...)
+	return b
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return BlockSize
+}
+
+// BlockSize returns the hash's underlying block size.
+// The Write method must be able to accept any amount
+// of data, but it may operate more efficiently if all writes
+// are a multiple of the block size.
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+// It never returns an error.
+func (d *digest) Write(p []byte) (n int, err error) {
+	n = len(p)
+	d.data = append(d.data, p...)
+	return
+}
+
+// elements splits the data in chunks of BlockSize bytes and converts them to field elements,
+// followed by the length in bytes of the data (as the left padding of the last chunk hides it)
+func (d *digest) elements() []fr.Element {
+	nbChunks := (len(d.data) + BlockSize - 1) / BlockSize
+	res := make([]fr.Element, nbChunks+1)
+	res[nbChunks].SetUint64(uint64(len(d.data)))
+
+	for i := 0; i < nbChunks; i++ {
+		var buffer [BlockSize]byte
+		chunk := d.data[i*BlockSize:]
+		if len(chunk) > BlockSize {
+			chunk = chunk[:BlockSize]
+		}
+		copy(buffer[BlockSize-len(chunk):], chunk)
+		res[i].SetBytes(buffer[:])
+	}
+
+	return res
+}
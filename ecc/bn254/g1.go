@@ -17,7 +17,10 @@
 package bn254
 
 import (
+	"encoding/binary"
 	"math/big"
+	"math/bits"
+	"runtime"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
@@ -58,6 +61,18 @@ func (p *G1Affine) ScalarMultiplication(a *G1Affine, s *big.Int) *G1Affine {
 	return p
 }
 
+// ScalarMultiplicationSecret computes and returns p = a*s, in constant time with respect to s.
+// It must be used instead of ScalarMultiplication when s is secret (for example, a private key),
+// as the running time of ScalarMultiplication depends on the bits of s.
+// a must be in the prime order subgroup.
+func (p *G1Affine) ScalarMultiplicationSecret(a *G1Affine, s *big.Int) *G1Affine {
+	var _p G1Jac
+	_p.FromAffine(a)
+	_p.mulLadder(&_p, s)
+	p.fromJacobianSecret(&_p)
+	return p
+}
+
 // Equal tests if two points (in Affine coordinates) are equal
 func (p *G1Affine) Equal(a *G1Affine) bool {
 	return p.X.Equal(&a.X) && p.Y.Equal(&a.Y)
@@ -349,6 +364,101 @@ func (p *G1Jac) IsInSubGroup() bool {
 
 }
 
+// ScalarMultiplicationSecret computes and returns p = a*s, in constant time with respect to s.
+// It must be used instead of ScalarMultiplication when s is secret (for example, a private key),
+// as the running time of ScalarMultiplication depends on the bits of s.
+// a must be in the prime order subgroup.
+func (p *G1Jac) ScalarMultiplicationSecret(a *G1Jac, s *big.Int) *G1Jac {
+	return p.mulLadder(a, s)
+}
+
+// mulLadder computes p = a*s with a Montgomery ladder, over the fixed number of bits
+// of fixedLengthScalar(s), with constant time conditional swaps.
+// As a is in the prime order subgroup and r0 - r1 = a, the additions
+// never hit the exceptional cases (r0 == r1 or r0, r1 at infinity) for non trivial scalars.
+func (p *G1Jac) mulLadder(a *G1Jac, s *big.Int) *G1Jac {
+	k := fixedLengthScalar(s)
+
+	// the bit fr.Bits of k is set
+	var r0, r1 G1Jac
+	r0.Set(a)
+	r1.Double(a)
+
+	// invariant: r1 = r0 + a
+	for i := fr.Bits - 1; i >= 0; i-- {
+		b := (k[i/64] >> (uint(i) % 64)) & 1
+		r0.cswap(&r1, b)
+		r1.AddAssign(&r0)
+		r0.DoubleAssign()
+		r0.cswap(&r1, b)
+	}
+	p.Set(&r0)
+
+	return p
+}
+
+// cswap swaps p and q if b == 1, and leaves them unchanged if b == 0, in constant time
+func (p *G1Jac) cswap(q *G1Jac, b uint64) {
+	mask := -b
+	pc := [...]*fp.Element{&p.X, &p.Y, &p.Z}
+	qc := [...]*fp.Element{&q.X, &q.Y, &q.Z}
+	for i := range pc {
+		for j := 0; j < fp.Limbs; j++ {
+			t := mask & (pc[i][j] ^ qc[i][j])
+			pc[i][j] ^= t
+			qc[i][j] ^= t
+		}
+	}
+}
+
+// fromJacobianSecret is FromJacobian, with a constant time inversion of p1.Z
+// (by Fermat's little theorem)
+func (p *G1Affine) fromJacobianSecret(p1 *G1Jac) *G1Affine {
+	var e big.Int
+	e.Sub(fp.Modulus(), big.NewInt(2))
+
+	var a, b fp.Element
+	a.Exp(p1.Z, &e)
+	b.Square(&a)
+	p.X.Mul(&p1.X, &b)
+	p.Y.Mul(&p1.Y, &b).Mul(&p.Y, &a)
+	return p
+}
+
+// fixedLengthScalar returns k + r or k + 2r, where k = s mod r, whichever has its bit fr.Bits set,
+// in little endian 64-bit words. Both are multiples of the same point when multiplied by
+// a point of the subgroup, but the resulting scalar has a fixed length, such that the
+// scalar multiplications by secret scalars process a fixed number of bits.
+func fixedLengthScalar(s *big.Int) [fr.Limbs + 1]uint64 {
+	var e fr.Element
+	e.SetBigInt(s).FromMont()
+
+	var buf [fr.Bytes]byte
+	fr.Modulus().FillBytes(buf[:])
+	var r, k1, k2 [fr.Limbs + 1]uint64
+	for i := 0; i < fr.Limbs; i++ {
+		r[i] = binary.BigEndian.Uint64(buf[fr.Bytes-8*(i+1):])
+	}
+
+	// k1 = k + r, k2 = k + 2r
+	var c1, c2 uint64
+	for i := 0; i < fr.Limbs+1; i++ {
+		var ki uint64
+		if i < fr.Limbs {
+			ki = e[i]
+		}
+		k1[i], c1 = bits.Add64(ki, r[i], c1)
+		k2[i], c2 = bits.Add64(k1[i], r[i], c2)
+	}
+
+	// select k1 if its bit fr.Bits is set, k2 otherwise
+	mask := -((k1[fr.Bits/64] >> (fr.Bits % 64)) & 1)
+	for i := 0; i < fr.Limbs+1; i++ {
+		k2[i] ^= mask & (k1[i] ^ k2[i])
+	}
+	return k2
+}
+
 // mulWindowed 2-bits windowed exponentiation
 func (p *G1Jac) mulWindowed(a *G1Jac, s *big.Int) *G1Jac {
 
@@ -758,10 +868,11 @@ func (p *g1JacExtended) doubleMixed(q *G1Affine) *g1JacExtended {
 // result must be allocated with len(result) == len(points)
 func BatchJacobianToAffineG1Affine(points []G1Jac, result []G1Affine) {
 	zeroes := make([]bool, len(points))
-	accumulator := fp.One()
+	var accumulator fp.Element
+	accumulator.SetOne()
 
 	// batch invert all points[].Z coordinates with Montgomery batch inversion trick
-	// (stores points[].Z^-1 in result[i].X to avoid allocating a slice of fr.Elements)
+	// (stores points[].Z^-1 in result[i].X to avoid allocating a slice of fp.Element)
 	for i := 0; i < len(points); i++ {
 		if points[i].Z.IsZero() {
 			zeroes[i] = true
@@ -843,7 +954,7 @@ func BatchScalarMultiplicationG1(base *G1Affine, scalars []fr.Element) []G1Affin
 		baseTable[i].AddMixed(base)
 	}
 
-	pScalars := partitionScalars(scalars, c)
+	pScalars := partitionScalars(scalars, c, runtime.NumCPU())
 
 	// compute offset and word selector / shift to select the right bits of our windows
 	selectors := make([]selector, nbChunks)
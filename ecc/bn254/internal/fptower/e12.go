@@ -60,6 +60,11 @@ func (z *E12) SetOne() *E12 {
 	return z
 }
 
+// IsZero returns true if z is zero, false otherwise
+func (z *E12) IsZero() bool {
+	return z.C0.IsZero() && z.C1.IsZero()
+}
+
 // ToMont converts to Mont form
 func (z *E12) ToMont() *E12 {
 	z.C0.ToMont()
@@ -353,3 +358,39 @@ func (z *E12) IsInSubGroup() bool {
 	_z.Exp(z, *frModulus)
 	return _z.Equal(&one)
 }
+
+// BatchInvertE12 returns a new slice with every element inverted.
+// Uses Montgomery batch inversion trick: a single E12 inversion is performed.
+// Zero elements are left unchanged (their inverse is set to 0).
+func BatchInvertE12(a []E12) []E12 {
+	res := make([]E12, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E12
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i] = accumulator
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	var accInverse E12
+	accInverse.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accInverse)
+		accInverse.Mul(&accInverse, &a[i])
+	}
+
+	return res
+}
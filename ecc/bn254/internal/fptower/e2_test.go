@@ -371,6 +371,26 @@ func TestE2Ops(t *testing.T) {
 	}
 }
 
+func TestE2BatchInvert(t *testing.T) {
+	const n = 10
+	a := make([]E2, n)
+	for i := 1; i < n; i++ {
+		a[i].SetRandom()
+	}
+
+	res := BatchInvertE2(a)
+	if !res[0].IsZero() {
+		t.Fatal("inverse of 0 should be 0")
+	}
+	for i := 1; i < n; i++ {
+		var expected E2
+		expected.Inverse(&a[i])
+		if !res[i].Equal(&expected) {
+			t.Fatal("BatchInvertE2 doesn't match Inverse")
+		}
+	}
+}
+
 // ------------------------------------------------------------
 // benches
 
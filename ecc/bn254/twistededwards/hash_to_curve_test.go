@@ -0,0 +1,207 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func TestElligator2Map(t *testing.T) {
+
+	if elligator2Z.Legendre() != -1 {
+		t.Fatal("Z should not be a square")
+	}
+
+	// the denominator 1 + Z*u^2 of x1 never vanishes, as -1/Z is not a square (-1 is a square in fr)
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne)
+	if minusOne.Legendre() != 1 {
+		t.Fatal("-1 should be a square")
+	}
+
+	// u = 0 is mapped with x1 = -A
+	var zero fr.Element
+	inputs := []fr.Element{zero}
+	for i := 0; i < 20; i++ {
+		var u fr.Element
+		u.SetRandom()
+		inputs = append(inputs, u)
+	}
+
+	for _, u := range inputs {
+		p := elligator2Map(u)
+		if !p.IsOnCurve() {
+			t.Fatalf("elligator2Map(%s) is not on the curve", u.String())
+		}
+
+		// u and -u are mapped to the same point, as only u^2 is used to select x
+		var v fr.Element
+		v.Neg(&u)
+		if q := elligator2Map(v); !q.Equal(&p) {
+			t.Fatal("elligator2Map(u) and elligator2Map(-u) should be equal")
+		}
+	}
+}
+
+func TestHashToCurve(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+	for i := 0; i < 10; i++ {
+		msg := []byte(fmt.Sprintf("msg %d", i))
+
+		for _, hashToCurve := range []func(msg, dst []byte) (PointAffine, error){HashToCurve, EncodeToCurve} {
+			p, err := hashToCurve(msg, dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !p.IsOnCurve() {
+				t.Fatal("hash to curve output should be on the curve")
+			}
+
+			// the output is in the prime order subgroup
+			var pProj, q PointProj
+			pProj.FromAffine(&p)
+			if scalarMulNaive(&q, &pProj, &ed.Order); !q.IsZero() || pProj.IsZero() {
+				t.Fatal("hash to curve output should be in the prime order subgroup")
+			}
+
+			// the output is deterministic, and depends on the message and on the domain separation tag
+			p2, _ := hashToCurve(msg, dst)
+			p3, _ := hashToCurve(append(msg, 0), dst)
+			p4, _ := hashToCurve(msg, []byte("another DST"))
+			if !p.Equal(&p2) || p.Equal(&p3) || p.Equal(&p4) {
+				t.Fatal("hash to curve output should depend only on the message and on the domain separation tag")
+			}
+		}
+	}
+
+	// EncodeToCurve(msg) = MapToCurve(u), with u = hash_to_field(msg)
+	u, err := hashToFr([]byte("abc"), dst, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MapToCurve(u[0])
+	p, _ := EncodeToCurve([]byte("abc"), dst)
+	if !p.Equal(&expected) {
+		t.Fatal("EncodeToCurve and MapToCurve mismatch")
+	}
+
+	// HashToCurve(msg) = MapToCurve(u0) + MapToCurve(u1), with u0, u1 = hash_to_field(msg)
+	if u, err = hashToFr([]byte("abc"), dst, 2); err != nil {
+		t.Fatal(err)
+	}
+	q0, q1 := MapToCurve(u[0]), MapToCurve(u[1])
+	expected.Add(&q0, &q1)
+	p, _ = HashToCurve([]byte("abc"), dst)
+	if !p.Equal(&expected) {
+		t.Fatal("HashToCurve and MapToCurve mismatch")
+	}
+}
+
+// TestHashToCurveVectors checks hash_to_field, the Elligator 2 map and HashToCurve against known answers,
+// computed with an independent implementation of the generic steps of RFC 9380 (expand_message_xmd
+// with SHA-256, hash_to_field, map_to_curve_elligator2 on the Montgomery curve, the rational map of
+// appendix D and clear_cofactor). q0 and q1 are the outputs of the map before the cofactor is cleared.
+func TestHashToCurveVectors(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+	vectors := []struct {
+		msg       string
+		u0, u1    string
+		q0, q1, p [2]string
+	}{
+		{
+			msg: "",
+			u0:  "5556109403625926857439623840608354364819279073507806446875918448742900497551",
+			u1:  "8499655854705244451710324689202475138935922967072518604530903558573286061408",
+			q0:  [2]string{"21815236105907448673559424231924100424818717122217163210146398552026611714966", "13068473788213305297647825873309248575518803608888273760642964658382510569839"},
+			q1:  [2]string{"19859392883818149413762914029387122644803298534178826335058268890772863942517", "21407576082026699132187496070733170960160729510125894433364016533932137869758"},
+			p:   [2]string{"18415640411351796856357084572196573412728035450431808834604776977463061402774", "3893931053794190745325610180441850810890118581749132312726007893181890331002"},
+		},
+		{
+			msg: "abc",
+			u0:  "6493452990718708610507303494664176125873759481681586982896258050226203202089",
+			u1:  "11011678606805196545823934851777372878536654214979659948057514307335742539032",
+			q0:  [2]string{"6164983906871689486935780997850024725678864580520720009938514444511816314580", "4340595391566142870932526681481877163531616457272353914401382295907247655592"},
+			q1:  [2]string{"5702519876045138872507734406120260421246733412733468204473069885143991203752", "19849715172838631680080677746076785605707454957639813137685518831250441549033"},
+			p:   [2]string{"17505594592844441019210238584564784680886457772763817410189134324153925940443", "13983907403987141078062673770407146174882999948475671423208617739766505292863"},
+		},
+		{
+			msg: "abcdef0123456789",
+			u0:  "857643645507727034957228035514007829396474330553185202997657487657787146657",
+			u1:  "15916016058384773829141430443473736531979953926133911205498100100250834509381",
+			q0:  [2]string{"11881799162248849529859539211062033307531200514271352773271192363825819844019", "9333241850717012771242620611107924546145185604393906002162745358658449126372"},
+			q1:  [2]string{"21627767088616764905116694076501725873027308862150165376085120266637322151803", "16166156002991513125213526608566176483303475629389982535362644919649166085843"},
+			p:   [2]string{"12782698689619553210007296694809957978764958873240702965521031373011372431772", "15891928711319915063515478027695620062322787675916345758015744164537623840013"},
+		},
+	}
+
+	for _, v := range vectors {
+		u, err := hashToFr([]byte(v.msg), dst, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var u0, u1 fr.Element
+		u0.SetString(v.u0)
+		u1.SetString(v.u1)
+		if !u[0].Equal(&u0) || !u[1].Equal(&u1) {
+			t.Fatalf("hashToFr(%q) mismatch", v.msg)
+		}
+
+		var q0, q1, expected PointAffine
+		q0.X.SetString(v.q0[0])
+		q0.Y.SetString(v.q0[1])
+		q1.X.SetString(v.q1[0])
+		q1.Y.SetString(v.q1[1])
+		if p := elligator2Map(u0); !p.Equal(&q0) {
+			t.Fatalf("elligator2Map(u0) mismatch for msg %q", v.msg)
+		}
+		if p := elligator2Map(u1); !p.Equal(&q1) {
+			t.Fatalf("elligator2Map(u1) mismatch for msg %q", v.msg)
+		}
+
+		p, err := HashToCurve([]byte(v.msg), dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected.X.SetString(v.p[0])
+		expected.Y.SetString(v.p[1])
+		if !p.Equal(&expected) {
+			t.Fatalf("HashToCurve(%q) mismatch", v.msg)
+		}
+	}
+}
+
+func BenchmarkHashToCurve(b *testing.B) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	msg := []byte("abc")
+	b.Run("EncodeToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncodeToCurve(msg, dst)
+		}
+	})
+	b.Run("HashToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			HashToCurve(msg, dst)
+		}
+	})
+}
@@ -0,0 +1,275 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// CPUSemaphore enables users to set optional number of CPUs the multiexp will use
+// this is thread safe and can be used accross parallel calls of MultiExp
+type CPUSemaphore struct {
+	chCpus chan struct{} // semaphore to limit number of cpus iterating through points and scalars at the same time
+	lock   sync.Mutex
+}
+
+// NewCPUSemaphore returns a new multiExp options to be used with MultiExp
+// this option can be shared between different MultiExp calls and will ensure only numCpus are used
+// through a semaphore
+func NewCPUSemaphore(numCpus int) *CPUSemaphore {
+	toReturn := &CPUSemaphore{
+		chCpus: make(chan struct{}, numCpus),
+	}
+	for i := 0; i < numCpus; i++ {
+		toReturn.chCpus <- struct{}{}
+	}
+	return toReturn
+}
+
+// MultiExp sets p to Σ scalars[i]*points[i] and returns it
+// it implements section 4 of https://eprint.iacr.org/2012/549.pdf
+// optionally, takes as parameter a CPUSemaphore struct
+// enabling to set max number of cpus to use
+func (p *PointAffine) MultiExp(points []PointAffine, scalars []big.Int, opts ...*CPUSemaphore) *PointAffine {
+	var _p PointProj
+	_p.MultiExp(points, scalars, opts...)
+	p.FromProj(&_p)
+	return p
+}
+
+// MultiExp sets p to Σ scalars[i]*points[i] and returns it
+// it implements section 4 of https://eprint.iacr.org/2012/549.pdf
+// optionally, takes as parameter a CPUSemaphore struct
+// enabling to set max number of cpus to use
+//
+// The scalars are reduced modulo the order of the curve (cofactor*order of the subgroup),
+// hence negative scalars are supported, and the points need not be in the prime order subgroup.
+// It panics if len(points) != len(scalars).
+func (p *PointProj) MultiExp(points []PointAffine, scalars []big.Int, opts ...*CPUSemaphore) *PointProj {
+	// note:
+	// step 1
+	// we compute, for each scalars over c-bit wide windows, nbChunk digits
+	// if the digit is larger than 2^{c-1}, then, we borrow 2^c from the next window and substract
+	// 2^{c} to the current digit, making it negative.
+	// negative digits will be processed in the next step as adding -G into the bucket instead of G
+	// (computing -G is cheap, and this saves us half of the buckets)
+	// step 2
+	// for each chunk, msmProcessChunk places points into buckets based on their digit
+	// and returns the weighted bucket sum in given channel
+	// step 3
+	// reduce the buckets weigthed sums into our result (msmReduceChunk)
+	//
+	// unlike the short Weierstrass MultiExp, c is not a constant and the buckets are
+	// allocated on the heap, as the twisted Edwards curves are not used for large MultiExp.
+	if len(points) != len(scalars) {
+		panic("number of points and scalars must match")
+	}
+
+	var opt *CPUSemaphore
+	if len(opts) > 0 {
+		opt = opts[0]
+	} else {
+		opt = NewCPUSemaphore(runtime.NumCPU())
+	}
+
+	var order big.Int
+	edwards.Cofactor.ToBigInt(&order)
+	order.Mul(&order, &edwards.Order)
+	nbBits := order.BitLen()
+
+	// approximate cost (in group operations)
+	// cost = bits/c * (nbPoints + 2^{c})
+	var c int
+	min := math.MaxFloat64
+	for i := 2; i <= maxC; i++ {
+		cost := float64(nbBits*(len(points)+(1<<i))) / float64(i)
+		if cost < min {
+			min = cost
+			c = i
+		}
+	}
+
+	// the last chunk absorbs the carry of the signed digits decomposition
+	nbChunks := (nbBits+1)/c + 1
+
+	// take all the cpus to ourselves
+	opt.lock.Lock()
+
+	digits := partitionScalars(scalars, &order, c, nbChunks)
+
+	chChunks := make([]chan PointProj, nbChunks)
+
+	// wait group to wait for all the go routines to start
+	var wg sync.WaitGroup
+	for chunk := nbChunks - 1; chunk >= 0; chunk-- {
+		chChunks[chunk] = make(chan PointProj, 1)
+		<-opt.chCpus // wait to have a cpu before scheduling
+		wg.Add(1)
+		go func(chRes chan PointProj, digits []uint16) {
+			wg.Done()
+			buckets := make([]PointProj, 1<<(c-1))
+			msmProcessChunk(chRes, buckets, c, points, digits)
+			opt.chCpus <- struct{}{} // release token in the semaphore
+		}(chChunks[chunk], digits[chunk*len(points):(chunk+1)*len(points)])
+	}
+
+	// wait for all goRoutines to actually start
+	wg.Wait()
+
+	// all my tasks are scheduled, I can let other func use avaiable tokens in the semaphore
+	opt.lock.Unlock()
+	return msmReduceChunk(p, c, chChunks)
+}
+
+// maxC is the largest window size of the MultiExp, such that the signed digits fit in an uint16
+const maxC = 16
+
+// partitionScalars reduces the scalars modulo order and computes their signed c-bit digits
+// the digits of the i-th scalar for the chunk j are stored in digits[j*len(scalars)+i]
+// a digit 0 < d < 2^{c-1} is stored as d, and a digit -2^{c-1} <= d < 0 is stored as (-d-1) | 2^{c-1}
+func partitionScalars(scalars []big.Int, order *big.Int, c, nbChunks int) []uint16 {
+	digits := make([]uint16, nbChunks*len(scalars))
+
+	mask := uint64((1 << c) - 1)      // low c bits are 1
+	msbWindow := uint16(1 << (c - 1)) // msb of the c-bit window
+	max := 1 << (c - 1)               // max value we want for our digits
+
+	parallel.Execute(len(scalars), func(start, end int) {
+		var s big.Int
+		var buf [fr.Limbs * 8]byte
+		var k [fr.Limbs]uint64
+		for i := start; i < end; i++ {
+			if scalars[i].Sign() < 0 || scalars[i].Cmp(order) >= 0 {
+				s.Mod(&scalars[i], order).FillBytes(buf[:])
+			} else {
+				scalars[i].FillBytes(buf[:])
+			}
+			for j := 0; j < fr.Limbs; j++ {
+				k[j] = binary.BigEndian.Uint64(buf[(fr.Limbs-1-j)*8:])
+			}
+
+			var carry int
+			for chunk := 0; chunk < nbChunks; chunk++ {
+				jc := chunk * c
+				index, shift := jc/64, uint64(jc%64)
+
+				// digit = value of the c-bit window, plus the carry of the previous window
+				digit := carry
+				carry = 0
+				if index < fr.Limbs {
+					w := k[index] >> shift
+					if shift > uint64(64-c) && index < fr.Limbs-1 {
+						// we are selecting bits over 2 words
+						w |= k[index+1] << (64 - shift)
+					}
+					digit += int(w & mask)
+				}
+
+				// if the digit is larger than 2^{c-1}, then, we borrow 2^c from the next window and substract
+				// 2^{c} to the current digit, making it negative.
+				if digit >= max {
+					digit -= (1 << c)
+					carry = 1
+				}
+
+				var bits uint16
+				if digit >= 0 {
+					bits = uint16(digit)
+				} else {
+					bits = uint16(-digit-1) | msbWindow
+				}
+				digits[chunk*len(scalars)+i] = bits
+			}
+		}
+	})
+
+	return digits
+}
+
+// msmReduceChunk reduces the weighted sum of the buckets into the result of the multiExp
+func msmReduceChunk(p *PointProj, c int, chChunks []chan PointProj) *PointProj {
+	_p := <-chChunks[len(chChunks)-1]
+	for j := len(chChunks) - 2; j >= 0; j-- {
+		for l := 0; l < c; l++ {
+			_p.Double(&_p)
+		}
+		totalj := <-chChunks[j]
+		_p.Add(&_p, &totalj)
+	}
+
+	return p.Set(&_p)
+}
+
+// msmProcessChunk places the points into the buckets according to their digits
+// and sends the weighted sum of the buckets in chRes
+func msmProcessChunk(chRes chan<- PointProj,
+	buckets []PointProj,
+	c int,
+	points []PointAffine,
+	digits []uint16) {
+
+	msbWindow := uint16(1 << (c - 1))
+
+	for i := 0; i < len(buckets); i++ {
+		buckets[i].setInfinity()
+	}
+
+	for i, bits := range digits {
+		if bits == 0 {
+			continue
+		}
+
+		// if msbWindow bit is set, we need to substract
+		if bits&msbWindow == 0 {
+			// add
+			buckets[bits-1].MixedAdd(&buckets[bits-1], &points[i])
+		} else {
+			// sub
+			var neg PointAffine
+			neg.Neg(&points[i])
+			buckets[bits & ^msbWindow].MixedAdd(&buckets[bits & ^msbWindow], &neg)
+		}
+	}
+
+	// reduce buckets into total
+	// total =  bucket[0] + 2*bucket[1] + 3*bucket[2] ... + n*bucket[n-1]
+
+	var runningSum, total PointProj
+	runningSum.setInfinity()
+	total.setInfinity()
+	for k := len(buckets) - 1; k >= 0; k-- {
+		runningSum.Add(&runningSum, &buckets[k])
+		total.Add(&total, &runningSum)
+	}
+
+	chRes <- total
+}
+
+// setInfinity sets p to the identity (0:1:1)
+func (p *PointProj) setInfinity() *PointProj {
+	p.X.SetZero()
+	p.Y.SetOne()
+	p.Z.SetOne()
+	return p
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pedersen
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+var errInvalidPoint = errors.New("invalid point: subgroup check failed")
+
+// sizePoint size in bytes of a compressed point (see twistededwards.PointAffine.Bytes)
+const sizePoint = fr.Limbs * 8
+
+// WriteTo writes binary encoding of the Key
+// as H||len(G)||G_0||G_1||..., where len(G) is a big endian uint32
+// and the points are compressed.
+func (key *Key) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, 0, 4+sizePoint*(len(key.G)+1))
+
+	bin := key.H.Bytes()
+	buf = append(buf, bin[:]...)
+	var nbG [4]byte
+	binary.BigEndian.PutUint32(nbG[:], uint32(len(key.G)))
+	buf = append(buf, nbG[:]...)
+	for i := 0; i < len(key.G); i++ {
+		bin = key.G[i].Bytes()
+		buf = append(buf, bin[:]...)
+	}
+
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// maxPreallocatedGenerators bounds the memory allocated by Key.ReadFrom
+// before the generators are actually read.
+const maxPreallocatedGenerators = 1 << 10
+
+// ReadFrom decodes Key data from reader.
+// It checks that the generators are in the prime order subgroup.
+func (key *Key) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+
+	read, err := readPoint(r, &key.H)
+	n += read
+	if err != nil {
+		return n, err
+	}
+
+	var nbG [4]byte
+	read32, err := io.ReadFull(r, nbG[:])
+	n += int64(read32)
+	if err != nil {
+		return n, err
+	}
+
+	// len(G) is not trusted: the generators are appended as they are decoded,
+	// so that an oversized length fails on the missing bytes instead of being allocated upfront.
+	size := binary.BigEndian.Uint32(nbG[:])
+	capacity := size
+	if capacity > maxPreallocatedGenerators {
+		capacity = maxPreallocatedGenerators
+	}
+	key.G = make([]twistededwards.PointAffine, 0, capacity)
+	for i := uint32(0); i < size; i++ {
+		var g twistededwards.PointAffine
+		read, err = readPoint(r, &g)
+		n += read
+		if err != nil {
+			return n, err
+		}
+		key.G = append(key.G, g)
+	}
+
+	return n, nil
+}
+
+// readPoint reads a compressed point from r and checks that it is in the prime order subgroup
+func readPoint(r io.Reader, p *twistededwards.PointAffine) (int64, error) {
+	var buf [sizePoint]byte
+	read, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return int64(read), err
+	}
+	if _, err := p.SetBytes(buf[:]); err != nil {
+		return int64(read), err
+	}
+	if !p.IsInSubGroup() {
+		return int64(read), errInvalidPoint
+	}
+	return int64(read), nil
+}
+
+// WriteTo writes binary encoding of the Commitment
+func (c *Commitment) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(c.Bytes())
+	return int64(n), err
+}
+
+// ReadFrom decodes Commitment data from reader.
+// It checks that the commitment is in the prime order subgroup.
+func (c *Commitment) ReadFrom(r io.Reader) (int64, error) {
+	return readPoint(r, (*twistededwards.PointAffine)(c))
+}
+
+// Bytes returns the compressed binary encoding of the Commitment
+func (c *Commitment) Bytes() []byte {
+	b := (*twistededwards.PointAffine)(c).Bytes()
+	return b[:]
+}
+
+// SetBytes sets c from buf, as returned by Bytes.
+// It checks that the commitment is in the prime order subgroup,
+// and returns the number of bytes read from buf.
+func (c *Commitment) SetBytes(buf []byte) (int, error) {
+	p := (*twistededwards.PointAffine)(c)
+	n, err := p.SetBytes(buf)
+	if err != nil {
+		return n, err
+	}
+	if !p.IsInSubGroup() {
+		return n, errInvalidPoint
+	}
+	return n, nil
+}
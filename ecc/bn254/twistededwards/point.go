@@ -23,6 +23,7 @@ import (
 	"math/bits"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/internal/ctmod"
 )
 
 // PointAffine point on a twisted Edwards curve
@@ -186,6 +187,17 @@ func (p *PointAffine) IsOnCurve() bool {
 	return lhs.Equal(&rhs)
 }
 
+// IsInSubGroup returns true if p is on the curve and in the prime order subgroup, false otherwise
+func (p *PointAffine) IsInSubGroup() bool {
+	if !p.IsOnCurve() {
+		return false
+	}
+	var _p PointExtended
+	_p.FromAffine(p)
+	_p.scalarMulWindowed(&_p, &edwards.Order)
+	return _p.IsZero()
+}
+
 // Add adds two points (x,y), (u,v) on a twisted Edwards curve with parameters a, d
 // modifies p
 func (p *PointAffine) Add(p1, p2 *PointAffine) *PointAffine {
@@ -224,6 +236,7 @@ func (p *PointAffine) Double(p1 *PointAffine) *PointAffine {
 // modifies p
 func (p *PointAffine) Neg(p1 *PointAffine) *PointAffine {
 	p.X.Neg(&p1.X)
+	p.Y = p1.Y
 	return p
 }
 
@@ -263,11 +276,11 @@ func (p *PointProj) Add(p1, p2 *PointProj) *PointProj {
 	res.X.Mul(&H, &I).
 		Sub(&res.X, &C).
 		Sub(&res.X, &D).
-		Mul(&res.X, &p1.Z).
+		Mul(&res.X, &A).
 		Mul(&res.X, &F)
 	H.Mul(&ecurve.A, &C)
 	res.Y.Sub(&D, &H).
-		Mul(&res.Y, &p.Z).
+		Mul(&res.Y, &A).
 		Mul(&res.Y, &G)
 	res.Z.Mul(&F, &G)
 
@@ -275,6 +288,33 @@ func (p *PointProj) Add(p1, p2 *PointProj) *PointProj {
 	return p
 }
 
+// MixedAdd adds a point in projective coordinates and a point in affine coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-projective.html#addition-madd-2008-bbjlp
+func (p *PointProj) MixedAdd(p1 *PointProj, p2 *PointAffine) *PointProj {
+
+	var B, C, D, E, F, G, H, I fr.Element
+	B.Square(&p1.Z)
+	C.Mul(&p1.X, &p2.X)
+	D.Mul(&p1.Y, &p2.Y)
+	E.Mul(&edwards.D, &C).Mul(&E, &D)
+	F.Sub(&B, &E)
+	G.Add(&B, &E)
+	H.Add(&p1.X, &p1.Y)
+	I.Add(&p2.X, &p2.Y)
+	p.X.Mul(&H, &I).
+		Sub(&p.X, &C).
+		Sub(&p.X, &D).
+		Mul(&p.X, &p1.Z).
+		Mul(&p.X, &F)
+	H.Mul(&edwards.A, &C)
+	p.Y.Sub(&D, &H).
+		Mul(&p.Y, &p1.Z).
+		Mul(&p.Y, &G)
+	p.Z.Mul(&F, &G)
+
+	return p
+}
+
 // Double adds points in projective coordinates
 // cf https://hyperelliptic.org/EFD/g1p/auto-twisted-projective.html
 func (p *PointProj) Double(p1 *PointProj) *PointProj {
@@ -306,9 +346,33 @@ func (p *PointProj) Double(p1 *PointProj) *PointProj {
 // Neg sets p to -p1 and returns it
 func (p *PointProj) Neg(p1 *PointProj) *PointProj {
 	p.X.Neg(&p1.X)
+	p.Y = p1.Y
+	p.Z = p1.Z
 	return p
 }
 
+// IsZero returns true if p is the identity (0:1:1)
+func (p *PointProj) IsZero() bool {
+	return !p.Z.IsZero() && p.X.IsZero() && p.Y.Equal(&p.Z)
+}
+
+// ClearCofactor sets p to cofactor*p1 and returns it
+func (p *PointProj) ClearCofactor(p1 *PointProj) *PointProj {
+	var cofactor big.Int
+	edwards.Cofactor.ToBigInt(&cofactor)
+
+	var res PointProj
+	res.Set(p1)
+	for i := cofactor.BitLen() - 2; i >= 0; i-- {
+		res.Double(&res)
+		if cofactor.Bit(i) == 1 {
+			res.Add(&res, p1)
+		}
+	}
+
+	return p.Set(&res)
+}
+
 // ScalarMul scalar multiplication of a point
 // p1 points on the twisted Edwards curve
 // c parameters of the twisted Edwards curve
@@ -316,30 +380,319 @@ func (p *PointProj) Neg(p1 *PointProj) *PointProj {
 // modifies p
 //func (p *PointAffine) ScalarMul(p1 *PointAffine, scalar fr.Element) *PointAffine {
 func (p *PointAffine) ScalarMul(p1 *PointAffine, scalar *big.Int) *PointAffine {
+	var _p PointExtended
+	_p.FromAffine(p1)
+	_p.ScalarMul(&_p, scalar)
+	p.FromExtended(&_p)
+	return p
+}
+
+// ScalarMulSecret scalar multiplication of a point, in constant time with respect to the scalar.
+// It must be used instead of ScalarMul when the scalar is secret (for example, a private key or
+// a signature nonce), as the running time of ScalarMul depends on the bits of the scalar.
+//
+// See PointExtended.ScalarMulSecret.
+// modifies p
+func (p *PointAffine) ScalarMulSecret(p1 *PointAffine, scalar *big.Int) *PointAffine {
+	var _p PointExtended
+	_p.FromAffine(p1)
+	_p.ScalarMulSecret(&_p, scalar)
+	p.fromExtendedSecret(&_p)
+	return p
+}
+
+// fromExtendedSecret is FromExtended, with a constant time inversion of p1.Z (by Fermat's little theorem)
+func (p *PointAffine) fromExtendedSecret(p1 *PointExtended) *PointAffine {
+	var e big.Int
+	e.Sub(fr.Modulus(), big.NewInt(2))
+	var zInv fr.Element
+	zInv.Exp(p1.Z, &e)
+	p.X.Mul(&p1.X, &zInv)
+	p.Y.Mul(&p1.Y, &zInv)
+	return p
+}
+
+// PointExtended point in extended coordinates (X:Y:T:Z), with x=X/Z, y=Y/Z and x*y=T/Z
+// cf https://eprint.iacr.org/2008/522.pdf
+type PointExtended struct {
+	X, Y, Z, T fr.Element
+}
+
+// Set sets p to p1 and returns it
+func (p *PointExtended) Set(p1 *PointExtended) *PointExtended {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.Set(&p1.Z)
+	p.T.Set(&p1.T)
+	return p
+}
+
+// Equal returns true if p=p1 false otherwise
+// If one point is on the affine chart Z=0 it returns false
+func (p *PointExtended) Equal(p1 *PointExtended) bool {
+	if p.Z.IsZero() || p1.Z.IsZero() {
+		return false
+	}
+	var pAffine, p1Affine PointAffine
+	pAffine.FromExtended(p)
+	p1Affine.FromExtended(p1)
+	return pAffine.Equal(&p1Affine)
+}
+
+// IsZero returns true if p is the identity (0:1:0:1)
+func (p *PointExtended) IsZero() bool {
+	return !p.Z.IsZero() && p.X.IsZero() && p.Y.Equal(&p.Z)
+}
+
+// setInfinity sets p to the identity (0:1:0:1)
+func (p *PointExtended) setInfinity() *PointExtended {
+	p.X.SetZero()
+	p.Y.SetOne()
+	p.Z.SetOne()
+	p.T.SetZero()
+	return p
+}
+
+// FromAffine sets p in extended from p in affine
+func (p *PointExtended) FromAffine(p1 *PointAffine) *PointExtended {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.SetOne()
+	p.T.Mul(&p1.X, &p1.Y)
+	return p
+}
+
+// FromProj sets p in extended from p in projective
+func (p *PointExtended) FromProj(p1 *PointProj) *PointExtended {
+	var X, Y fr.Element
+	X.Mul(&p1.X, &p1.Z)
+	Y.Mul(&p1.Y, &p1.Z)
+	p.T.Mul(&p1.X, &p1.Y)
+	p.Z.Square(&p1.Z)
+	p.X.Set(&X)
+	p.Y.Set(&Y)
+	return p
+}
+
+// FromExtended sets p in affine from p in extended
+func (p *PointAffine) FromExtended(p1 *PointExtended) *PointAffine {
+	var zInv fr.Element
+	zInv.Inverse(&p1.Z)
+	p.X.Mul(&p1.X, &zInv)
+	p.Y.Mul(&p1.Y, &zInv)
+	return p
+}
+
+// FromExtended sets p in projective from p in extended
+func (p *PointProj) FromExtended(p1 *PointExtended) *PointProj {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.Set(&p1.Z)
+	return p
+}
+
+// Neg sets p to -p1 and returns it
+func (p *PointExtended) Neg(p1 *PointExtended) *PointExtended {
+	p.X.Neg(&p1.X)
+	p.Y = p1.Y
+	p.Z = p1.Z
+	p.T.Neg(&p1.T)
+	return p
+}
 
-	var resProj, p1Proj PointProj
-	resProj.X.SetZero()
-	resProj.Y.SetOne()
-	resProj.Z.SetOne()
+// Add adds points in extended coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-extended.html#addition-add-2008-hwcd
+func (p *PointExtended) Add(p1, p2 *PointExtended) *PointExtended {
+
+	var A, B, C, D, E, F, G, H fr.Element
+	A.Mul(&p1.X, &p2.X)
+	B.Mul(&p1.Y, &p2.Y)
+	C.Mul(&p1.T, &p2.T).Mul(&C, &edwards.D)
+	D.Mul(&p1.Z, &p2.Z)
+	E.Add(&p1.X, &p1.Y)
+	F.Add(&p2.X, &p2.Y)
+	E.Mul(&E, &F).
+		Sub(&E, &A).
+		Sub(&E, &B)
+	F.Sub(&D, &C)
+	G.Add(&D, &C)
+	H.Mul(&edwards.A, &A)
+	H.Sub(&B, &H)
+
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
 
-	p1Proj.FromAffine(p1)
+	return p
+}
 
+// MixedAdd adds a point in extended coordinates and a point in affine coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-extended.html#addition-madd-2008-hwcd
+func (p *PointExtended) MixedAdd(p1 *PointExtended, p2 *PointAffine) *PointExtended {
+
+	var A, B, C, D, E, F, G, H fr.Element
+	A.Mul(&p1.X, &p2.X)
+	B.Mul(&p1.Y, &p2.Y)
+	C.Mul(&p2.X, &p2.Y).Mul(&C, &p1.T).Mul(&C, &edwards.D)
+	D.Set(&p1.Z)
+	E.Add(&p1.X, &p1.Y)
+	F.Add(&p2.X, &p2.Y)
+	E.Mul(&E, &F).
+		Sub(&E, &A).
+		Sub(&E, &B)
+	F.Sub(&D, &C)
+	G.Add(&D, &C)
+	H.Mul(&edwards.A, &A)
+	H.Sub(&B, &H)
+
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
+
+	return p
+}
+
+// Double doubles a point in extended coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-extended.html#doubling-dbl-2008-hwcd
+func (p *PointExtended) Double(p1 *PointExtended) *PointExtended {
+
+	var A, B, C, D, E, F, G, H fr.Element
+	A.Square(&p1.X)
+	B.Square(&p1.Y)
+	C.Square(&p1.Z).Double(&C)
+	D.Mul(&edwards.A, &A)
+	E.Add(&p1.X, &p1.Y).
+		Square(&E).
+		Sub(&E, &A).
+		Sub(&E, &B)
+	G.Add(&D, &B)
+	F.Sub(&G, &C)
+	H.Sub(&D, &B)
+
+	p.X.Mul(&E, &F)
+	p.Y.Mul(&G, &H)
+	p.T.Mul(&E, &H)
+	p.Z.Mul(&F, &G)
+
+	return p
+}
+
+// ClearCofactor sets p to cofactor*p1 and returns it
+func (p *PointExtended) ClearCofactor(p1 *PointExtended) *PointExtended {
+	var cofactor big.Int
+	edwards.Cofactor.ToBigInt(&cofactor)
+
+	var res PointExtended
+	res.Set(p1)
+	for i := cofactor.BitLen() - 2; i >= 0; i-- {
+		res.Double(&res)
+		if cofactor.Bit(i) == 1 {
+			res.Add(&res, p1)
+		}
+	}
+
+	return p.Set(&res)
+}
+
+// ScalarMul scalar multiplication of a point in extended coordinates,
+// with a fixed 4-bit window (see scalarMulWindowed)
+// scalar NOT in Montgomery form
+// modifies p
+func (p *PointExtended) ScalarMul(p1 *PointExtended, scalar *big.Int) *PointExtended {
+	return p.scalarMulWindowed(p1, scalar)
+}
+
+// scalarMulWindowed scalar multiplication of a point in extended coordinates,
+// with a fixed 4-bit window
+func (p *PointExtended) scalarMulWindowed(p1 *PointExtended, scalar *big.Int) *PointExtended {
+	const c = 4
 	const wordSize = bits.UintSize
 
-	sWords := scalar.Bits()
+	// table[i] = i*p1
+	var table [1 << c]PointExtended
+	table[0].setInfinity()
+	table[1].Set(p1)
+	for i := 2; i < len(table); i++ {
+		table[i].Add(&table[i-1], p1)
+	}
+
+	var res PointExtended
+	res.setInfinity()
 
+	sWords := scalar.Bits()
 	for i := len(sWords) - 1; i >= 0; i-- {
 		ithWord := sWords[i]
-		for k := 0; k < wordSize; k++ {
-			resProj.Double(&resProj)
-			kthBit := (ithWord >> (wordSize - 1 - k)) & 1
-			if kthBit == 1 {
-				resProj.Add(&resProj, &p1Proj)
+		for k := wordSize - c; k >= 0; k -= c {
+			for j := 0; j < c; j++ {
+				res.Double(&res)
+			}
+			if w := (ithWord >> k) & (1<<c - 1); w != 0 {
+				res.Add(&res, &table[w])
 			}
 		}
 	}
 
-	p.FromProj(&resProj)
+	return p.Set(&res)
+}
 
-	return p
+// ScalarMulSecret scalar multiplication of a point in extended coordinates,
+// in constant time with respect to the scalar.
+//
+// It is a fixed 4-bit window scalar multiplication over a fixed number of bits, where the
+// multiples of p1 are selected in constant time, which relies on the completeness of the
+// twisted Edwards addition law.
+// The scalar is always read over 2*fr.Bytes bytes, and reduced in constant time modulo the order
+// of the curve (the cofactor times the order of the subgroup): it must be smaller than 2^(16*fr.Bytes).
+// modifies p
+func (p *PointExtended) ScalarMulSecret(p1 *PointExtended, scalar *big.Int) *PointExtended {
+	const c = 4
+
+	var order big.Int
+	edwards.Cofactor.ToBigInt(&order)
+	order.Mul(&order, &edwards.Order)
+	m := ctmod.NewModulus(&order)
+
+	var buf [2 * fr.Bytes]byte
+	scalar.FillBytes(buf[:])
+	var k [fr.Bytes]byte
+	ctmod.FillBytes(k[:], m.SetBytes(make([]uint64, m.Words()), buf[:]))
+
+	// table[i] = i*p1
+	var table [1 << c]PointExtended
+	table[0].setInfinity()
+	table[1].Set(p1)
+	for i := 2; i < len(table); i++ {
+		table[i].Add(&table[i-1], p1)
+	}
+
+	var res, q PointExtended
+	res.setInfinity()
+	for i := 0; i < fr.Bytes; i++ {
+		for _, w := range [2]uint8{k[i] >> c, k[i] & (1<<c - 1)} {
+			for j := 0; j < c; j++ {
+				res.Double(&res)
+			}
+			q.setInfinity()
+			for j := 1; j < len(table); j++ {
+				q.cmov(&table[j], uint64(subtle.ConstantTimeByteEq(uint8(j), w)))
+			}
+			res.Add(&res, &q)
+		}
+	}
+
+	return p.Set(&res)
+}
+
+// cmov sets p to q if b == 1, and leaves it unchanged if b == 0, in constant time
+func (p *PointExtended) cmov(q *PointExtended, b uint64) {
+	mask := -b
+	pc := [...]*fr.Element{&p.X, &p.Y, &p.Z, &p.T}
+	qc := [...]*fr.Element{&q.X, &q.Y, &q.Z, &q.T}
+	for i := range pc {
+		for j := 0; j < fr.Limbs; j++ {
+			pc[i][j] ^= mask & (pc[i][j] ^ qc[i][j])
+		}
+	}
 }
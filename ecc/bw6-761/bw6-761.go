@@ -20,6 +20,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bw6-761/fp"
 	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/internal/fptower"
 )
 
 // https://eprint.iacr.org/2020/351.pdf
@@ -74,6 +75,26 @@ var glvBasis ecc.Lattice
 // generator of the curve
 var xGen big.Int
 
+// expose the tower, so that GT coordinates can be constructed and manipulated
+
+// E2 is a degree two finite field extension of fp.Element
+type E2 = fptower.E2
+
+// E6 is a degree three finite field extension of fp2
+type E6 = fptower.E6
+
+// BatchInvertE2 returns a new slice with every element of a inverted, using Montgomery's
+// batch inversion trick (a single E2 inversion). Zero elements are left unchanged.
+func BatchInvertE2(a []E2) []E2 {
+	return fptower.BatchInvertE2(a)
+}
+
+// BatchInvertE6 returns a new slice with every element of a inverted, using Montgomery's
+// batch inversion trick (a single E6 inversion). Zero elements are left unchanged.
+func BatchInvertE6(a []E6) []E6 {
+	return fptower.BatchInvertE6(a)
+}
+
 func init() {
 
 	bCurveCoeff.SetOne().Neg(&bCurveCoeff)
@@ -0,0 +1,51 @@
+package bw6761
+
+import "testing"
+
+func TestBatchInvertE2(t *testing.T) {
+	const n = 10
+	a := make([]E2, n)
+	for i := 1; i < n; i++ {
+		a[i].SetRandom()
+	}
+
+	res := BatchInvertE2(a)
+	if len(res) != n || !res[0].IsZero() {
+		t.Fatal("inverse of 0 should be 0")
+	}
+	for i := 1; i < n; i++ {
+		var expected E2
+		expected.Inverse(&a[i])
+		if !res[i].Equal(&expected) {
+			t.Fatal("BatchInvertE2 doesn't match Inverse")
+		}
+	}
+
+	if len(BatchInvertE2(nil)) != 0 {
+		t.Fatal("BatchInvertE2 of an empty slice should be empty")
+	}
+}
+
+func TestBatchInvertE6(t *testing.T) {
+	const n = 10
+	a := make([]E6, n)
+	for i := 1; i < n; i++ {
+		a[i].SetRandom()
+	}
+
+	res := BatchInvertE6(a)
+	if len(res) != n || !res[0].IsZero() {
+		t.Fatal("inverse of 0 should be 0")
+	}
+	for i := 1; i < n; i++ {
+		var expected E6
+		expected.Inverse(&a[i])
+		if !res[i].Equal(&expected) {
+			t.Fatal("BatchInvertE6 doesn't match Inverse")
+		}
+	}
+
+	if len(BatchInvertE6(nil)) != 0 {
+		t.Fatal("BatchInvertE6 of an empty slice should be empty")
+	}
+}
@@ -0,0 +1,474 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bw6-761"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr/polynomial"
+)
+
+// testSRS re-used accross tests of the KZG scheme
+var testSRS *SRS
+
+func init() {
+	const srsSize = 230
+	testSRS, _ = NewSRS(srsSize, new(big.Int).SetInt64(42))
+}
+
+func randomPolynomial(size int) polynomial.Polynomial {
+	f := make(polynomial.Polynomial, size)
+	for i := 0; i < size; i++ {
+		f[i].SetRandom()
+	}
+	return f
+}
+
+func TestDividePolyByXminusA(t *testing.T) {
+
+	const pSize = 230
+
+	// build random polynomial
+	pol := randomPolynomial(pSize)
+
+	// evaluate the polynomial at a random point
+	var point fr.Element
+	point.SetRandom()
+	evaluation := pol.Eval(&point).(*fr.Element)
+
+	// probabilistic test (using Schwartz Zippel lemma, evaluation at one point is enough)
+	var randPoint, xminusa fr.Element
+	randPoint.SetRandom()
+	polRandpoint := pol.Eval(&randPoint).(*fr.Element)
+	polRandpoint.Sub(polRandpoint, evaluation) // f(rand)-f(point)
+
+	// compute f-f(a)/x-a
+	h := dividePolyByXminusA(pol, *evaluation, point)
+	pol = nil // h reuses this memory
+
+	if len(h) != 229 {
+		t.Fatal("inconsistant size of quotient")
+	}
+
+	hRandPoint := h.Eval(&randPoint).(*fr.Element)
+	xminusa.Sub(&randPoint, &point) // rand-point
+
+	// f(rand)-f(point)	==? h(rand)*(rand-point)
+	hRandPoint.Mul(hRandPoint, &xminusa)
+
+	if !hRandPoint.Equal(polRandpoint) {
+		t.Fatal("Error f-f(a)/x-a")
+	}
+}
+
+func TestSerializationSRS(t *testing.T) {
+
+	// create a SRS
+	srs, err := NewSRS(64, new(big.Int).SetInt64(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// serialize it...
+	var buf bytes.Buffer
+	_, err = srs.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reconstruct the SRS
+	var _srs SRS
+	_, err = _srs.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compare
+	if !reflect.DeepEqual(srs, &_srs) {
+		t.Fatal("scheme serialization failed")
+	}
+
+}
+
+func TestCommit(t *testing.T) {
+
+	// create a polynomial
+	f := make(polynomial.Polynomial, 60)
+	for i := 0; i < 60; i++ {
+		f[i].SetRandom()
+	}
+
+	// commit using the method from KZG
+	kzgCommit, err := Commit(f, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// check commitment using manual commit
+	var x fr.Element
+	x.SetString("42")
+	fx := f.Eval(&x).(*fr.Element)
+	var fxbi big.Int
+	fx.ToBigIntRegular(&fxbi)
+	var manualCommit bw6761.G1Affine
+	manualCommit.Set(&testSRS.G1[0])
+	manualCommit.ScalarMultiplication(&manualCommit, &fxbi)
+
+	// compare both results
+	if !kzgCommit.Equal(&manualCommit) {
+		t.Fatal("error KZG commitment")
+	}
+
+	// polynomials larger than the SRS are rejected
+	if _, err := Commit(make(polynomial.Polynomial, len(testSRS.G1)+1), testSRS); err != ErrInvalidPolynomialSize {
+		t.Fatal("commitment to a polynomial larger than the SRS should fail")
+	}
+
+}
+
+func TestVerifySinglePoint(t *testing.T) {
+
+	// create a polynomial
+	f := randomPolynomial(60)
+
+	// commit the polynomial
+	digest, err := Commit(f, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compute opening proof at a random point
+	var point fr.Element
+	point.SetString("4321")
+	proof, err := Open(f, &point, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the claimed valued
+	expected := f.Eval(&point).(*fr.Element)
+	if !proof.ClaimedValue.Equal(expected) {
+		t.Fatal("inconsistant claimed value")
+	}
+
+	// verify correct proof
+	err = Verify(&digest, &proof, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify wrong proof
+	proof.ClaimedValue.Double(&proof.ClaimedValue)
+	err = Verify(&digest, &proof, testSRS)
+	if err == nil {
+		t.Fatal("verifying wrong proof should have failed")
+	}
+}
+
+func TestVerifyConstantPolynomial(t *testing.T) {
+
+	// constant polynomial, the quotient is zero
+	f := randomPolynomial(1)
+
+	digest, err := Commit(f, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var point fr.Element
+	point.SetRandom()
+	proof, err := Open(f, &point, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proof.H.IsInfinity() {
+		t.Fatal("quotient of a constant polynomial should be zero")
+	}
+
+	if err := Verify(&digest, &proof, testSRS); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBatchVerifySinglePoint(t *testing.T) {
+
+	// create polynomials
+	f := make([]polynomial.Polynomial, 10)
+	for i := 0; i < 10; i++ {
+		f[i] = randomPolynomial(60 + i)
+	}
+
+	// commit the polynomials
+	digests := make([]Digest, 10)
+	for i := 0; i < 10; i++ {
+		digests[i], _ = Commit(f[i], testSRS)
+
+	}
+
+	// pick a hash function
+	hf := sha256.New()
+
+	// compute opening proof at a random point
+	var point fr.Element
+	point.SetString("4321")
+	proof, err := BatchOpenSinglePoint(f, digests, &point, hf, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the claimed values
+	for i := 0; i < 10; i++ {
+		expectedClaim := f[i].Eval(&point).(*fr.Element)
+		if !expectedClaim.Equal(&proof.ClaimedValues[i]) {
+			t.Fatal("inconsistant claimed values")
+		}
+	}
+
+	// verify correct proof
+	err = BatchVerifySinglePoint(digests, &proof, hf, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify wrong proof
+	proof.ClaimedValues[0].Double(&proof.ClaimedValues[0])
+	err = BatchVerifySinglePoint(digests, &proof, hf, testSRS)
+	if err == nil {
+		t.Fatal("verifying wrong proof should have failed")
+	}
+
+}
+
+func TestBatchVerifyMultiPoints(t *testing.T) {
+
+	// create polynomials
+	f := make([]polynomial.Polynomial, 10)
+	for i := 0; i < 10; i++ {
+		f[i] = randomPolynomial(60 + i)
+	}
+
+	// commit the polynomials
+	digests := make([]Digest, 10)
+	for i := 0; i < 10; i++ {
+		digests[i], _ = Commit(f[i], testSRS)
+	}
+
+	// compute opening proofs at different points
+	var point fr.Element
+	point.SetRandom()
+	proofs := make([]OpeningProof, 10)
+	for i := 0; i < 10; i++ {
+		var err error
+		proofs[i], err = Open(f[i], &point, testSRS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		point.Add(&point, &point)
+	}
+
+	// verify correct proof
+	err := BatchVerifyMultiPoints(digests, proofs, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify wrong proof
+	proofs[0].ClaimedValue.Double(&proofs[0].ClaimedValue)
+	err = BatchVerifyMultiPoints(digests, proofs, testSRS)
+	if err == nil {
+		t.Fatal("verifying wrong proof should have failed")
+	}
+
+}
+
+func TestSerializationProofs(t *testing.T) {
+
+	f := randomPolynomial(60)
+	var point fr.Element
+	point.SetRandom()
+
+	// opening proof
+	proof, err := Open(f, &point, testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _proof OpeningProof
+	if _, err := _proof.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(proof, _proof) {
+		t.Fatal("opening proof serialization failed")
+	}
+
+	// batch opening proof
+	digest, _ := Commit(f, testSRS)
+	batchProof, err := BatchOpenSinglePoint([]polynomial.Polynomial{f, f}, []Digest{digest, digest}, &point, sha256.New(), testSRS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := batchProof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _batchProof BatchOpeningProof
+	if _, err := _batchProof.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(batchProof, _batchProof) {
+		t.Fatal("batch opening proof serialization failed")
+	}
+
+	// a batch opening proof with an oversized number of claimed values
+	buf.Reset()
+	batchProof.ClaimedValues = nil
+	if _, err := batchProof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	binary.BigEndian.PutUint64(data[len(data)-8:], math.MaxUint64)
+	if _, err := _batchProof.ReadFrom(bytes.NewReader(data)); err == nil {
+		t.Fatal("decoding a batch opening proof with an oversized number of claimed values should fail")
+	}
+}
+
+const benchSize = 1 << 16
+
+func BenchmarkKZGCommit(b *testing.B) {
+	benchSRS, err := NewSRS(benchSize, new(big.Int).SetInt64(42))
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomPolynomial(benchSize / 2)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Commit(p, benchSRS)
+	}
+}
+
+func BenchmarkKZGOpen(b *testing.B) {
+	benchSRS, err := NewSRS(benchSize, new(big.Int).SetInt64(42))
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomPolynomial(benchSize / 2)
+	var r fr.Element
+	r.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Open(p, &r, benchSRS)
+	}
+}
+
+func BenchmarkKZGVerify(b *testing.B) {
+	benchSRS, err := NewSRS(benchSize, new(big.Int).SetInt64(42))
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomPolynomial(benchSize / 2)
+	var r fr.Element
+	r.SetRandom()
+
+	// commit
+	comm, err := Commit(p, benchSRS)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// open
+	openingProof, err := Open(p, &r, benchSRS)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Verify(&comm, &openingProof, benchSRS)
+	}
+}
+
+func BenchmarkKZGBatchOpen10(b *testing.B) {
+	benchSRS, err := NewSRS(benchSize, new(big.Int).SetInt64(42))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// 10 random polynomials
+	var ps [10]polynomial.Polynomial
+	for i := 0; i < 10; i++ {
+		ps[i] = randomPolynomial(benchSize / 2)
+	}
+
+	// commitments
+	var commitments [10]Digest
+	for i := 0; i < 10; i++ {
+		commitments[i], _ = Commit(ps[i], benchSRS)
+	}
+
+	var r fr.Element
+	r.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = BatchOpenSinglePoint(ps[:], commitments[:], &r, sha256.New(), benchSRS)
+	}
+}
+
+func BenchmarkKZGBatchVerify10(b *testing.B) {
+	benchSRS, err := NewSRS(benchSize, new(big.Int).SetInt64(42))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// 10 random polynomials
+	var ps [10]polynomial.Polynomial
+	for i := 0; i < 10; i++ {
+		ps[i] = randomPolynomial(benchSize / 2)
+	}
+
+	// commitments
+	var commitments [10]Digest
+	for i := 0; i < 10; i++ {
+		commitments[i], _ = Commit(ps[i], benchSRS)
+	}
+
+	var r fr.Element
+	r.SetRandom()
+
+	proof, err := BatchOpenSinglePoint(ps[:], commitments[:], &r, sha256.New(), benchSRS)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = BatchVerifySinglePoint(commitments[:], &proof, sha256.New(), benchSRS)
+	}
+}
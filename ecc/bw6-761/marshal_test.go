@@ -42,6 +42,7 @@ func TestEncoder(t *testing.T) {
 	var inF G2Affine
 	var inG []G1Affine
 	var inH []G2Affine
+	var inI GT
 
 	// set values of inputs
 	inA = rand.Uint64()
@@ -53,12 +54,13 @@ func TestEncoder(t *testing.T) {
 	inG = make([]G1Affine, 2)
 	inH = make([]G2Affine, 0)
 	inG[1] = inD
+	inI.SetRandom()
 
 	// encode them, compressed and raw
 	var buf, bufRaw bytes.Buffer
 	enc := NewEncoder(&buf)
 	encRaw := NewEncoder(&bufRaw, RawEncoding())
-	toEncode := []interface{}{inA, &inB, &inC, &inD, &inE, &inF, inG, inH}
+	toEncode := []interface{}{inA, &inB, &inC, &inD, &inE, &inF, inG, inH, &inI}
 	for _, v := range toEncode {
 		if err := enc.Encode(v); err != nil {
 			t.Fatal(err)
@@ -80,8 +82,9 @@ func TestEncoder(t *testing.T) {
 		var outF G2Affine
 		var outG []G1Affine
 		var outH []G2Affine
+		var outI GT
 
-		toDecode := []interface{}{&outA, &outB, &outC, &outD, &outE, &outF, &outG, &outH}
+		toDecode := []interface{}{&outA, &outB, &outC, &outD, &outE, &outF, &outG, &outH, &outI}
 		for _, v := range toDecode {
 			if err := dec.Decode(v); err != nil {
 				t.Fatal(err)
@@ -102,6 +105,9 @@ func TestEncoder(t *testing.T) {
 		if !inF.Equal(&outF) {
 			t.Fatal("decode(encode(G2Affine) failed")
 		}
+		if !inI.Equal(&outI) {
+			t.Fatal("decode(encode(GT) failed")
+		}
 		if (len(inG) != len(outG)) || (len(inH) != len(outH)) {
 			t.Fatal("decode(encode(slice(points))) failed")
 		}
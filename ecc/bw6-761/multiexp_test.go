@@ -17,12 +17,16 @@
 package bw6761
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"math/big"
 	"math/bits"
 	"runtime"
 	"testing"
+	"time"
 
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
@@ -78,12 +82,12 @@ func TestMultiExpG1(t *testing.T) {
 				opt := NewCPUSemaphore(runtime.NumCPU())
 
 				opt.lock.Lock()
-				scalars := partitionScalars(sampleScalars[:], 5)
-				r5.msmC5(samplePoints[:], scalars, opt)
+				scalars := partitionScalars(sampleScalars[:], 5, runtime.NumCPU())
+				r5.msmC5(samplePoints[:], scalars, &msmScheduler{sem: opt})
 
 				opt.lock.Lock()
-				scalars = partitionScalars(sampleScalars[:], 16)
-				r16.msmC16(samplePoints[:], scalars, opt)
+				scalars = partitionScalars(sampleScalars[:], 16, runtime.NumCPU())
+				r16.msmC16(samplePoints[:], scalars, &msmScheduler{sem: opt})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
@@ -113,8 +117,8 @@ func TestMultiExpG1(t *testing.T) {
 				// semaphore to limit number of cpus
 				opt := NewCPUSemaphore(runtime.NumCPU())
 				opt.lock.Lock()
-				scalars := partitionScalars(sampleScalars[:], 4)
-				result.msmC4(samplePoints[:], scalars, opt)
+				scalars := partitionScalars(sampleScalars[:], 4, runtime.NumCPU())
+				result.msmC4(samplePoints[:], scalars, &msmScheduler{sem: opt})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
@@ -143,8 +147,8 @@ func TestMultiExpG1(t *testing.T) {
 				// semaphore to limit number of cpus
 				opt := NewCPUSemaphore(runtime.NumCPU())
 				opt.lock.Lock()
-				scalars := partitionScalars(sampleScalars[:], 5)
-				result.msmC5(samplePoints[:], scalars, opt)
+				scalars := partitionScalars(sampleScalars[:], 5, runtime.NumCPU())
+				result.msmC5(samplePoints[:], scalars, &msmScheduler{sem: opt})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
@@ -173,8 +177,8 @@ func TestMultiExpG1(t *testing.T) {
 				// semaphore to limit number of cpus
 				opt := NewCPUSemaphore(runtime.NumCPU())
 				opt.lock.Lock()
-				scalars := partitionScalars(sampleScalars[:], 8)
-				result.msmC8(samplePoints[:], scalars, opt)
+				scalars := partitionScalars(sampleScalars[:], 8, runtime.NumCPU())
+				result.msmC8(samplePoints[:], scalars, &msmScheduler{sem: opt})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
@@ -203,8 +207,8 @@ func TestMultiExpG1(t *testing.T) {
 				// semaphore to limit number of cpus
 				opt := NewCPUSemaphore(runtime.NumCPU())
 				opt.lock.Lock()
-				scalars := partitionScalars(sampleScalars[:], 16)
-				result.msmC16(samplePoints[:], scalars, opt)
+				scalars := partitionScalars(sampleScalars[:], 16, runtime.NumCPU())
+				result.msmC16(samplePoints[:], scalars, &msmScheduler{sem: opt})
 
 				// compute expected result with double and add
 				var finalScalar, mixerBigInt big.Int
@@ -256,39 +260,71 @@ func TestMultiExpG1(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
-func BenchmarkMultiExpG1(b *testing.B) {
-	// ensure every words of the scalars are filled
-	var mixer fr.Element
-	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+func TestMultiExpBatchAffineG1(t *testing.T) {
 
-	const pow = (bits.UintSize / 2) - (bits.UintSize / 8) // 24 on 64 bits arch, 12 on 32 bits
-	const nbSamples = 1 << pow
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 2
 
-	var samplePoints [nbSamples]G1Affine
-	var sampleScalars [nbSamples]fr.Element
+	properties := gopter.NewProperties(parameters)
 
-	for i := 1; i <= nbSamples; i++ {
-		sampleScalars[i-1].SetUint64(uint64(i)).
-			Mul(&sampleScalars[i-1], &mixer).
-			FromMont()
-		samplePoints[i-1] = g1GenAff
+	genScalar := GenFr()
+
+	const nbSamples = 1 << 10
+
+	// few distinct points and their opposites, so that the buckets get doublings and cancellations,
+	// the last one being the point at infinity
+	var base [7]G1Affine
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < len(base); i++ {
+		base[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+	samplePoints := make([]G1Affine, nbSamples)
+	for i := 0; i < nbSamples-1; i++ {
+		samplePoints[i] = base[i%len(base)]
+		if (i/len(base))%2 == 1 {
+			samplePoints[i].Neg(&samplePoints[i])
+		}
 	}
 
-	var testPoint G1Affine
+	properties.Property("[G1] Multi exponentation with batch affine buckets should be consistant with msmC16", prop.ForAll(
+		func(mixer fr.Element) bool {
 
-	for i := 5; i <= pow; i++ {
-		using := 1 << i
+			// half of the scalars are small, so that the same points are added in the same buckets
+			sampleScalars := make([]fr.Element, nbSamples)
+			for i := 1; i <= nbSamples; i++ {
+				if i%2 == 0 {
+					sampleScalars[i-1].SetUint64(uint64(i%3 + 1))
+				} else {
+					sampleScalars[i-1].SetUint64(uint64(i)).
+						MulAssign(&mixer)
+				}
+				sampleScalars[i-1].FromMont()
+			}
 
-		b.Run(fmt.Sprintf("%d points", using), func(b *testing.B) {
-			b.ResetTimer()
-			for j := 0; j < b.N; j++ {
-				testPoint.MultiExp(samplePoints[:using], sampleScalars[:using])
+			var expected G1Jac
+			opt := NewCPUSemaphore(runtime.NumCPU())
+			opt.lock.Lock()
+			expected.msmC16(samplePoints, partitionScalars(sampleScalars, 16, runtime.NumCPU()), &msmScheduler{sem: opt})
+
+			for _, c := range []uint64{12, 13, 16} {
+				var result G1Jac
+				opt.lock.Lock()
+				result.msmBatchAffine(samplePoints, partitionScalars(sampleScalars, c, runtime.NumCPU()), c, fr.Limbs*64, &msmScheduler{sem: opt})
+				if !result.Equal(&expected) {
+					return false
+				}
 			}
-		})
-	}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
-func TestMultiExpG2(t *testing.T) {
+func TestMultiExpGLVG1(t *testing.T) {
 
 	parameters := gopter.DefaultTestParameters()
 	parameters.MinSuccessfulTests = 2
@@ -300,220 +336,1428 @@ func TestMultiExpG2(t *testing.T) {
 	// size of the multiExps
 	const nbSamples = 143
 
-	// multi exp points
-	var samplePoints [nbSamples]G2Affine
-	var g G2Jac
-	g.Set(&g2Gen)
-	for i := 1; i <= nbSamples; i++ {
-		samplePoints[i-1].FromJacobian(&g)
-		g.AddAssign(&g2Gen)
+	// the last point is the point at infinity
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples-1; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
 	}
 
-	// final scalar to use in double and add method (without mixer factor)
-	// n(n+1)(2n+1)/6  (sum of the squares from 1 to n)
-	var scalar big.Int
-	scalar.SetInt64(nbSamples)
-	scalar.Mul(&scalar, new(big.Int).SetInt64(nbSamples+1))
-	scalar.Mul(&scalar, new(big.Int).SetInt64(2*nbSamples+1))
-	scalar.Div(&scalar, new(big.Int).SetInt64(6))
-
-	if testing.Short() {
-		// we test only c = 5 and c = 16
-
-		properties.Property("[G2] Multi exponentation (c=5, c=16) should be consistant with sum of square", prop.ForAll(
-			func(mixer fr.Element) bool {
+	properties.Property("[G1] Multi exponentation with GLV split scalars should be consistant with msmC16", prop.ForAll(
+		func(mixer fr.Element) bool {
 
-				var r5, r16, expected G2Jac
+			sampleScalars := make([]fr.Element, nbSamples)
+			for i := 0; i < nbSamples; i++ {
+				sampleScalars[i].SetUint64(uint64(i)).
+					MulAssign(&mixer)
+				mixer.Square(&mixer)
+			}
+			// edge cases: 0, 1, -1
+			sampleScalars[0].SetZero()
+			sampleScalars[1].SetOne()
+			sampleScalars[2].SetOne().Neg(&sampleScalars[2])
+			for i := 0; i < nbSamples; i++ {
+				sampleScalars[i].FromMont()
+			}
 
-				// mixer ensures that all the words of a fpElement are set
-				var sampleScalars [nbSamples]fr.Element
+			var expected G1Jac
+			opt := NewCPUSemaphore(runtime.NumCPU())
+			opt.lock.Lock()
+			expected.msmC16(samplePoints, partitionScalars(sampleScalars, 16, runtime.NumCPU()), &msmScheduler{sem: opt})
 
-				for i := 1; i <= nbSamples; i++ {
-					sampleScalars[i-1].SetUint64(uint64(i)).
-						MulAssign(&mixer).
-						FromMont()
+			for _, c := range []uint64{msmMinC, 9, 13} {
+				var result G1Jac
+				result.msmGLV(samplePoints, sampleScalars, c, &msmScheduler{sem: opt})
+				if !result.Equal(&expected) {
+					return false
 				}
+			}
+			return true
+		},
+		genScalar,
+	))
 
-				// semaphore to limit number of cpus
-				opt := NewCPUSemaphore(runtime.NumCPU())
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
-				opt.lock.Lock()
-				scalars := partitionScalars(sampleScalars[:], 5)
-				r5.msmC5(samplePoints[:], scalars, opt)
+	// MultiExp switches to msmGLV on the numbers of points of msmUseGLVG1Affine
+	for _, nbPoints := range []int{1 << 10, 1 << 11} {
+		points := make([]G1Affine, nbPoints)
+		scalars := make([]fr.Element, nbPoints)
+		g.Set(&g1Gen)
+		for i := range points {
+			points[i].FromJacobian(&g)
+			g.AddAssign(&g1Gen)
+			scalars[i].SetRandom()
+		}
+		var result, expected G1Jac
+		result.MultiExp(points, scalars)
+		expected.msm(points, scalars, msmBestCG1Affine(nbPoints, 0), &msmScheduler{sem: NewCPUSemaphore(runtime.NumCPU())})
+		if !result.Equal(&expected) {
+			t.Fatalf("MultiExp of %d points (GLV: %v) doesn't match msm", nbPoints, msmUseGLVG1Affine(nbPoints))
+		}
+	}
+}
 
-				opt.lock.Lock()
-				scalars = partitionScalars(sampleScalars[:], 16)
-				r16.msmC16(samplePoints[:], scalars, opt)
+func TestMultiExpContextG1(t *testing.T) {
 
-				// compute expected result with double and add
-				var finalScalar, mixerBigInt big.Int
-				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
-				expected.ScalarMultiplication(&g2Gen, &finalScalar)
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 2
 
-				return r5.Equal(&expected) && r16.Equal(&expected)
-			},
-			genScalar,
-		))
-	} else {
+	properties := gopter.NewProperties(parameters)
 
-		properties.Property("[G2] Multi exponentation (c=4) should be consistant with sum of square", prop.ForAll(
-			func(mixer fr.Element) bool {
+	genScalar := GenFr()
 
-				var result, expected G2Jac
+	// size of the multiExps
+	const nbSamples = 143
 
-				// mixer ensures that all the words of a fpElement are set
-				var sampleScalars [nbSamples]fr.Element
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
 
-				for i := 1; i <= nbSamples; i++ {
-					sampleScalars[i-1].SetUint64(uint64(i)).
-						MulAssign(&mixer).
-						FromMont()
-				}
+	sampleScalars := func(mixer fr.Element, n int) []fr.Element {
+		scalars := make([]fr.Element, n)
+		for i := 0; i < n; i++ {
+			scalars[i].SetUint64(uint64(i + 1)).
+				MulAssign(&mixer).
+				FromMont()
+			mixer.Square(&mixer)
+		}
+		return scalars
+	}
 
-				// semaphore to limit number of cpus
-				opt := NewCPUSemaphore(runtime.NumCPU())
-				opt.lock.Lock()
-				scalars := partitionScalars(sampleScalars[:], 4)
-				result.msmC4(samplePoints[:], scalars, opt)
+	properties.Property("[G1] MultiExpContext should be consistant with MultiExp", prop.ForAll(
+		func(mixer fr.Element) bool {
+			scalars := sampleScalars(mixer, nbSamples)
+
+			var expected G1Jac
+			expected.MultiExp(samplePoints, scalars)
+
+			configs := []ecc.MultiExpConfig{
+				{},
+				{NbTasks: 3},
+				// a single window of c = 5 at a time
+				{NbTasks: 3, MaxBucketMemory: msmBucketMemoryG1Affine(5)},
+				// less memory than the buckets of a window of the smallest c
+				{NbTasks: 3, MaxBucketMemory: 1},
+				{GLV: true},
+				{NbTasks: 3, MaxBucketMemory: msmBucketMemoryG1Affine(5), GLV: true},
+			}
+			for _, config := range configs {
+				var result G1Jac
+				if _, err := result.MultiExpContext(context.Background(), samplePoints, scalars, config); err != nil {
+					return false
+				}
+				if !result.Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
 
-				// compute expected result with double and add
-				var finalScalar, mixerBigInt big.Int
-				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
-				expected.ScalarMultiplication(&g2Gen, &finalScalar)
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
-				return result.Equal(&expected)
-			},
-			genScalar,
-		))
+	// the memory bound is honored by the choice of c
+	maxBucketMemory := msmBucketMemoryG1Affine(8)
+	if c := msmBestCG1Affine(1<<22, maxBucketMemory); msmBucketMemoryG1Affine(c) > maxBucketMemory {
+		t.Fatal("the buckets of a window exceed MaxBucketMemory")
+	}
 
-		properties.Property("[G2] Multi exponentation (c=5) should be consistant with sum of square", prop.ForAll(
-			func(mixer fr.Element) bool {
+	var mixer fr.Element
+	mixer.SetRandom()
+
+	// a cancelled multiExp returns ctx.Err() and leaves the result unchanged
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var result G1Jac
+	result.Set(&g1Gen)
+	if _, err := result.MultiExpContext(ctx, samplePoints, sampleScalars(mixer, nbSamples), ecc.MultiExpConfig{}); err != context.Canceled {
+		t.Fatal("a cancelled MultiExpContext should return context.Canceled")
+	}
+	if !result.Equal(&g1Gen) {
+		t.Fatal("a cancelled MultiExpContext should leave the result unchanged")
+	}
 
-				var result, expected G2Jac
+	// the go routines of a large multiExp stop before the end of the computation
+	if !testing.Short() {
+		const nbPoints = 1 << 16
+		points := make([]G1Affine, nbPoints)
+		for i := 0; i < nbPoints; i++ {
+			points[i] = samplePoints[i%nbSamples]
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		if _, err := result.MultiExpContext(ctx, points, sampleScalars(mixer, nbPoints), ecc.MultiExpConfig{NbTasks: 2}); err != context.DeadlineExceeded {
+			t.Fatal("MultiExpContext should return context.DeadlineExceeded")
+		}
+	}
+}
 
-				// mixer ensures that all the words of a fpElement are set
-				var sampleScalars [nbSamples]fr.Element
+func TestMultiExpUint64G1(t *testing.T) {
 
-				for i := 1; i <= nbSamples; i++ {
-					sampleScalars[i-1].SetUint64(uint64(i)).
-						MulAssign(&mixer).
-						FromMont()
-				}
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 2
 
-				// semaphore to limit number of cpus
-				opt := NewCPUSemaphore(runtime.NumCPU())
-				opt.lock.Lock()
-				scalars := partitionScalars(sampleScalars[:], 5)
-				result.msmC5(samplePoints[:], scalars, opt)
+	properties := gopter.NewProperties(parameters)
 
-				// compute expected result with double and add
-				var finalScalar, mixerBigInt big.Int
-				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
-				expected.ScalarMultiplication(&g2Gen, &finalScalar)
+	genScalar := GenFr()
 
-				return result.Equal(&expected)
-			},
-			genScalar,
-		))
+	// size of the multiExps
+	const nbSamples = 143
 
-		properties.Property("[G2] Multi exponentation (c=8) should be consistant with sum of square", prop.ForAll(
-			func(mixer fr.Element) bool {
+	// the last point is the point at infinity
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples-1; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
 
-				var result, expected G2Jac
+	// expected computes the MultiExp with the corresponding fr.Element scalars
+	expected := func(scalars []uint64) G1Jac {
+		frScalars := make([]fr.Element, len(scalars))
+		for i := 0; i < len(scalars); i++ {
+			frScalars[i] = fr.Element{scalars[i]}
+		}
+		var res G1Jac
+		res.MultiExp(samplePoints, frScalars)
+		return res
+	}
 
-				// mixer ensures that all the words of a fpElement are set
-				var sampleScalars [nbSamples]fr.Element
+	properties.Property("[G1] MultiExpUint64 should be consistant with MultiExp", prop.ForAll(
+		func(mixer fr.Element) bool {
 
-				for i := 1; i <= nbSamples; i++ {
-					sampleScalars[i-1].SetUint64(uint64(i)).
-						MulAssign(&mixer).
-						FromMont()
+			// booleans, bytes and 64-bit scalars
+			scalars := make([]uint64, nbSamples)
+			for i := 0; i < nbSamples; i++ {
+				mixer.Square(&mixer)
+				switch i % 3 {
+				case 0:
+					scalars[i] = mixer[0] & 1
+				case 1:
+					scalars[i] = mixer[0] & 0xff
+				default:
+					scalars[i] = mixer[0]
 				}
+			}
 
-				// semaphore to limit number of cpus
-				opt := NewCPUSemaphore(runtime.NumCPU())
-				opt.lock.Lock()
-				scalars := partitionScalars(sampleScalars[:], 8)
-				result.msmC8(samplePoints[:], scalars, opt)
+			var booleans, bytes [nbSamples]uint64
+			for i := 0; i < nbSamples; i++ {
+				booleans[i] = scalars[i] & 1
+				bytes[i] = scalars[i] & 0xff
+			}
 
-				// compute expected result with double and add
-				var finalScalar, mixerBigInt big.Int
-				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
-				expected.ScalarMultiplication(&g2Gen, &finalScalar)
+			for _, s := range [][]uint64{scalars, booleans[:], bytes[:]} {
+				var result G1Jac
+				result.MultiExpUint64(samplePoints, s)
+				e := expected(s)
+				if !result.Equal(&e) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
 
-				return result.Equal(&expected)
-			},
-			genScalar,
-		))
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
-		properties.Property("[G2] Multi exponentation (c=16) should be consistant with sum of square", prop.ForAll(
-			func(mixer fr.Element) bool {
+	// only 0 and 1 scalars
+	var zeros, ones [nbSamples]uint64
+	for i := 0; i < nbSamples; i++ {
+		ones[i] = 1
+	}
+	for _, s := range [][]uint64{zeros[:], ones[:]} {
+		var result G1Jac
+		result.MultiExpUint64(samplePoints, s)
+		e := expected(s)
+		if !result.Equal(&e) {
+			t.Fatal("MultiExpUint64 with 0 and 1 scalars should be consistant with MultiExp")
+		}
+	}
+}
 
-				var result, expected G2Jac
+func TestMultiExpPrecomputedG1(t *testing.T) {
 
-				// mixer ensures that all the words of a fpElement are set
-				var sampleScalars [nbSamples]fr.Element
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 2
 
-				for i := 1; i <= nbSamples; i++ {
-					sampleScalars[i-1].SetUint64(uint64(i)).
-						MulAssign(&mixer).
-						FromMont()
-				}
+	properties := gopter.NewProperties(parameters)
 
-				// semaphore to limit number of cpus
-				opt := NewCPUSemaphore(runtime.NumCPU())
-				opt.lock.Lock()
-				scalars := partitionScalars(sampleScalars[:], 16)
-				result.msmC16(samplePoints[:], scalars, opt)
+	genScalar := GenFr()
 
-				// compute expected result with double and add
-				var finalScalar, mixerBigInt big.Int
-				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
-				expected.ScalarMultiplication(&g2Gen, &finalScalar)
+	const nbSamples = 30
 
-				return result.Equal(&expected)
-			},
-			genScalar,
-		))
+	// multi exp points, the last one being the point at infinity
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples-1; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
 
+	// (c, stride) pairs, the last stride being larger than the number of windows
+	params := [][2]uint64{{4, 1}, {5, 3}, {16, 2}, {7, 1000}}
+	tables := make([]*G1MultiExpTable, len(params))
+	for i, p := range params {
+		var err error
+		if tables[i], err = NewG1MultiExpTable(samplePoints, p[0], p[1]); err != nil {
+			t.Fatal(err)
+		}
 	}
 
-	// note : this test is here as we expect to have a different multiExp than the above bucket method
-	// for small number of points
-	properties.Property("[G2] Multi exponentation (<50points) should be consistant with sum of square", prop.ForAll(
+	properties.Property("[G1] Multi exponentation with a precomputed table should be consistant with MultiExp", prop.ForAll(
 		func(mixer fr.Element) bool {
 
-			var g G2Jac
-			g.Set(&g2Gen)
-
 			// mixer ensures that all the words of a fpElement are set
-			samplePoints := make([]G2Affine, 30)
-			sampleScalars := make([]fr.Element, 30)
-
-			for i := 1; i <= 30; i++ {
+			sampleScalars := make([]fr.Element, nbSamples)
+			for i := 1; i <= nbSamples; i++ {
 				sampleScalars[i-1].SetUint64(uint64(i)).
 					MulAssign(&mixer).
 					FromMont()
-				samplePoints[i-1].FromJacobian(&g)
-				g.AddAssign(&g2Gen)
 			}
 
-			var op1MultiExp G2Affine
-			op1MultiExp.MultiExp(samplePoints, sampleScalars)
+			var expected G1Jac
+			expected.MultiExp(samplePoints, sampleScalars)
 
-			var finalBigScalar fr.Element
-			var finalBigScalarBi big.Int
-			var op1ScalarMul G2Affine
-			finalBigScalar.SetString("9455").MulAssign(&mixer)
+			for _, table := range tables {
+				var result G1Jac
+				if _, err := result.MultiExpPrecomputed(table, sampleScalars, NewCPUSemaphore(3)); err != nil {
+					return false
+				}
+				if !result.Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// scalars close to r, whose highest digit is the largest, with every allowed c
+	{
+		var expected G1Jac
+		smallScalars := make([]fr.Element, nbSamples)
+		largeScalars := make([]fr.Element, nbSamples)
+		for i := 0; i < nbSamples; i++ {
+			smallScalars[i].SetUint64(uint64(i + 1)).FromMont()
+			largeScalars[i].SetUint64(uint64(i + 1)).Neg(&largeScalars[i]).FromMont()
+		}
+		// Σ (r-i) P_i = - Σ i P_i
+		expected.MultiExp(samplePoints, smallScalars)
+		expected.Neg(&expected)
+
+		for c := uint64(minWindowSize); c <= 16; c++ {
+			table, err := NewG1MultiExpTable(samplePoints, c, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var result G1Jac
+			if _, err := result.MultiExpPrecomputed(table, largeScalars); err != nil {
+				t.Fatal(err)
+			}
+			if !result.Equal(&expected) {
+				t.Fatalf("multi exponentation with a precomputed table of scalars close to r is wrong with c = %d", c)
+			}
+		}
+	}
+
+	// invalid parameters
+	for _, c := range []uint64{2, 17} {
+		if _, err := NewG1MultiExpTable(samplePoints, c, 1); err != ErrInvalidWindowSize {
+			t.Fatal("expected ErrInvalidWindowSize")
+		}
+	}
+	if _, err := NewG1MultiExpTable(samplePoints, 4, 0); err != ErrInvalidStride {
+		t.Fatal("expected ErrInvalidStride")
+	}
+	var result G1Jac
+	if _, err := result.MultiExpPrecomputed(tables[0], make([]fr.Element, nbSamples-1)); err != ErrScalarsSizeMismatch {
+		t.Fatal("expected ErrScalarsSizeMismatch")
+	}
+
+	// serialization
+	for _, table := range tables[:2] {
+		for _, raw := range []bool{false, true} {
+			var buf bytes.Buffer
+			var err error
+			if raw {
+				_, err = table.WriteRawTo(&buf)
+			} else {
+				_, err = table.WriteTo(&buf)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded G1MultiExpTable
+			if _, err := decoded.ReadFrom(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if decoded.C != table.C || decoded.Stride != table.Stride || decoded.NbPoints != table.NbPoints {
+				t.Fatal("decoded table parameters don't match")
+			}
+			for i := range table.Points {
+				if !decoded.Points[i].Equal(&table.Points[i]) {
+					t.Fatal("decoded table points don't match")
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkMultiExpPrecomputedG1(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const nbSamples = 1 << 16
+
+	samplePoints := make([]G1Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1] = g1GenAff
+	}
+
+	var testPoint G1Affine
+
+	for _, stride := range []uint64{1, 4, 16} {
+		table, _ := NewG1MultiExpTable(samplePoints, 16, stride)
+		b.Run(fmt.Sprintf("c=16 stride=%d", stride), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				_, _ = testPoint.MultiExpPrecomputed(table, sampleScalars)
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpBatchAffineG1(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const pow = 20
+	const nbSamples = 1 << pow
+
+	// distinct points, as with equal points most of the additions into the affine buckets are doublings
+	samplePoints := make([]G1Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+
+	var testPoint G1Jac
+
+	for i := 14; i <= pow; i += 2 {
+		using := 1 << i
+		const c = 16
+		scalars := partitionScalars(sampleScalars[:using], c, runtime.NumCPU())
+
+		b.Run(fmt.Sprintf("%d points/msmC16", using), func(b *testing.B) {
+			opt := NewCPUSemaphore(runtime.NumCPU())
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				opt.lock.Lock()
+				testPoint.msmC16(samplePoints[:using], scalars, &msmScheduler{sem: opt})
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d points/batchAffine", using), func(b *testing.B) {
+			opt := NewCPUSemaphore(runtime.NumCPU())
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				opt.lock.Lock()
+				testPoint.msmBatchAffine(samplePoints[:using], scalars, c, fr.Limbs*64, &msmScheduler{sem: opt})
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpGLVG1(b *testing.B) {
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const nbSamples = 1 << 18
+
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+	sampleScalars := make([]fr.Element, nbSamples)
+	for i := 0; i < nbSamples; i++ {
+		sampleScalars[i].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i], &mixer).
+			FromMont()
+	}
+
+	for using := 1 << 10; using <= nbSamples; using *= 2 {
+		b.Run(fmt.Sprintf("%d points/full", using), func(b *testing.B) {
+			var testPoint G1Jac
+			C := msmBestCG1Affine(using, 0)
+			for j := 0; j < b.N; j++ {
+				testPoint.msm(samplePoints[:using], sampleScalars[:using], C, &msmScheduler{sem: NewCPUSemaphore(runtime.NumCPU())})
+			}
+		})
+		b.Run(fmt.Sprintf("%d points/glv", using), func(b *testing.B) {
+			var testPoint G1Jac
+			C := msmBestCGLVG1Affine(using, 0)
+			for j := 0; j < b.N; j++ {
+				testPoint.msmGLV(samplePoints[:using], sampleScalars[:using], C, &msmScheduler{sem: NewCPUSemaphore(runtime.NumCPU())})
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpUint64G1(b *testing.B) {
+	const nbSamples = 1 << 18
+
+	samplePoints := make([]G1Affine, nbSamples)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+
+	// booleans and bytes, as fr.Element scalars too
+	booleans := make([]uint64, nbSamples)
+	bytes := make([]uint64, nbSamples)
+	frBooleans := make([]fr.Element, nbSamples)
+	frBytes := make([]fr.Element, nbSamples)
+	for i := 0; i < nbSamples; i++ {
+		booleans[i] = uint64(i*7) % 5 & 1
+		bytes[i] = uint64(i*2654435761) & 0xff
+		frBooleans[i] = fr.Element{booleans[i]}
+		frBytes[i] = fr.Element{bytes[i]}
+	}
+
+	var testPoint G1Jac
+	for using := 1 << 10; using <= nbSamples; using *= 4 {
+		b.Run(fmt.Sprintf("%d booleans/fr", using), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], frBooleans[:using])
+			}
+		})
+		b.Run(fmt.Sprintf("%d booleans/uint64", using), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpUint64(samplePoints[:using], booleans[:using])
+			}
+		})
+		b.Run(fmt.Sprintf("%d bytes/fr", using), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], frBytes[:using])
+			}
+		})
+		b.Run(fmt.Sprintf("%d bytes/uint64", using), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpUint64(samplePoints[:using], bytes[:using])
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpG1(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const pow = (bits.UintSize / 2) - (bits.UintSize / 8) // 24 on 64 bits arch, 12 on 32 bits
+	const nbSamples = 1 << pow
+
+	var samplePoints [nbSamples]G1Affine
+	var sampleScalars [nbSamples]fr.Element
+
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1] = g1GenAff
+	}
+
+	var testPoint G1Affine
+
+	for i := 5; i <= pow; i++ {
+		using := 1 << i
+
+		b.Run(fmt.Sprintf("%d points", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], sampleScalars[:using])
+			}
+		})
+	}
+}
+
+func TestMultiExpG2(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 2
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	// size of the multiExps
+	const nbSamples = 143
+
+	// multi exp points
+	var samplePoints [nbSamples]G2Affine
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 1; i <= nbSamples; i++ {
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	// final scalar to use in double and add method (without mixer factor)
+	// n(n+1)(2n+1)/6  (sum of the squares from 1 to n)
+	var scalar big.Int
+	scalar.SetInt64(nbSamples)
+	scalar.Mul(&scalar, new(big.Int).SetInt64(nbSamples+1))
+	scalar.Mul(&scalar, new(big.Int).SetInt64(2*nbSamples+1))
+	scalar.Div(&scalar, new(big.Int).SetInt64(6))
+
+	if testing.Short() {
+		// we test only c = 5 and c = 16
+
+		properties.Property("[G2] Multi exponentation (c=5, c=16) should be consistant with sum of square", prop.ForAll(
+			func(mixer fr.Element) bool {
+
+				var r5, r16, expected G2Jac
+
+				// mixer ensures that all the words of a fpElement are set
+				var sampleScalars [nbSamples]fr.Element
+
+				for i := 1; i <= nbSamples; i++ {
+					sampleScalars[i-1].SetUint64(uint64(i)).
+						MulAssign(&mixer).
+						FromMont()
+				}
+
+				// semaphore to limit number of cpus
+				opt := NewCPUSemaphore(runtime.NumCPU())
+
+				opt.lock.Lock()
+				scalars := partitionScalars(sampleScalars[:], 5, runtime.NumCPU())
+				r5.msmC5(samplePoints[:], scalars, &msmScheduler{sem: opt})
+
+				opt.lock.Lock()
+				scalars = partitionScalars(sampleScalars[:], 16, runtime.NumCPU())
+				r16.msmC16(samplePoints[:], scalars, &msmScheduler{sem: opt})
+
+				// compute expected result with double and add
+				var finalScalar, mixerBigInt big.Int
+				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
+				expected.ScalarMultiplication(&g2Gen, &finalScalar)
+
+				return r5.Equal(&expected) && r16.Equal(&expected)
+			},
+			genScalar,
+		))
+	} else {
+
+		properties.Property("[G2] Multi exponentation (c=4) should be consistant with sum of square", prop.ForAll(
+			func(mixer fr.Element) bool {
+
+				var result, expected G2Jac
+
+				// mixer ensures that all the words of a fpElement are set
+				var sampleScalars [nbSamples]fr.Element
+
+				for i := 1; i <= nbSamples; i++ {
+					sampleScalars[i-1].SetUint64(uint64(i)).
+						MulAssign(&mixer).
+						FromMont()
+				}
+
+				// semaphore to limit number of cpus
+				opt := NewCPUSemaphore(runtime.NumCPU())
+				opt.lock.Lock()
+				scalars := partitionScalars(sampleScalars[:], 4, runtime.NumCPU())
+				result.msmC4(samplePoints[:], scalars, &msmScheduler{sem: opt})
+
+				// compute expected result with double and add
+				var finalScalar, mixerBigInt big.Int
+				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
+				expected.ScalarMultiplication(&g2Gen, &finalScalar)
+
+				return result.Equal(&expected)
+			},
+			genScalar,
+		))
+
+		properties.Property("[G2] Multi exponentation (c=5) should be consistant with sum of square", prop.ForAll(
+			func(mixer fr.Element) bool {
+
+				var result, expected G2Jac
+
+				// mixer ensures that all the words of a fpElement are set
+				var sampleScalars [nbSamples]fr.Element
+
+				for i := 1; i <= nbSamples; i++ {
+					sampleScalars[i-1].SetUint64(uint64(i)).
+						MulAssign(&mixer).
+						FromMont()
+				}
+
+				// semaphore to limit number of cpus
+				opt := NewCPUSemaphore(runtime.NumCPU())
+				opt.lock.Lock()
+				scalars := partitionScalars(sampleScalars[:], 5, runtime.NumCPU())
+				result.msmC5(samplePoints[:], scalars, &msmScheduler{sem: opt})
+
+				// compute expected result with double and add
+				var finalScalar, mixerBigInt big.Int
+				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
+				expected.ScalarMultiplication(&g2Gen, &finalScalar)
+
+				return result.Equal(&expected)
+			},
+			genScalar,
+		))
+
+		properties.Property("[G2] Multi exponentation (c=8) should be consistant with sum of square", prop.ForAll(
+			func(mixer fr.Element) bool {
+
+				var result, expected G2Jac
+
+				// mixer ensures that all the words of a fpElement are set
+				var sampleScalars [nbSamples]fr.Element
+
+				for i := 1; i <= nbSamples; i++ {
+					sampleScalars[i-1].SetUint64(uint64(i)).
+						MulAssign(&mixer).
+						FromMont()
+				}
+
+				// semaphore to limit number of cpus
+				opt := NewCPUSemaphore(runtime.NumCPU())
+				opt.lock.Lock()
+				scalars := partitionScalars(sampleScalars[:], 8, runtime.NumCPU())
+				result.msmC8(samplePoints[:], scalars, &msmScheduler{sem: opt})
+
+				// compute expected result with double and add
+				var finalScalar, mixerBigInt big.Int
+				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
+				expected.ScalarMultiplication(&g2Gen, &finalScalar)
+
+				return result.Equal(&expected)
+			},
+			genScalar,
+		))
+
+		properties.Property("[G2] Multi exponentation (c=16) should be consistant with sum of square", prop.ForAll(
+			func(mixer fr.Element) bool {
+
+				var result, expected G2Jac
+
+				// mixer ensures that all the words of a fpElement are set
+				var sampleScalars [nbSamples]fr.Element
+
+				for i := 1; i <= nbSamples; i++ {
+					sampleScalars[i-1].SetUint64(uint64(i)).
+						MulAssign(&mixer).
+						FromMont()
+				}
+
+				// semaphore to limit number of cpus
+				opt := NewCPUSemaphore(runtime.NumCPU())
+				opt.lock.Lock()
+				scalars := partitionScalars(sampleScalars[:], 16, runtime.NumCPU())
+				result.msmC16(samplePoints[:], scalars, &msmScheduler{sem: opt})
+
+				// compute expected result with double and add
+				var finalScalar, mixerBigInt big.Int
+				finalScalar.Mul(&scalar, mixer.ToBigIntRegular(&mixerBigInt))
+				expected.ScalarMultiplication(&g2Gen, &finalScalar)
+
+				return result.Equal(&expected)
+			},
+			genScalar,
+		))
+
+	}
+
+	// note : this test is here as we expect to have a different multiExp than the above bucket method
+	// for small number of points
+	properties.Property("[G2] Multi exponentation (<50points) should be consistant with sum of square", prop.ForAll(
+		func(mixer fr.Element) bool {
+
+			var g G2Jac
+			g.Set(&g2Gen)
+
+			// mixer ensures that all the words of a fpElement are set
+			samplePoints := make([]G2Affine, 30)
+			sampleScalars := make([]fr.Element, 30)
+
+			for i := 1; i <= 30; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+				samplePoints[i-1].FromJacobian(&g)
+				g.AddAssign(&g2Gen)
+			}
+
+			var op1MultiExp G2Affine
+			op1MultiExp.MultiExp(samplePoints, sampleScalars)
+
+			var finalBigScalar fr.Element
+			var finalBigScalarBi big.Int
+			var op1ScalarMul G2Affine
+			finalBigScalar.SetString("9455").MulAssign(&mixer)
 			finalBigScalar.ToBigIntRegular(&finalBigScalarBi)
 			op1ScalarMul.ScalarMultiplication(&g2GenAff, &finalBigScalarBi)
 
-			return op1ScalarMul.Equal(&op1MultiExp)
+			return op1ScalarMul.Equal(&op1MultiExp)
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestMultiExpBatchAffineG2(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 2
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	const nbSamples = 1 << 10
+
+	// few distinct points and their opposites, so that the buckets get doublings and cancellations,
+	// the last one being the point at infinity
+	var base [7]G2Affine
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < len(base); i++ {
+		base[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+	samplePoints := make([]G2Affine, nbSamples)
+	for i := 0; i < nbSamples-1; i++ {
+		samplePoints[i] = base[i%len(base)]
+		if (i/len(base))%2 == 1 {
+			samplePoints[i].Neg(&samplePoints[i])
+		}
+	}
+
+	properties.Property("[G2] Multi exponentation with batch affine buckets should be consistant with msmC16", prop.ForAll(
+		func(mixer fr.Element) bool {
+
+			// half of the scalars are small, so that the same points are added in the same buckets
+			sampleScalars := make([]fr.Element, nbSamples)
+			for i := 1; i <= nbSamples; i++ {
+				if i%2 == 0 {
+					sampleScalars[i-1].SetUint64(uint64(i%3 + 1))
+				} else {
+					sampleScalars[i-1].SetUint64(uint64(i)).
+						MulAssign(&mixer)
+				}
+				sampleScalars[i-1].FromMont()
+			}
+
+			var expected G2Jac
+			opt := NewCPUSemaphore(runtime.NumCPU())
+			opt.lock.Lock()
+			expected.msmC16(samplePoints, partitionScalars(sampleScalars, 16, runtime.NumCPU()), &msmScheduler{sem: opt})
+
+			for _, c := range []uint64{12, 13, 16} {
+				var result G2Jac
+				opt.lock.Lock()
+				result.msmBatchAffine(samplePoints, partitionScalars(sampleScalars, c, runtime.NumCPU()), c, fr.Limbs*64, &msmScheduler{sem: opt})
+				if !result.Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestMultiExpGLVG2(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 2
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	// size of the multiExps
+	const nbSamples = 143
+
+	// the last point is the point at infinity
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples-1; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	properties.Property("[G2] Multi exponentation with GLV split scalars should be consistant with msmC16", prop.ForAll(
+		func(mixer fr.Element) bool {
+
+			sampleScalars := make([]fr.Element, nbSamples)
+			for i := 0; i < nbSamples; i++ {
+				sampleScalars[i].SetUint64(uint64(i)).
+					MulAssign(&mixer)
+				mixer.Square(&mixer)
+			}
+			// edge cases: 0, 1, -1
+			sampleScalars[0].SetZero()
+			sampleScalars[1].SetOne()
+			sampleScalars[2].SetOne().Neg(&sampleScalars[2])
+			for i := 0; i < nbSamples; i++ {
+				sampleScalars[i].FromMont()
+			}
+
+			var expected G2Jac
+			opt := NewCPUSemaphore(runtime.NumCPU())
+			opt.lock.Lock()
+			expected.msmC16(samplePoints, partitionScalars(sampleScalars, 16, runtime.NumCPU()), &msmScheduler{sem: opt})
+
+			for _, c := range []uint64{msmMinC, 9, 13} {
+				var result G2Jac
+				result.msmGLV(samplePoints, sampleScalars, c, &msmScheduler{sem: opt})
+				if !result.Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// MultiExp switches to msmGLV on the numbers of points of msmUseGLVG2Affine
+	for _, nbPoints := range []int{1 << 10, 1 << 11} {
+		points := make([]G2Affine, nbPoints)
+		scalars := make([]fr.Element, nbPoints)
+		g.Set(&g2Gen)
+		for i := range points {
+			points[i].FromJacobian(&g)
+			g.AddAssign(&g2Gen)
+			scalars[i].SetRandom()
+		}
+		var result, expected G2Jac
+		result.MultiExp(points, scalars)
+		expected.msm(points, scalars, msmBestCG2Affine(nbPoints, 0), &msmScheduler{sem: NewCPUSemaphore(runtime.NumCPU())})
+		if !result.Equal(&expected) {
+			t.Fatalf("MultiExp of %d points (GLV: %v) doesn't match msm", nbPoints, msmUseGLVG2Affine(nbPoints))
+		}
+	}
+}
+
+func TestMultiExpContextG2(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 2
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	// size of the multiExps
+	const nbSamples = 143
+
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	sampleScalars := func(mixer fr.Element, n int) []fr.Element {
+		scalars := make([]fr.Element, n)
+		for i := 0; i < n; i++ {
+			scalars[i].SetUint64(uint64(i + 1)).
+				MulAssign(&mixer).
+				FromMont()
+			mixer.Square(&mixer)
+		}
+		return scalars
+	}
+
+	properties.Property("[G2] MultiExpContext should be consistant with MultiExp", prop.ForAll(
+		func(mixer fr.Element) bool {
+			scalars := sampleScalars(mixer, nbSamples)
+
+			var expected G2Jac
+			expected.MultiExp(samplePoints, scalars)
+
+			configs := []ecc.MultiExpConfig{
+				{},
+				{NbTasks: 3},
+				// a single window of c = 5 at a time
+				{NbTasks: 3, MaxBucketMemory: msmBucketMemoryG2Affine(5)},
+				// less memory than the buckets of a window of the smallest c
+				{NbTasks: 3, MaxBucketMemory: 1},
+				{GLV: true},
+				{NbTasks: 3, MaxBucketMemory: msmBucketMemoryG2Affine(5), GLV: true},
+			}
+			for _, config := range configs {
+				var result G2Jac
+				if _, err := result.MultiExpContext(context.Background(), samplePoints, scalars, config); err != nil {
+					return false
+				}
+				if !result.Equal(&expected) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// the memory bound is honored by the choice of c
+	maxBucketMemory := msmBucketMemoryG2Affine(8)
+	if c := msmBestCG2Affine(1<<22, maxBucketMemory); msmBucketMemoryG2Affine(c) > maxBucketMemory {
+		t.Fatal("the buckets of a window exceed MaxBucketMemory")
+	}
+
+	var mixer fr.Element
+	mixer.SetRandom()
+
+	// a cancelled multiExp returns ctx.Err() and leaves the result unchanged
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var result G2Jac
+	result.Set(&g2Gen)
+	if _, err := result.MultiExpContext(ctx, samplePoints, sampleScalars(mixer, nbSamples), ecc.MultiExpConfig{}); err != context.Canceled {
+		t.Fatal("a cancelled MultiExpContext should return context.Canceled")
+	}
+	if !result.Equal(&g2Gen) {
+		t.Fatal("a cancelled MultiExpContext should leave the result unchanged")
+	}
+
+	// the go routines of a large multiExp stop before the end of the computation
+	if !testing.Short() {
+		const nbPoints = 1 << 16
+		points := make([]G2Affine, nbPoints)
+		for i := 0; i < nbPoints; i++ {
+			points[i] = samplePoints[i%nbSamples]
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		if _, err := result.MultiExpContext(ctx, points, sampleScalars(mixer, nbPoints), ecc.MultiExpConfig{NbTasks: 2}); err != context.DeadlineExceeded {
+			t.Fatal("MultiExpContext should return context.DeadlineExceeded")
+		}
+	}
+}
+
+func TestMultiExpUint64G2(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 2
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	// size of the multiExps
+	const nbSamples = 143
+
+	// the last point is the point at infinity
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples-1; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	// expected computes the MultiExp with the corresponding fr.Element scalars
+	expected := func(scalars []uint64) G2Jac {
+		frScalars := make([]fr.Element, len(scalars))
+		for i := 0; i < len(scalars); i++ {
+			frScalars[i] = fr.Element{scalars[i]}
+		}
+		var res G2Jac
+		res.MultiExp(samplePoints, frScalars)
+		return res
+	}
+
+	properties.Property("[G2] MultiExpUint64 should be consistant with MultiExp", prop.ForAll(
+		func(mixer fr.Element) bool {
+
+			// booleans, bytes and 64-bit scalars
+			scalars := make([]uint64, nbSamples)
+			for i := 0; i < nbSamples; i++ {
+				mixer.Square(&mixer)
+				switch i % 3 {
+				case 0:
+					scalars[i] = mixer[0] & 1
+				case 1:
+					scalars[i] = mixer[0] & 0xff
+				default:
+					scalars[i] = mixer[0]
+				}
+			}
+
+			var booleans, bytes [nbSamples]uint64
+			for i := 0; i < nbSamples; i++ {
+				booleans[i] = scalars[i] & 1
+				bytes[i] = scalars[i] & 0xff
+			}
+
+			for _, s := range [][]uint64{scalars, booleans[:], bytes[:]} {
+				var result G2Jac
+				result.MultiExpUint64(samplePoints, s)
+				e := expected(s)
+				if !result.Equal(&e) {
+					return false
+				}
+			}
+			return true
+		},
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// only 0 and 1 scalars
+	var zeros, ones [nbSamples]uint64
+	for i := 0; i < nbSamples; i++ {
+		ones[i] = 1
+	}
+	for _, s := range [][]uint64{zeros[:], ones[:]} {
+		var result G2Jac
+		result.MultiExpUint64(samplePoints, s)
+		e := expected(s)
+		if !result.Equal(&e) {
+			t.Fatal("MultiExpUint64 with 0 and 1 scalars should be consistant with MultiExp")
+		}
+	}
+}
+
+func TestMultiExpPrecomputedG2(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 2
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	const nbSamples = 30
+
+	// multi exp points, the last one being the point at infinity
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples-1; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	// (c, stride) pairs, the last stride being larger than the number of windows
+	params := [][2]uint64{{4, 1}, {5, 3}, {16, 2}, {7, 1000}}
+	tables := make([]*G2MultiExpTable, len(params))
+	for i, p := range params {
+		var err error
+		if tables[i], err = NewG2MultiExpTable(samplePoints, p[0], p[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	properties.Property("[G2] Multi exponentation with a precomputed table should be consistant with MultiExp", prop.ForAll(
+		func(mixer fr.Element) bool {
+
+			// mixer ensures that all the words of a fpElement are set
+			sampleScalars := make([]fr.Element, nbSamples)
+			for i := 1; i <= nbSamples; i++ {
+				sampleScalars[i-1].SetUint64(uint64(i)).
+					MulAssign(&mixer).
+					FromMont()
+			}
+
+			var expected G2Jac
+			expected.MultiExp(samplePoints, sampleScalars)
+
+			for _, table := range tables {
+				var result G2Jac
+				if _, err := result.MultiExpPrecomputed(table, sampleScalars, NewCPUSemaphore(3)); err != nil {
+					return false
+				}
+				if !result.Equal(&expected) {
+					return false
+				}
+			}
+			return true
 		},
 		genScalar,
 	))
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// scalars close to r, whose highest digit is the largest, with every allowed c
+	{
+		var expected G2Jac
+		smallScalars := make([]fr.Element, nbSamples)
+		largeScalars := make([]fr.Element, nbSamples)
+		for i := 0; i < nbSamples; i++ {
+			smallScalars[i].SetUint64(uint64(i + 1)).FromMont()
+			largeScalars[i].SetUint64(uint64(i + 1)).Neg(&largeScalars[i]).FromMont()
+		}
+		// Σ (r-i) P_i = - Σ i P_i
+		expected.MultiExp(samplePoints, smallScalars)
+		expected.Neg(&expected)
+
+		for c := uint64(minWindowSize); c <= 16; c++ {
+			table, err := NewG2MultiExpTable(samplePoints, c, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var result G2Jac
+			if _, err := result.MultiExpPrecomputed(table, largeScalars); err != nil {
+				t.Fatal(err)
+			}
+			if !result.Equal(&expected) {
+				t.Fatalf("multi exponentation with a precomputed table of scalars close to r is wrong with c = %d", c)
+			}
+		}
+	}
+
+	// invalid parameters
+	for _, c := range []uint64{2, 17} {
+		if _, err := NewG2MultiExpTable(samplePoints, c, 1); err != ErrInvalidWindowSize {
+			t.Fatal("expected ErrInvalidWindowSize")
+		}
+	}
+	if _, err := NewG2MultiExpTable(samplePoints, 4, 0); err != ErrInvalidStride {
+		t.Fatal("expected ErrInvalidStride")
+	}
+	var result G2Jac
+	if _, err := result.MultiExpPrecomputed(tables[0], make([]fr.Element, nbSamples-1)); err != ErrScalarsSizeMismatch {
+		t.Fatal("expected ErrScalarsSizeMismatch")
+	}
+
+	// serialization
+	for _, table := range tables[:2] {
+		for _, raw := range []bool{false, true} {
+			var buf bytes.Buffer
+			var err error
+			if raw {
+				_, err = table.WriteRawTo(&buf)
+			} else {
+				_, err = table.WriteTo(&buf)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded G2MultiExpTable
+			if _, err := decoded.ReadFrom(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if decoded.C != table.C || decoded.Stride != table.Stride || decoded.NbPoints != table.NbPoints {
+				t.Fatal("decoded table parameters don't match")
+			}
+			for i := range table.Points {
+				if !decoded.Points[i].Equal(&table.Points[i]) {
+					t.Fatal("decoded table points don't match")
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkMultiExpPrecomputedG2(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const nbSamples = 1 << 16
+
+	samplePoints := make([]G2Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1] = g2GenAff
+	}
+
+	var testPoint G2Affine
+
+	for _, stride := range []uint64{1, 4, 16} {
+		table, _ := NewG2MultiExpTable(samplePoints, 16, stride)
+		b.Run(fmt.Sprintf("c=16 stride=%d", stride), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				_, _ = testPoint.MultiExpPrecomputed(table, sampleScalars)
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpBatchAffineG2(b *testing.B) {
+	// ensure every words of the scalars are filled
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const pow = 20
+	const nbSamples = 1 << pow
+
+	// distinct points, as with equal points most of the additions into the affine buckets are doublings
+	samplePoints := make([]G2Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 1; i <= nbSamples; i++ {
+		sampleScalars[i-1].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i-1], &mixer).
+			FromMont()
+		samplePoints[i-1].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	var testPoint G2Jac
+
+	for i := 14; i <= pow; i += 2 {
+		using := 1 << i
+		const c = 16
+		scalars := partitionScalars(sampleScalars[:using], c, runtime.NumCPU())
+
+		b.Run(fmt.Sprintf("%d points/msmC16", using), func(b *testing.B) {
+			opt := NewCPUSemaphore(runtime.NumCPU())
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				opt.lock.Lock()
+				testPoint.msmC16(samplePoints[:using], scalars, &msmScheduler{sem: opt})
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d points/batchAffine", using), func(b *testing.B) {
+			opt := NewCPUSemaphore(runtime.NumCPU())
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				opt.lock.Lock()
+				testPoint.msmBatchAffine(samplePoints[:using], scalars, c, fr.Limbs*64, &msmScheduler{sem: opt})
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpGLVG2(b *testing.B) {
+	var mixer fr.Element
+	mixer.SetString("7716837800905789770901243404444209691916730933998574719964609384059111546487")
+
+	const nbSamples = 1 << 18
+
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+	sampleScalars := make([]fr.Element, nbSamples)
+	for i := 0; i < nbSamples; i++ {
+		sampleScalars[i].SetUint64(uint64(i)).
+			Mul(&sampleScalars[i], &mixer).
+			FromMont()
+	}
+
+	for using := 1 << 10; using <= nbSamples; using *= 2 {
+		b.Run(fmt.Sprintf("%d points/full", using), func(b *testing.B) {
+			var testPoint G2Jac
+			C := msmBestCG2Affine(using, 0)
+			for j := 0; j < b.N; j++ {
+				testPoint.msm(samplePoints[:using], sampleScalars[:using], C, &msmScheduler{sem: NewCPUSemaphore(runtime.NumCPU())})
+			}
+		})
+		b.Run(fmt.Sprintf("%d points/glv", using), func(b *testing.B) {
+			var testPoint G2Jac
+			C := msmBestCGLVG2Affine(using, 0)
+			for j := 0; j < b.N; j++ {
+				testPoint.msmGLV(samplePoints[:using], sampleScalars[:using], C, &msmScheduler{sem: NewCPUSemaphore(runtime.NumCPU())})
+			}
+		})
+	}
+}
+
+func BenchmarkMultiExpUint64G2(b *testing.B) {
+	const nbSamples = 1 << 18
+
+	samplePoints := make([]G2Affine, nbSamples)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := 0; i < nbSamples; i++ {
+		samplePoints[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+
+	// booleans and bytes, as fr.Element scalars too
+	booleans := make([]uint64, nbSamples)
+	bytes := make([]uint64, nbSamples)
+	frBooleans := make([]fr.Element, nbSamples)
+	frBytes := make([]fr.Element, nbSamples)
+	for i := 0; i < nbSamples; i++ {
+		booleans[i] = uint64(i*7) % 5 & 1
+		bytes[i] = uint64(i*2654435761) & 0xff
+		frBooleans[i] = fr.Element{booleans[i]}
+		frBytes[i] = fr.Element{bytes[i]}
+	}
+
+	var testPoint G2Jac
+	for using := 1 << 10; using <= nbSamples; using *= 4 {
+		b.Run(fmt.Sprintf("%d booleans/fr", using), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], frBooleans[:using])
+			}
+		})
+		b.Run(fmt.Sprintf("%d booleans/uint64", using), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpUint64(samplePoints[:using], booleans[:using])
+			}
+		})
+		b.Run(fmt.Sprintf("%d bytes/fr", using), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], frBytes[:using])
+			}
+		})
+		b.Run(fmt.Sprintf("%d bytes/uint64", using), func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpUint64(samplePoints[:using], bytes[:using])
+			}
+		})
+	}
 }
 
 func BenchmarkMultiExpG2(b *testing.B) {
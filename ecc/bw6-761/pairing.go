@@ -16,6 +16,7 @@ package bw6761
 
 import (
 	"errors"
+	"io"
 	"sync"
 
 	"github.com/consensys/gnark-crypto/ecc/bw6-761/fp"
@@ -319,9 +320,223 @@ func MillerLoop(P []G1Affine, Q []G2Affine) (GT, error) {
 	return result, nil
 }
 
+// G2Prepared stores the lines of the Miller loops of a fixed G2 point, which don't
+// depend on the G1 point, so that they are computed once (see MillerLoopFixedQ)
+type G2Prepared struct {
+	q     G2Affine
+	lines []lineEvaluation
+}
+
+// NewG2Prepared computes the lines of the Miller loops of Q
+func NewG2Prepared(Q *G2Affine) *G2Prepared {
+	res := &G2Prepared{q: *Q}
+	if Q.IsInfinity() {
+		return res
+	}
+	res.lines = make([]lineEvaluation, 0, nbPreparedLines())
+
+	var xQ, Q1, QSaved, QNeg G2Jac
+	var l lineEvaluation
+	xQ.FromAffine(Q)
+	QSaved.FromAffine(Q)
+
+	// Miller loop part 1, as in preCompute1
+	for i := len(loopCounter1) - 2; i >= 0; i-- {
+		Q1.Set(&xQ)
+		xQ.Double(&Q1).Neg(&xQ)
+		lineCoefficients(&Q1, &xQ, &l)
+		res.lines = append(res.lines, l)
+		xQ.Neg(&xQ)
+
+		if loopCounter1[i] == 1 {
+			lineCoefficients(&xQ, &QSaved, &l)
+			res.lines = append(res.lines, l)
+			xQ.AddAssign(&QSaved)
+		}
+	}
+
+	// line through [x]Q and Q
+	lineCoefficients(&xQ, &QSaved, &l)
+	res.lines = append(res.lines, l)
+
+	// Miller loop part 2, as in preCompute2
+	QSaved.Set(&xQ)
+	QNeg.Neg(&xQ)
+	for i := len(loopCounter2) - 2; i >= 0; i-- {
+		Q1.Set(&xQ)
+		xQ.Double(&Q1).Neg(&xQ)
+		lineCoefficients(&Q1, &xQ, &l)
+		res.lines = append(res.lines, l)
+		xQ.Neg(&xQ)
+
+		if loopCounter2[i] == 1 {
+			lineCoefficients(&xQ, &QSaved, &l)
+			res.lines = append(res.lines, l)
+			xQ.AddAssign(&QSaved)
+		} else if loopCounter2[i] == -1 {
+			lineCoefficients(&xQ, &QNeg, &l)
+			res.lines = append(res.lines, l)
+			xQ.AddAssign(&QNeg)
+		}
+	}
+
+	return res
+}
+
+// nbPreparedLines returns the number of lines stored in a G2Prepared
+func nbPreparedLines() int {
+	n := 1
+	for i := len(loopCounter1) - 2; i >= 0; i-- {
+		n++
+		if loopCounter1[i] != 0 {
+			n++
+		}
+	}
+	for i := len(loopCounter2) - 2; i >= 0; i-- {
+		n++
+		if loopCounter2[i] != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// mulByLine multiplies result by the line l evaluated at p
+func mulByLine(result *GT, l *lineEvaluation, p *G1Affine) {
+	var e lineEvaluation
+	e.r0.Mul(&l.r0, &p.Y)
+	e.r1.Mul(&l.r1, &p.X)
+	e.r2.Set(&l.r2)
+	mulAssign(result, &e)
+}
+
+// MillerLoopFixedQ Miller loop, where the G2 points are given with their precomputed lines
+func MillerLoopFixedQ(P []G1Affine, Q []G2Prepared) (GT, error) {
+	n := len(P)
+	if n == 0 || n != len(Q) {
+		return GT{}, errors.New("invalid inputs sizes")
+	}
+
+	// filter infinity points
+	p := make([]G1Affine, 0, n)
+	q := make([]*G2Prepared, 0, n)
+
+	for k := 0; k < n; k++ {
+		if P[k].IsInfinity() || Q[k].q.IsInfinity() {
+			continue
+		}
+		if len(Q[k].lines) != nbPreparedLines() {
+			return GT{}, errors.New("invalid prepared G2 point")
+		}
+		p = append(p, P[k])
+		q = append(q, &Q[k])
+	}
+	n = len(p)
+
+	// Miller loop part 1
+	// computes f(P), div(f)=x(Q)-([x]Q)-(x-1)(O)
+	var result GT
+	result.SetOne()
+	j := 0
+	for i := len(loopCounter1) - 2; i >= 0; i-- {
+
+		result.Square(&result)
+		for k := 0; k < n; k++ {
+			mulByLine(&result, &q[k].lines[j], &p[k])
+		}
+		j++
+
+		if loopCounter1[i] != 0 {
+			for k := 0; k < n; k++ {
+				mulByLine(&result, &q[k].lines[j], &p[k])
+			}
+			j++
+		}
+	}
+
+	// see MillerLoop
+	var mx, mxInv, mxplusone GT
+	mx.Set(&result)
+	mxInv.Inverse(&result)
+	mxplusone.Set(&mx)
+
+	for k := 0; k < n; k++ {
+		mulByLine(&mxplusone, &q[k].lines[j], &p[k])
+	}
+	j++
+
+	// Miller loop part 2
+	for i := len(loopCounter2) - 2; i >= 0; i-- {
+
+		result.Square(&result)
+		for k := 0; k < n; k++ {
+			mulByLine(&result, &q[k].lines[j], &p[k])
+		}
+		j++
+
+		if loopCounter2[i] == 1 {
+			for k := 0; k < n; k++ {
+				mulByLine(&result, &q[k].lines[j], &p[k])
+			}
+			result.MulAssign(&mx)
+			j++
+		} else if loopCounter2[i] == -1 {
+			for k := 0; k < n; k++ {
+				mulByLine(&result, &q[k].lines[j], &p[k])
+			}
+			result.MulAssign(&mxInv)
+			j++
+		}
+	}
+
+	result.Frobenius(&result).MulAssign(&mxplusone)
+
+	return result, nil
+}
+
+// PairingCheckFixedQ calculates the reduced pairing for a set of points, where the G2 points
+// are given with their precomputed lines, and returns True if the result is One
+func PairingCheckFixedQ(P []G1Affine, Q []G2Prepared) (bool, error) {
+	f, err := MillerLoopFixedQ(P, Q)
+	if err != nil {
+		return false, err
+	}
+	f = FinalExponentiation(&f)
+	var one GT
+	one.SetOne()
+	return f.Equal(&one), nil
+}
+
+// WriteTo writes the binary encoding of the prepared point: the point only,
+// as the lines are computed again from it by ReadFrom
+func (q *G2Prepared) WriteTo(w io.Writer) (int64, error) {
+	enc := NewEncoder(w)
+	err := enc.Encode(&q.q)
+	return enc.BytesWritten(), err
+}
+
+// ReadFrom decodes a prepared point written with WriteTo, and computes its lines
+func (q *G2Prepared) ReadFrom(r io.Reader) (int64, error) {
+	dec := NewDecoder(r)
+	if err := dec.Decode(&q.q); err != nil {
+		return dec.BytesRead(), err
+	}
+	*q = *NewG2Prepared(&q.q)
+	return dec.BytesRead(), nil
+}
+
 // lineEval computes the evaluation of the line through Q, R (on the twist) at P
 // Q, R are in jacobian coordinates
 func lineEval(Q, R *G2Jac, P *G1Affine, result *lineEvaluation) {
+	lineCoefficients(Q, R, result)
+	result.r1.Mul(&result.r1, &P.X)
+	result.r0.Mul(&result.r0, &P.Y)
+}
+
+// lineCoefficients computes the coefficients of the line through Q, R (on the twist),
+// before its evaluation at a point P (r1 and r0 are to be multiplied by P.X and P.Y)
+// Q, R are in jacobian coordinates
+func lineCoefficients(Q, R *G2Jac, result *lineEvaluation) {
 
 	// converts _Q and _R to projective coords
 	var _Q, _R g2Proj
@@ -339,9 +554,6 @@ func lineEval(Q, R *G2Jac, P *G1Affine, result *lineEvaluation) {
 	result.r1.Sub(&result.r1, &_Q.z)
 	result.r0.Sub(&result.r0, &_Q.x)
 	result.r2.Sub(&result.r2, &_Q.y)
-
-	result.r1.Mul(&result.r1, &P.X)
-	result.r0.Mul(&result.r0, &P.Y)
 }
 
 func mulAssign(z *GT, l *lineEvaluation) *GT {
@@ -15,6 +15,7 @@
 package bw6761
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 
@@ -211,6 +212,94 @@ func TestPairing(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+func TestMillerLoopFixedQ(t *testing.T) {
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 10
+
+	properties := gopter.NewProperties(parameters)
+
+	genR1 := GenFr()
+	genR2 := GenFr()
+
+	properties.Property("[BW6-761] MillerLoopFixedQ should be equal to MillerLoop", prop.ForAll(
+		func(a, b fr.Element) bool {
+
+			var ag1, infG1 G1Affine
+			var bg2, infG2 G2Affine
+
+			var abigint, bbigint big.Int
+
+			a.ToBigIntRegular(&abigint)
+			b.ToBigIntRegular(&bbigint)
+
+			ag1.ScalarMultiplication(&g1GenAff, &abigint)
+			bg2.ScalarMultiplication(&g2GenAff, &bbigint)
+
+			tabP := []G1Affine{g1GenAff, ag1, infG1, ag1}
+			tabQ := []G2Affine{bg2, g2GenAff, bg2, infG2}
+			prepared := make([]G2Prepared, len(tabQ))
+			for i := range tabQ {
+				prepared[i] = *NewG2Prepared(&tabQ[i])
+			}
+
+			expected, _ := MillerLoop(tabP, tabQ)
+			res, err := MillerLoopFixedQ(tabP, prepared)
+
+			return err == nil && res.Equal(&expected)
+		},
+		genR1,
+		genR2,
+	))
+
+	properties.Property("[BW6-761] PairingCheckFixedQ", prop.ForAll(
+		func(a fr.Element) bool {
+
+			var ag1, ag1Neg G1Affine
+			var abigint big.Int
+			a.ToBigIntRegular(&abigint)
+			ag1.ScalarMultiplication(&g1GenAff, &abigint)
+			ag1Neg.Neg(&ag1)
+
+			prepared := []G2Prepared{*NewG2Prepared(&g2GenAff), *NewG2Prepared(&g2GenAff)}
+
+			res, _ := PairingCheckFixedQ([]G1Affine{ag1, ag1Neg}, prepared)
+			wrong, _ := PairingCheckFixedQ([]G1Affine{ag1, ag1}, prepared)
+
+			return res && !wrong
+		},
+		genR1,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// serialization
+	for _, q := range []G2Affine{g2GenAff, {}} {
+		prepared := NewG2Prepared(&q)
+		var buf bytes.Buffer
+		written, err := prepared.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != SizeOfG2AffineCompressed {
+			t.Fatal("a G2Prepared should be encoded as its point only")
+		}
+		var decoded G2Prepared
+		read, err := decoded.ReadFrom(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != read || !decoded.q.Equal(&prepared.q) || len(decoded.lines) != len(prepared.lines) {
+			t.Fatal("decode(encode(G2Prepared)) failed")
+		}
+		for i := range prepared.lines {
+			if decoded.lines[i] != prepared.lines[i] {
+				t.Fatal("decode(encode(G2Prepared)) failed")
+			}
+		}
+	}
+}
+
 // ------------------------------------------------------------
 // benches
 
@@ -243,6 +332,16 @@ func BenchmarkMillerLoop(b *testing.B) {
 	}
 }
 
+func BenchmarkMillerLoopFixedQ(b *testing.B) {
+
+	prepared := []G2Prepared{*NewG2Prepared(&g2GenAff)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MillerLoopFixedQ([]G1Affine{g1GenAff}, prepared)
+	}
+}
+
 func BenchmarkFinalExponentiation(b *testing.B) {
 
 	var a GT
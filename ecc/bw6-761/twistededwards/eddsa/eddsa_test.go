@@ -18,6 +18,7 @@ package eddsa
 
 import (
 	"crypto/sha256"
+	gohash "hash"
 	"math/rand"
 	"testing"
 
@@ -152,8 +153,99 @@ func TestEddsaSHA256(t *testing.T) {
 
 }
 
+// newBatch returns n key pairs, messages and signatures
+func newBatch(tb testing.TB, n int, hFunc gohash.Hash) ([]PublicKey, [][]byte, [][]byte) {
+	r := rand.New(rand.NewSource(0))
+	pubs := make([]PublicKey, n)
+	msgs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		pubs[i] = privKey.PublicKey
+		var frMsg fr.Element
+		frMsg.SetUint64(uint64(i))
+		msgBin := frMsg.Bytes()
+		msgs[i] = msgBin[:]
+		if sigs[i], err = privKey.Sign(msgs[i], hFunc); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return pubs, sigs, msgs
+}
+
+func TestBatchVerify(t *testing.T) {
+
+	hFunc := hash.MIMC_BW6_761.New("seed")
+	const n = 11
+	pubs, sigs, msgs := newBatch(t, n, hFunc)
+
+	// all signatures are valid
+	ok, invalid, err := BatchVerify(pubs, sigs, msgs, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || len(invalid) != 0 {
+		t.Fatal("BatchVerify of valid signatures should return true")
+	}
+
+	// wrong message, swapped signatures, and malformed signature
+	msgs[2] = msgs[3]
+	sigs[5], sigs[6] = sigs[6], sigs[5]
+	sigs[9] = sigs[9][:len(sigs[9])-1]
+	ok, invalid, err = BatchVerify(pubs, sigs, msgs, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{2, 5, 6, 9}
+	if ok || len(invalid) != len(expected) {
+		t.Fatalf("BatchVerify should return the invalid signatures %v, got %v", expected, invalid)
+	}
+	for i := range expected {
+		if invalid[i] != expected[i] {
+			t.Fatalf("BatchVerify should return the invalid signatures %v, got %v", expected, invalid)
+		}
+	}
+
+	// consistency with Verify
+	for i := 0; i < n; i++ {
+		res, _ := pubs[i].Verify(sigs[i], msgs[i], hFunc)
+		isInvalid := i == 2 || i == 5 || i == 6 || i == 9
+		if res == isInvalid {
+			t.Fatalf("Verify and BatchVerify mismatch for signature %d", i)
+		}
+	}
+
+	if _, _, err := BatchVerify(pubs, sigs[1:], msgs, hFunc); err == nil {
+		t.Fatal("BatchVerify should fail if the sizes mismatch")
+	}
+}
+
 // benchmarks
 
+func BenchmarkSign(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src)
+
+	hFunc := hash.MIMC_BW6_761.New("seed")
+
+	privKey, err := signature.EDDSA_BW6_761.New(r)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var frMsg fr.Element
+	frMsg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035978")
+	msgBin := frMsg.Bytes()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign(msgBin[:], hFunc)
+	}
+}
+
 func BenchmarkVerify(b *testing.B) {
 
 	src := rand.NewSource(0)
@@ -177,3 +269,23 @@ func BenchmarkVerify(b *testing.B) {
 		pubKey.Verify(signature, msgBin[:], hFunc)
 	}
 }
+
+func BenchmarkBatchVerify(b *testing.B) {
+
+	hFunc := hash.MIMC_BW6_761.New("seed")
+	const n = 64
+	pubs, sigs, msgs := newBatch(b, n, hFunc)
+
+	b.Run("individual", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < n; j++ {
+				pubs[j].Verify(sigs[j], msgs[j], hFunc)
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchVerify(pubs, sigs, msgs, hFunc)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+)
+
+// Z parameter of the Elligator 2 map, the non-square of smallest absolute value in fr
+// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-H.3
+var elligator2Z fr.Element
+
+func init() {
+	elligator2Z.SetString("5")
+}
+
+// hashToFr hashes msg to count scalar field elements.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.2
+func hashToFr(msg, dst []byte, count int) ([]fr.Element, error) {
+
+	// 128 bits of security
+	// L = ceil((ceil(log2(r)) + k) / 8), where k is the security parameter = 128
+	const L = (fr.Bits + 128 + 7) / 8
+
+	lenInBytes := count * L
+	pseudoRandomBytes, err := ecc.ExpandMsgXmd(msg, dst, lenInBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]fr.Element, count)
+	for i := 0; i < count; i++ {
+		res[i].SetBytes(pseudoRandomBytes[i*L : (i+1)*L])
+	}
+	return res, nil
+}
+
+// sgn0 returns the parity of u
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-4.1
+func sgn0(u fr.Element) uint64 {
+	u.FromMont()
+	return u[0] & 1
+}
+
+// elligator2Map maps u to a point on the twisted Edwards curve, which is not necessarily
+// in the prime order subgroup.
+//
+// It is the Elligator 2 map to the birationally equivalent Montgomery curve K*t^2 = s^3 + J*s^2 + s,
+// where J = 2(a+d)/(a-d) and K = 4/(a-d), followed by the rational map (x, y) = (s/t, (s-1)/(s+1)).
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-6.7.1
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-6.8.2
+func elligator2Map(u fr.Element) PointAffine {
+
+	// Elligator 2 on the curve y^2 = x^3 + A*x^2 + B*x, where A = J/K = (a+d)/2 and B = 1/K^2 = ((a-d)/4)^2,
+	// the point (s, t) on the Montgomery curve is then (x*K, y*K)
+	var A, B, aMinusD, tmp fr.Element
+	tmp.SetUint64(2).Inverse(&tmp)
+	A.Add(&edwards.A, &edwards.D).Mul(&A, &tmp)
+	aMinusD.Sub(&edwards.A, &edwards.D)
+	B.Square(&tmp).Mul(&B, &aMinusD).Square(&B)
+
+	// x1 = -A / (1 + Z*u^2), or -A if the denominator is 0
+	var x1, x2, gx1, gx2, one fr.Element
+	one.SetOne()
+	tmp.Square(&u).Mul(&tmp, &elligator2Z).Add(&tmp, &one)
+	x1.Neg(&A)
+	if !tmp.IsZero() {
+		x1.Div(&x1, &tmp)
+	}
+
+	// gx1 = x1^3 + A*x1^2 + B*x1 = x1*(x1*(x1 + A) + B)
+	gx1.Add(&x1, &A).Mul(&gx1, &x1).Add(&gx1, &B).Mul(&gx1, &x1)
+
+	// x2 = -x1 - A
+	x2.Add(&x1, &A).Neg(&x2)
+
+	// if gx1 is a square, (x, y) = (x1, sqrt(gx1)) with sgn0(y) = 1
+	// otherwise, (x, y) = (x2, sqrt(gx2)) with sgn0(y) = 0
+	var x, y fr.Element
+	if gx1.Legendre() != -1 {
+		x.Set(&x1)
+		y.Sqrt(&gx1)
+		if sgn0(y) != 1 {
+			y.Neg(&y)
+		}
+	} else {
+		gx2.Add(&x2, &A).Mul(&gx2, &x2).Add(&gx2, &B).Mul(&gx2, &x2)
+		x.Set(&x2)
+		y.Sqrt(&gx2)
+		if sgn0(y) != 0 {
+			y.Neg(&y)
+		}
+	}
+
+	// (s/t, (s-1)/(s+1)) = (x/y, (4x - (a-d)) / (4x + (a-d))), with a single inversion
+	// the exceptional cases t = 0 and s = -1 are mapped to the identity
+	var num, den, inv fr.Element
+	tmp.Double(&x).Double(&tmp)
+	num.Sub(&tmp, &aMinusD)
+	den.Add(&tmp, &aMinusD)
+
+	var res PointAffine
+	inv.Mul(&y, &den)
+	if inv.IsZero() {
+		res.X.SetZero()
+		res.Y.SetOne()
+		return res
+	}
+	inv.Inverse(&inv)
+	res.X.Mul(&x, &den).Mul(&res.X, &inv)
+	res.Y.Mul(&num, &y).Mul(&res.Y, &inv)
+
+	return res
+}
+
+// MapToCurve maps an fr.Element to a point of the prime order subgroup, using the Elligator 2 map
+// followed by the multiplication by the cofactor
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-6.7.1
+func MapToCurve(u fr.Element) PointAffine {
+	res := elligator2Map(u)
+	var _res PointExtended
+	_res.FromAffine(&res)
+	_res.ClearCofactor(&_res)
+	res.FromExtended(&_res)
+	return res
+}
+
+// EncodeToCurve hashes msg to a point of the prime order subgroup, using the Elligator 2 map.
+// Its output is not uniformly distributed, see HashToCurve.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-3
+func EncodeToCurve(msg, dst []byte) (PointAffine, error) {
+	var res PointAffine
+	u, err := hashToFr(msg, dst, 1)
+	if err != nil {
+		return res, err
+	}
+	res = MapToCurve(u[0])
+	return res, nil
+}
+
+// HashToCurve hashes msg to a point of the prime order subgroup, using the Elligator 2 map.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-3
+func HashToCurve(msg, dst []byte) (PointAffine, error) {
+	var res PointAffine
+	u, err := hashToFr(msg, dst, 2)
+	if err != nil {
+		return res, err
+	}
+	Q0 := MapToCurve(u[0])
+	Q1 := MapToCurve(u[1])
+	var _res PointExtended
+	_res.FromAffine(&Q0)
+	_res.MixedAdd(&_res, &Q1)
+	res.FromExtended(&_res)
+	return res, nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+)
+
+func TestElligator2Map(t *testing.T) {
+
+	if elligator2Z.Legendre() != -1 {
+		t.Fatal("Z should not be a square")
+	}
+
+	// the denominator 1 + Z*u^2 of x1 never vanishes, as -1/Z is not a square (-1 is a square in fr)
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne)
+	if minusOne.Legendre() != 1 {
+		t.Fatal("-1 should be a square")
+	}
+
+	// u = 0 is mapped with x1 = -A
+	var zero fr.Element
+	inputs := []fr.Element{zero}
+	for i := 0; i < 20; i++ {
+		var u fr.Element
+		u.SetRandom()
+		inputs = append(inputs, u)
+	}
+
+	for _, u := range inputs {
+		p := elligator2Map(u)
+		if !p.IsOnCurve() {
+			t.Fatalf("elligator2Map(%s) is not on the curve", u.String())
+		}
+
+		// u and -u are mapped to the same point, as only u^2 is used to select x
+		var v fr.Element
+		v.Neg(&u)
+		if q := elligator2Map(v); !q.Equal(&p) {
+			t.Fatal("elligator2Map(u) and elligator2Map(-u) should be equal")
+		}
+	}
+}
+
+func TestHashToCurve(t *testing.T) {
+
+	ed := GetEdwardsCurve()
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+	for i := 0; i < 10; i++ {
+		msg := []byte(fmt.Sprintf("msg %d", i))
+
+		for _, hashToCurve := range []func(msg, dst []byte) (PointAffine, error){HashToCurve, EncodeToCurve} {
+			p, err := hashToCurve(msg, dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !p.IsOnCurve() {
+				t.Fatal("hash to curve output should be on the curve")
+			}
+
+			// the output is in the prime order subgroup
+			var pProj, q PointProj
+			pProj.FromAffine(&p)
+			if scalarMulNaive(&q, &pProj, &ed.Order); !q.IsZero() || pProj.IsZero() {
+				t.Fatal("hash to curve output should be in the prime order subgroup")
+			}
+
+			// the output is deterministic, and depends on the message and on the domain separation tag
+			p2, _ := hashToCurve(msg, dst)
+			p3, _ := hashToCurve(append(msg, 0), dst)
+			p4, _ := hashToCurve(msg, []byte("another DST"))
+			if !p.Equal(&p2) || p.Equal(&p3) || p.Equal(&p4) {
+				t.Fatal("hash to curve output should depend only on the message and on the domain separation tag")
+			}
+		}
+	}
+
+	// EncodeToCurve(msg) = MapToCurve(u), with u = hash_to_field(msg)
+	u, err := hashToFr([]byte("abc"), dst, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MapToCurve(u[0])
+	p, _ := EncodeToCurve([]byte("abc"), dst)
+	if !p.Equal(&expected) {
+		t.Fatal("EncodeToCurve and MapToCurve mismatch")
+	}
+
+	// HashToCurve(msg) = MapToCurve(u0) + MapToCurve(u1), with u0, u1 = hash_to_field(msg)
+	if u, err = hashToFr([]byte("abc"), dst, 2); err != nil {
+		t.Fatal(err)
+	}
+	q0, q1 := MapToCurve(u[0]), MapToCurve(u[1])
+	expected.Add(&q0, &q1)
+	p, _ = HashToCurve([]byte("abc"), dst)
+	if !p.Equal(&expected) {
+		t.Fatal("HashToCurve and MapToCurve mismatch")
+	}
+}
+
+// TestHashToCurveVectors checks hash_to_field, the Elligator 2 map and HashToCurve against known answers,
+// computed with an independent implementation of the generic steps of RFC 9380 (expand_message_xmd
+// with SHA-256, hash_to_field, map_to_curve_elligator2 on the Montgomery curve, the rational map of
+// appendix D and clear_cofactor). q0 and q1 are the outputs of the map before the cofactor is cleared.
+func TestHashToCurveVectors(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+
+	vectors := []struct {
+		msg       string
+		u0, u1    string
+		q0, q1, p [2]string
+	}{
+		{
+			msg: "",
+			u0:  "166259215324126082533871391593745104770085046164814597255882615574189773822337001731186733828759763574451021766058",
+			u1:  "40011726552392195533392929528895375353632358873806451214703191223404722976632984081831108974726767629949216477780",
+			q0:  [2]string{"218788913374462187116715934241919628538748368852605786478972977962994650857286101117288316271441517257673612742875", "179523924059851764141830103992851739088744525109195174850738315018752400438712951103992704539797001097458493999504"},
+			q1:  [2]string{"236504665216644539783637008928345431502709458039374508097339462833434115885079326317722446397534806578685990454913", "162221303360411611384226974346862190858151437196378565423618495151657073432598286620620429869985640846495451257607"},
+			p:   [2]string{"127981725380297920476045139993275513137470318554055935528138418220783544932452070335533406888543400978599319030878", "127389880419562157066923315218340318860876985877268010757802691373461743188060000400312277827215213010938435151182"},
+		},
+		{
+			msg: "abc",
+			u0:  "175467995399050580575452925960430452499183433726796576129528938830180786879255415974449734409930530686752553871191",
+			u1:  "13454217281444329947014034908163296130429001270293599739241793979740348489932044144227708575904228385330661052940",
+			q0:  [2]string{"34265769965864196693261459906760541580319232520930380803959763984160412803150638087887290343354054705483930381614", "112302098041256146445834477009829634452964917773102187369485629579969305214878874695779470950521720238865251349801"},
+			q1:  [2]string{"57124808287645035551402119228841710284211766618463425596446341856772580086220758409184114797123264573235635930387", "251431736003822430827202783888724007679235478645684238516573376817226776581959726328595775154400338249616647400907"},
+			p:   [2]string{"48393727531330597328475527702037604165832601744476501599107688627524455581449273781540006486171712351126160274858", "108933211675680685609259541553436079272423536339712492318138819277949952579060218560296022980562321891152291283842"},
+		},
+		{
+			msg: "abcdef0123456789",
+			u0:  "118585404267661332095641918763799778642481499926883251985829115871921008017922383013770467967194008565156084409888",
+			u1:  "88695273797548584864407980104853024951928222196683325406498784041977419804167549239165314147303615332991579306449",
+			q0:  [2]string{"152902653098065478910130022320699579823862560403756141858767398003866149643294637272196006381410963534448240434560", "245146442150930517739974685146266252000965993221509244047292444832536481938649562493257902368849562740666319082318"},
+			q1:  [2]string{"25278369381776547600179827174502545450293183329032000402845394437652716172723508818462931982910847613038414781992", "168315924874437762362659476500949823655584834766192244876999209556670218126246591754869959306181849209297917108096"},
+			p:   [2]string{"5646770909738585205913667368316834705824106060295705223197831050246980916555613336786593024754869700950165798143", "177874399245848808562419572429726031836022089187507411659525686283975461329161188209476817488110170080976603416658"},
+		},
+	}
+
+	for _, v := range vectors {
+		u, err := hashToFr([]byte(v.msg), dst, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var u0, u1 fr.Element
+		u0.SetString(v.u0)
+		u1.SetString(v.u1)
+		if !u[0].Equal(&u0) || !u[1].Equal(&u1) {
+			t.Fatalf("hashToFr(%q) mismatch", v.msg)
+		}
+
+		var q0, q1, expected PointAffine
+		q0.X.SetString(v.q0[0])
+		q0.Y.SetString(v.q0[1])
+		q1.X.SetString(v.q1[0])
+		q1.Y.SetString(v.q1[1])
+		if p := elligator2Map(u0); !p.Equal(&q0) {
+			t.Fatalf("elligator2Map(u0) mismatch for msg %q", v.msg)
+		}
+		if p := elligator2Map(u1); !p.Equal(&q1) {
+			t.Fatalf("elligator2Map(u1) mismatch for msg %q", v.msg)
+		}
+
+		p, err := HashToCurve([]byte(v.msg), dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected.X.SetString(v.p[0])
+		expected.Y.SetString(v.p[1])
+		if !p.Equal(&expected) {
+			t.Fatalf("HashToCurve(%q) mismatch", v.msg)
+		}
+	}
+}
+
+func BenchmarkHashToCurve(b *testing.B) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	msg := []byte("abc")
+	b.Run("EncodeToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			EncodeToCurve(msg, dst)
+		}
+	})
+	b.Run("HashToCurve", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			HashToCurve(msg, dst)
+		}
+	})
+}
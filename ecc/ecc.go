@@ -44,3 +44,15 @@ func (id ID) String() string {
 		panic("unimplemented ecc ID")
 	}
 }
+
+// MultiExpConfig enables to set optional configuration attribute to a call to MultiExpContext
+type MultiExpConfig struct {
+	NbTasks         int  // go routines to be used in the multiexp. can be larger than num cpus. (0 means runtime.NumCPU())
+	MaxBucketMemory int  // upper bound, in bytes, of the buckets allocated at the same time. (0 means no bound)
+	GLV             bool // split the scalars with the GLV endomorphism, whatever the number of points. (by default, only where it is faster)
+}
+
+// FFTConfig enables to set optional configuration attribute to a call to FFTContext or FFTInverseContext
+type FFTConfig struct {
+	NbTasks int // go routines to be used in the fft. (0 means runtime.NumCPU())
+}
@@ -146,6 +146,48 @@ func SplitScalar(s *big.Int, l *Lattice) [2]big.Int {
 	return v
 }
 
+// ScalarSplitter splits scalars as SplitScalar does, without allocating memory:
+// the divisions by the determinant of the lattice are replaced by multiplications
+// by precomputed approximations.
+//
+// A ScalarSplitter is not safe for concurrent use.
+type ScalarSplitter struct {
+	l      *Lattice
+	shift  uint    // precision of the approximations
+	b1, b2 big.Int // closest integers from 2^shift*V2[1]/Det and -2^shift*V1[1]/Det
+	half   big.Int // 2^(shift-1)
+
+	k1, k2, tmp big.Int
+}
+
+// NewScalarSplitter returns a ScalarSplitter for the scalars in [0, |l.Det|)
+func NewScalarSplitter(l *Lattice) *ScalarSplitter {
+	sp := &ScalarSplitter{l: l}
+
+	// the error on s*b1/2^shift is less than s/2^(shift+1), smaller than the distance
+	// from s*V2[1]/Det to a half-integer: the rounding matches the one of SplitScalar
+	sp.shift = uint(2*l.Det.BitLen() + 2)
+	sp.b1.Lsh(&l.V2[1], sp.shift)
+	rounding(&sp.b1, &l.Det, &sp.b1)
+	sp.b2.Lsh(&l.V1[1], sp.shift).Neg(&sp.b2)
+	rounding(&sp.b2, &l.Det, &sp.b2)
+	sp.half.SetUint64(1).Lsh(&sp.half, sp.shift-1)
+
+	return sp
+}
+
+// Split sets u, v such that u+vlambda=s[r], as SplitScalar does, for s in [0, |Det|)
+func (sp *ScalarSplitter) Split(s, u, v *big.Int) {
+	sp.k1.Mul(s, &sp.b1).Add(&sp.k1, &sp.half).Rsh(&sp.k1, sp.shift)
+	sp.k2.Mul(s, &sp.b2).Add(&sp.k2, &sp.half).Rsh(&sp.k2, sp.shift)
+
+	// (u, v) = (s, 0) - (k1*V1 + k2*V2)
+	sp.tmp.Mul(&sp.k2, &sp.l.V2[0])
+	u.Mul(&sp.k1, &sp.l.V1[0]).Add(u, &sp.tmp).Sub(s, u)
+	sp.tmp.Mul(&sp.k2, &sp.l.V2[1])
+	v.Mul(&sp.k1, &sp.l.V1[1]).Add(v, &sp.tmp).Neg(v)
+}
+
 // sets res to the closest integer from n/d
 func rounding(n, d, res *big.Int) {
 	var dshift, r, one big.Int
@@ -223,7 +265,7 @@ func ExpandMsgXmd(msg, dst []byte, lenInBytes int) ([]byte, error) {
 	b1 := h.Sum(nil)
 
 	res := make([]byte, lenInBytes)
-	copy(res[:h.Size()], b1)
+	copy(res, b1)
 
 	for i := 2; i <= ell; i++ {
 		// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
@@ -245,7 +287,7 @@ func ExpandMsgXmd(msg, dst []byte, lenInBytes int) ([]byte, error) {
 			return nil, err
 		}
 		b1 = h.Sum(nil)
-		copy(res[h.Size()*(i-1):h.Size()*i], b1)
+		copy(res[h.Size()*(i-1):], b1)
 	}
 	return res, nil
 }
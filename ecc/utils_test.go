@@ -1,6 +1,8 @@
 package ecc
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"math/big"
 	"testing"
 )
@@ -41,6 +43,37 @@ func TestSplitting(t *testing.T) {
 
 }
 
+func TestScalarSplitter(t *testing.T) {
+
+	var lambda, r big.Int
+	var l Lattice
+
+	r.SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	lambda.SetString("4407920970296243842393367215006156084916469457145843978461", 10)
+
+	PrecomputeLattice(&r, &lambda, &l)
+	sp := NewScalarSplitter(&l)
+
+	var u, v big.Int
+	for i := 0; i < 1000; i++ {
+		s, err := rand.Int(rand.Reader, &r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			s.SetUint64(0)
+		}
+		if i == 1 {
+			s.Sub(&r, big.NewInt(1))
+		}
+		expected := SplitScalar(s, &l)
+		sp.Split(s, &u, &v)
+		if u.Cmp(&expected[0]) != 0 || v.Cmp(&expected[1]) != 0 {
+			t.Fatal("ScalarSplitter doesn't match SplitScalar")
+		}
+	}
+}
+
 func BenchmarkSplitting256(b *testing.B) {
 
 	var lambda, r, s big.Int
@@ -51,9 +84,42 @@ func BenchmarkSplitting256(b *testing.B) {
 	PrecomputeLattice(&r, &lambda, &l)
 	s.SetString("183927522224640574525727508854836440041603434369820418657580", 10)
 
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		SplitScalar(&s, &l)
-	}
+	b.Run("SplitScalar", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			SplitScalar(&s, &l)
+		}
+	})
+
+	b.Run("ScalarSplitter", func(b *testing.B) {
+		sp := NewScalarSplitter(&l)
+		var u, v big.Int
+		for i := 0; i < b.N; i++ {
+			sp.Split(&s, &u, &v)
+		}
+	})
+}
 
+func TestExpandMsgXmd(t *testing.T) {
+	// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-K.1, and lengths which are not
+	// a multiple of the hash size (computed with an independent implementation of the RFC)
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA256-128")
+	vectors := []struct {
+		msg        string
+		lenInBytes int
+		expected   string
+	}{
+		{"", 0x20, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+		{"abc", 0x20, "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+		{"abc", 48, "2b877f5f0dfd881405426c6b87b39205ef53a548b0e4d567fc007cb37c6fa1f3b19f42871efefca518ac950c27ac4e28"},
+		{"abcdef0123456789", 20, "a28590e9884ff8a769faf97c3407c54d4f1ef0be"},
+	}
+	for _, v := range vectors {
+		res, err := ExpandMsgXmd([]byte(v.msg), dst, v.lenInBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(res) != v.expected {
+			t.Fatalf("ExpandMsgXmd(%q, %d) mismatch", v.msg, v.lenInBytes)
+		}
+	}
 }
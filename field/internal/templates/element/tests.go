@@ -45,6 +45,18 @@ func Benchmark{{toTitle .ElementName}}Inverse(b *testing.B) {
 }
 
 
+func Benchmark{{toTitle .ElementName}}BatchInvert(b *testing.B) {
+	const n = 1024
+	a := make([]{{.ElementName}}, n)
+	for i := 0; i < n; i++ {
+		a[i].SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchInvert(a)
+	}
+}
+
 func Benchmark{{toTitle .ElementName}}Exp(b *testing.B) {
 	var x {{.ElementName}}
 	x.SetRandom()
@@ -385,6 +397,41 @@ func Test{{toTitle .ElementName}}InverseExp(t *testing.T) {
 	}
 }
 
+func Test{{toTitle .ElementName}}BatchInvert(t *testing.T) {
+	const n = 33
+	a := make([]{{.ElementName}}, n)
+	for i := 0; i < n; i++ {
+		if i%7 != 0 {
+			a[i].SetRandom()
+		}
+	}
+
+	check := func(res []{{.ElementName}}) {
+		if len(res) != n {
+			t.Fatal("wrong size")
+		}
+		for i := 0; i < n; i++ {
+			var expected {{.ElementName}}
+			expected.Inverse(&a[i])
+			if a[i].IsZero() {
+				expected.SetZero()
+			}
+			if !res[i].Equal(&expected) {
+				t.Fatal("BatchInvert doesn't match Inverse")
+			}
+		}
+	}
+
+	check(BatchInvert(a))
+	for _, nbTasks := range []int{0, 1, 4, n, 2 * n} {
+		check(BatchInvertParallel(a, nbTasks))
+	}
+
+	if len(BatchInvert(nil)) != 0 {
+		t.Fatal("BatchInvert of an empty slice should be empty")
+	}
+}
+
 func Test{{toTitle .ElementName}}MulByConstants(t *testing.T) {
 
 	parameters := gopter.DefaultTestParameters()
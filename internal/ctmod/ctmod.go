@@ -0,0 +1,130 @@
+// Package ctmod implements modular arithmetic on fixed width integers, in constant time with
+// respect to their values, for the secret scalars of the signature schemes.
+//
+// The integers are little endian slices of 64-bit words. Their lengths are public: the running
+// time of the operations depends on them, and not on the values of the words.
+package ctmod
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Modulus is a public modulus m, on Words() 64-bit words
+type Modulus struct {
+	m []uint64
+}
+
+// NewModulus returns the modulus m, which must be positive
+func NewModulus(m *big.Int) *Modulus {
+	if m.Sign() <= 0 {
+		panic("ctmod: the modulus must be positive")
+	}
+	n := (m.BitLen() + 63) / 64
+	buf := make([]byte, 8*n)
+	m.FillBytes(buf)
+	res := &Modulus{m: make([]uint64, n)}
+	setBytes(res.m, buf)
+	return res
+}
+
+// Words returns the number of 64-bit words of the integers modulo m
+func (m *Modulus) Words() int {
+	return len(m.m)
+}
+
+// SetBytes sets z to b mod m and returns it. b is a big endian integer of any length,
+// all its bits are processed. z must have Words() words.
+func (m *Modulus) SetBytes(z []uint64, b []byte) []uint64 {
+	t := make([]uint64, len(m.m))
+	for i := range z {
+		z[i] = 0
+	}
+	for _, v := range b {
+		for j := 7; j >= 0; j-- {
+			// z = 2z + bit mod m
+			hi := z[len(z)-1] >> 63
+			m.shiftIn(z, uint64(v>>uint(j))&1)
+			m.reduceOnce(z, t, hi)
+		}
+	}
+	return z
+}
+
+// MulAdd sets z to x*y + a mod m and returns it. y and a must be smaller than m,
+// x, y, a and z must have Words() words.
+func (m *Modulus) MulAdd(z, x, y, a []uint64) []uint64 {
+	t := make([]uint64, len(m.m))
+	acc := make([]uint64, len(m.m))
+	ya := make([]uint64, len(m.m))
+
+	// double and add, over all the bits of x
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			hi := acc[len(acc)-1] >> 63
+			m.shiftIn(acc, 0)
+			m.reduceOnce(acc, t, hi)
+
+			mask := -((x[i] >> uint(j)) & 1)
+			for k := range ya {
+				ya[k] = y[k] & mask
+			}
+			m.add(acc, ya, t)
+		}
+	}
+	m.add(acc, a, t)
+
+	copy(z, acc)
+	return z
+}
+
+// FillBytes sets b to the big endian encoding of x, on len(b) bytes, and returns it.
+// The bytes of x above len(b) must be zero.
+func FillBytes(b []byte, x []uint64) []byte {
+	for i := range b {
+		b[len(b)-1-i] = 0
+		if i < 8*len(x) {
+			b[len(b)-1-i] = byte(x[i/8] >> (8 * uint(i%8)))
+		}
+	}
+	return b
+}
+
+// shiftIn sets z to 2z + bit, dropping the most significant bit of z
+func (m *Modulus) shiftIn(z []uint64, bit uint64) {
+	for i := range z {
+		z[i], bit = z[i]<<1|bit, z[i]>>63
+	}
+}
+
+// add sets z to z + x mod m, with z, x < m. t is a scratch buffer of Words() words.
+func (m *Modulus) add(z, x, t []uint64) {
+	var c uint64
+	for i := range z {
+		z[i], c = bits.Add64(z[i], x[i], c)
+	}
+	m.reduceOnce(z, t, c)
+}
+
+// reduceOnce sets z to z - m if hi*2^(64*Words()) + z >= m, with hi*2^(64*Words()) + z < 2m.
+// t is a scratch buffer of Words() words.
+func (m *Modulus) reduceOnce(z, t []uint64, hi uint64) {
+	var b uint64
+	for i := range z {
+		t[i], b = bits.Sub64(z[i], m.m[i], b)
+	}
+	mask := -(hi | (b ^ 1))
+	for i := range z {
+		z[i] ^= mask & (z[i] ^ t[i])
+	}
+}
+
+// setBytes sets z to the big endian integer b, which must fit in len(z) words
+func setBytes(z []uint64, b []byte) {
+	for i := range z {
+		z[i] = 0
+	}
+	for i := range b {
+		z[i/8] |= uint64(b[len(b)-1-i]) << (8 * uint(i%8))
+	}
+}
@@ -0,0 +1,91 @@
+package ctmod
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestModulus(t *testing.T) {
+	moduli := []string{
+		// the order of the subgroup of the twisted Edwards curve on bn254
+		"2736030358979909402780800718157159386076813972158567259200215660948447373041",
+		// a modulus with the most significant bit of its word set
+		"115792089237316195423570985008687907853269984665640564039457584007908834671663",
+		"3",
+	}
+	for _, s := range moduli {
+		var mod big.Int
+		mod.SetString(s, 10)
+		m := NewModulus(&mod)
+		n := m.Words()
+
+		for i := 0; i < 50; i++ {
+			// x of any length is reduced
+			b := make([]byte, 8*n+i)
+			if _, err := rand.Read(b); err != nil {
+				t.Fatal(err)
+			}
+			var expected big.Int
+			expected.SetBytes(b).Mod(&expected, &mod)
+			x := m.SetBytes(make([]uint64, n), b)
+			if got := toBigInt(x); got.Cmp(&expected) != 0 {
+				t.Fatalf("SetBytes: expected %s, got %s", expected.String(), got.String())
+			}
+
+			y := randomReduced(t, m, &mod)
+			a := randomReduced(t, m, &mod)
+			var by, ba big.Int
+			by.Set(toBigInt(y))
+			ba.Set(toBigInt(a))
+			expected.Mul(&expected, &by).Add(&expected, &ba).Mod(&expected, &mod)
+			z := m.MulAdd(make([]uint64, n), x, y, a)
+			if got := toBigInt(z); got.Cmp(&expected) != 0 {
+				t.Fatalf("MulAdd: expected %s, got %s", expected.String(), got.String())
+			}
+
+			buf := make([]byte, 8*n)
+			if got := new(big.Int).SetBytes(FillBytes(buf, z)); got.Cmp(&expected) != 0 {
+				t.Fatalf("FillBytes: expected %s, got %s", expected.String(), got.String())
+			}
+		}
+
+		// m - 1 and 0
+		var mMinusOne big.Int
+		mMinusOne.Sub(&mod, big.NewInt(1))
+		buf := make([]byte, 8*n)
+		x := m.SetBytes(make([]uint64, n), mMinusOne.FillBytes(buf))
+		z := m.MulAdd(make([]uint64, n), x, x, x)
+		// (m-1)^2 + m - 1 = m(m-1) = 0 mod m
+		if got := toBigInt(z); got.Sign() != 0 {
+			t.Fatalf("MulAdd: expected 0, got %s", got.String())
+		}
+	}
+}
+
+func randomReduced(t *testing.T, m *Modulus, mod *big.Int) []uint64 {
+	v, err := rand.Int(rand.Reader, mod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 8*m.Words())
+	return m.SetBytes(make([]uint64, m.Words()), v.FillBytes(buf))
+}
+
+func toBigInt(x []uint64) *big.Int {
+	buf := make([]byte, 8*len(x))
+	return new(big.Int).SetBytes(FillBytes(buf, x))
+}
+
+func BenchmarkMulAdd(b *testing.B) {
+	var mod big.Int
+	mod.SetString("2736030358979909402780800718157159386076813972158567259200215660948447373041", 10)
+	m := NewModulus(&mod)
+	n := m.Words()
+	x := m.SetBytes(make([]uint64, n), []byte("a 64 bytes hash output, reduced modulo the order of the subgroup"))
+	z := make([]uint64, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.MulAdd(z, x, x, x)
+	}
+}
@@ -13,6 +13,7 @@ func init() {
 			GLV:              true,
 			CofactorCleaning: true,
 			CRange:           defaultCRange(),
+			MultiExpGLV:      [2]int{1 << 11, 1 << 17},
 		},
 		G2: Point{
 			CoordType:        "fptower.E2",
@@ -20,6 +21,7 @@ func init() {
 			GLV:              true,
 			CofactorCleaning: true,
 			CRange:           defaultCRange(),
+			MultiExpGLV:      [2]int{0, 1 << 16},
 		},
 	})
 
@@ -20,6 +20,7 @@ func init() {
 			GLV:              true,
 			CofactorCleaning: true,
 			CRange:           defaultCRange(),
+			MultiExpGLV:      [2]int{0, 1 << 16},
 		},
 	})
 }
@@ -13,6 +13,7 @@ func init() {
 			GLV:              true,
 			CofactorCleaning: true,
 			CRange:           []int{4, 5, 8, 16},
+			MultiExpGLV:      [2]int{1 << 13, 1 << 16},
 		},
 		G2: Point{
 			CoordType:        "fp.Element",
@@ -20,6 +21,7 @@ func init() {
 			GLV:              true,
 			CofactorCleaning: true,
 			CRange:           []int{4, 5, 8, 16},
+			MultiExpGLV:      [2]int{1 << 13, 1 << 16},
 		},
 	})
 
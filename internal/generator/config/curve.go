@@ -38,9 +38,10 @@ func (c *Curve) ID() ecc.ID {
 type Point struct {
 	CoordType        string
 	PointName        string
-	GLV              bool  // scalar mulitplication using GLV
-	CofactorCleaning bool  // flag telling if the Cofactor cleaning is available
-	CRange           []int // multiexp bucket method: generate inner methods (with const arrays) for each c
+	GLV              bool   // scalar mulitplication using GLV
+	CofactorCleaning bool   // flag telling if the Cofactor cleaning is available
+	CRange           []int  // multiexp bucket method: generate inner methods (with const arrays) for each c
+	MultiExpGLV      [2]int // MultiExp splits the scalars with GLV for a number of points in [MultiExpGLV[0], MultiExpGLV[1]) (see BenchmarkMultiExpGLV)
 }
 
 var Curves []Curve
@@ -0,0 +1,50 @@
+package config
+
+import "math/big"
+
+// TwistedEdwardsCurve describes a twisted Edwards curve defined over the scalar field of a Curve
+type TwistedEdwardsCurve struct {
+	Curve
+	EdwardsPackage string // name of the package of the twisted Edwards curve
+	SignatureID    string // the eddsa signature scheme on the curve is signature.EDDSA_<SignatureID>
+	GLV            bool   // scalar multiplication using GLV
+	Elligator2Z    string // Z parameter of the Elligator 2 map, in Fr
+}
+
+// TwistedEdwardsCurves returns the twisted Edwards companion curves of c
+func TwistedEdwardsCurves(c Curve) []TwistedEdwardsCurve {
+	z := elligator2Z(c.FrModulus)
+	res := []TwistedEdwardsCurve{{
+		Curve:          c,
+		EdwardsPackage: "twistededwards",
+		SignatureID:    c.EnumID,
+		Elligator2Z:    z,
+	}}
+	if c.Name == "bls12-381" {
+		res = append(res, TwistedEdwardsCurve{
+			Curve:          c,
+			EdwardsPackage: "bandersnatch",
+			SignatureID:    "BLS12_381_BANDERSNATCH",
+			GLV:            true,
+			Elligator2Z:    z,
+		})
+	}
+	return res
+}
+
+// elligator2Z returns the non-square of smallest absolute value modulo r, the positive one first
+// cf https://www.rfc-editor.org/rfc/rfc9380.html#appendix-H.3
+func elligator2Z(modulus string) string {
+	var r, z big.Int
+	r.SetString(modulus, 10)
+	for ctr := int64(1); ; ctr++ {
+		z.SetInt64(ctr)
+		if big.Jacobi(&z, &r) == -1 {
+			return z.String()
+		}
+		z.Sub(&r, &z)
+		if big.Jacobi(&z, &r) == -1 {
+			return z.String()
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package poseidon
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	entriesF := []bavard.EntryF{
+		{File: filepath.Join(baseDir, "poseidon.go"), TemplateF: []string{"poseidon.go.tmpl"}, PackageDoc: "provides the Poseidon hash function and permutation, cf https://eprint.iacr.org/2019/458.pdf"},
+		{File: filepath.Join(baseDir, "parameters.go"), TemplateF: []string{"parameters.go.tmpl"}},
+		{File: filepath.Join(baseDir, "poseidon_test.go"), TemplateF: []string{"tests/poseidon.go.tmpl"}},
+	}
+	return bgen.GenerateF(conf, "poseidon", "./crypto/hash/poseidon/template", entriesF...)
+
+}
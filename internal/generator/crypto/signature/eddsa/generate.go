@@ -7,7 +7,7 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 )
 
-func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+func Generate(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.BatchGenerator) error {
 
 	// eddsa
 	entriesF := []bavard.EntryF{
@@ -18,6 +18,7 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	entriesF := []bavard.EntryF{
 		{File: filepath.Join(baseDir, "multiexp.go"), TemplateF: []string{"multiexp.go.tmpl"}},
 		{File: filepath.Join(baseDir, "multiexp_test.go"), TemplateF: []string{"tests/multiexp.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_precomputed.go"), TemplateF: []string{"multiexp_precomputed.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), TemplateF: []string{"marshal.go.tmpl"}, PackageDoc: doc},
 		{File: filepath.Join(baseDir, "marshal_test.go"), TemplateF: []string{"tests/marshal.go.tmpl"}},
 	}
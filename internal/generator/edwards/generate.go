@@ -7,9 +7,15 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 )
 
-func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
-	return bgen.GenerateF(conf, "twistededwards", "./edwards/template", bavard.EntryF{
-		File: filepath.Join(baseDir, "point.go"), TemplateF: []string{"pointtwistededwards.go.tmpl"},
-	})
+func Generate(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.BatchGenerator) error {
+	entries := []bavard.EntryF{
+		{File: filepath.Join(baseDir, "point.go"), TemplateF: []string{"pointtwistededwards.go.tmpl"}},
+		{File: filepath.Join(baseDir, "point_test.go"), TemplateF: []string{"tests/point.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp.go"), TemplateF: []string{"multiexp.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_test.go"), TemplateF: []string{"tests/multiexp.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash_to_curve.go"), TemplateF: []string{"hash_to_curve.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash_to_curve_test.go"), TemplateF: []string{"tests/hash_to_curve.go.tmpl"}},
+	}
+	return bgen.GenerateF(conf, conf.EdwardsPackage, "./edwards/template", entries...)
 
 }
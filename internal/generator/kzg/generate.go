@@ -0,0 +1,17 @@
+package kzg
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	entries := []bavard.EntryF{
+		{File: filepath.Join(baseDir, "kzg.go"), TemplateF: []string{"kzg.go.tmpl"}, PackageDoc: "provides a KZG commitment scheme."},
+		{File: filepath.Join(baseDir, "marshal.go"), TemplateF: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "kzg_test.go"), TemplateF: []string{"tests/kzg.go.tmpl"}},
+	}
+	return bgen.GenerateF(conf, "kzg", "./kzg/template/", entries...)
+}
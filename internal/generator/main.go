@@ -12,11 +12,14 @@ import (
 	"github.com/consensys/gnark-crypto/field/generator"
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/mimc"
+	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/poseidon"
 	"github.com/consensys/gnark-crypto/internal/generator/crypto/signature/eddsa"
 	"github.com/consensys/gnark-crypto/internal/generator/ecc"
 	"github.com/consensys/gnark-crypto/internal/generator/edwards"
 	"github.com/consensys/gnark-crypto/internal/generator/fft"
+	"github.com/consensys/gnark-crypto/internal/generator/kzg"
 	"github.com/consensys/gnark-crypto/internal/generator/pairing"
+	"github.com/consensys/gnark-crypto/internal/generator/pedersen"
 	"github.com/consensys/gnark-crypto/internal/generator/polynomial"
 	"github.com/consensys/gnark-crypto/internal/generator/tower"
 )
@@ -57,7 +60,9 @@ func main() {
 			assertNoError(pairing.Generate(conf, curveDir, bgen))
 
 			// generate twisted edwards companion curves
-			assertNoError(edwards.Generate(conf, filepath.Join(curveDir, "twistededwards"), bgen))
+			for _, tConf := range config.TwistedEdwardsCurves(conf) {
+				assertNoError(edwards.Generate(tConf, filepath.Join(curveDir, tConf.EdwardsPackage), bgen))
+			}
 
 			// generate fft on fr
 			assertNoError(fft.Generate(conf, filepath.Join(curveDir, "fr", "fft"), bgen))
@@ -65,11 +70,27 @@ func main() {
 			// generate polynomial on fr
 			assertNoError(polynomial.Generate(conf, filepath.Join(curveDir, "fr", "polynomial"), bgen))
 
+			// generate kzg on fr
+			assertNoError(kzg.Generate(conf, filepath.Join(curveDir, "fr", "kzg"), bgen))
+
+			// generate pedersen on G1
+			assertNoError(pedersen.Generate(conf, filepath.Join(curveDir, "fr", "pedersen"), bgen))
+
 			// generate mimc on fr
 			assertNoError(mimc.Generate(conf, filepath.Join(curveDir, "fr", "mimc"), bgen))
 
+			// generate poseidon on fr
+			assertNoError(poseidon.Generate(conf, filepath.Join(curveDir, "fr", "poseidon"), bgen))
+
 			// generate eddsa on companion curves
-			assertNoError(eddsa.Generate(conf, filepath.Join(curveDir, "twistededwards", "eddsa"), bgen))
+			for _, tConf := range config.TwistedEdwardsCurves(conf) {
+				assertNoError(eddsa.Generate(tConf, filepath.Join(curveDir, tConf.EdwardsPackage, "eddsa"), bgen))
+			}
+
+			// generate pedersen on companion curves
+			for _, tConf := range config.TwistedEdwardsCurves(conf) {
+				assertNoError(pedersen.GenerateEdwards(tConf, filepath.Join(curveDir, tConf.EdwardsPackage, "pedersen"), bgen))
+			}
 
 		}(conf)
 
@@ -0,0 +1,28 @@
+package pedersen
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+// Generate generates the pedersen vector commitment on G1
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	entries := []bavard.EntryF{
+		{File: filepath.Join(baseDir, "pedersen.go"), TemplateF: []string{"pedersen.go.tmpl"}, PackageDoc: "provides a Pedersen vector commitment scheme on G1."},
+		{File: filepath.Join(baseDir, "marshal.go"), TemplateF: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "pedersen_test.go"), TemplateF: []string{"tests/pedersen.go.tmpl"}},
+	}
+	return bgen.GenerateF(conf, "pedersen", "./pedersen/template/", entries...)
+}
+
+// GenerateEdwards generates the pedersen vector commitment on a twisted Edwards companion curve
+func GenerateEdwards(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.BatchGenerator) error {
+	entries := []bavard.EntryF{
+		{File: filepath.Join(baseDir, "pedersen.go"), TemplateF: []string{"pedersen.go.tmpl"}, PackageDoc: "provides a Pedersen vector commitment scheme on the twisted Edwards curve."},
+		{File: filepath.Join(baseDir, "marshal.go"), TemplateF: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "pedersen_test.go"), TemplateF: []string{"tests/pedersen.go.tmpl"}},
+	}
+	return bgen.GenerateF(conf, "pedersen", "./pedersen/template/edwards/", entries...)
+}
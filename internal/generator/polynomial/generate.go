@@ -10,6 +10,9 @@ import (
 func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
 	entries := []bavard.EntryF{
 		{File: filepath.Join(baseDir, "polynomial.go"), TemplateF: []string{"polynomial.go.tmpl"}},
+		{File: filepath.Join(baseDir, "interpolation.go"), TemplateF: []string{"interpolation.go.tmpl"}},
+		{File: filepath.Join(baseDir, "interpolation_test.go"), TemplateF: []string{"tests/interpolation.go.tmpl"}},
+		{File: filepath.Join(baseDir, "polynomial_test.go"), TemplateF: []string{"tests/polynomial.go.tmpl"}},
 	}
 	return bgen.GenerateF(conf, "polynomial", "./polynomial/template/", entries...)
 }